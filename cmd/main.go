@@ -1,13 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/euro1061/gohex/internal/adapters/currency/cached"
+	"github.com/euro1061/gohex/internal/adapters/currency/static"
+	"github.com/euro1061/gohex/internal/adapters/payment/fake"
 	"github.com/euro1061/gohex/internal/adapters/repository/postgres"
+	"github.com/euro1061/gohex/internal/adapters/storage/local"
+	"github.com/euro1061/gohex/internal/adapters/storage/s3"
 	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/euro1061/gohex/internal/ports/currency"
 	"github.com/euro1061/gohex/internal/ports/http"
+	"github.com/euro1061/gohex/internal/ports/payment"
+	"github.com/euro1061/gohex/internal/ports/storage"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/swagger"
@@ -39,50 +54,183 @@ func main() {
 		log.Fatal("Error loading .env file")
 	}
 
+	// "migrate up|down|status|create" manages the schema instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Initialize database connection
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-	)
+	db, err := initDB()
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	db, err := gorm.Open(postgresDB.Open(dsn), &gorm.Config{})
+	// Refuse to serve on an out of date schema. MIGRATE_ON_START=true applies
+	// pending migrations first; replicas starting together take turns.
+	migrator, err := postgres.NewMigrator(db)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if os.Getenv("MIGRATE_ON_START") == "true" {
+		applied, err := migrator.Up(context.Background())
+		for _, migration := range applied {
+			log.Printf("Applied migration %s", migration)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		log.Fatalf("%v; run \"%s migrate up\" first", err, os.Args[0])
+	}
 
 	// Initialize repositories
 	productRepo := postgres.NewProductRepository(db)
 	userRepo := postgres.NewUserRepository(db)
+	categoryRepo := postgres.NewCategoryRepository(db)
+	inventoryRepo := postgres.NewInventoryRepository(db)
+	imageRepo := postgres.NewProductImageRepository(db)
+	priceRepo := postgres.NewPriceRepository(db)
+	reviewRepo := postgres.NewReviewRepository(db)
+	promotionRepo := postgres.NewPromotionRepository(db)
+	cartRepo := postgres.NewCartRepository(db)
+	orderRepo := postgres.NewOrderRepository(db)
+	paymentRepo := postgres.NewPaymentRepository(db)
+	taxRepo := postgres.NewTaxRateRepository(db)
+	wishlistRepo := postgres.NewWishlistRepository(db)
+	unitOfWork := postgres.NewUnitOfWork(db)
+
+	// Initialize blob storage
+	blobStore, err := newBlobStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Initialize payment gateway
+	paymentGateway, err := newPaymentGateway()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Initialize exchange rates
+	exchangeRates, err := newExchangeRates()
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Initialize services
-	productService := application.NewProductService(productRepo)
+	productService := application.NewProductService(productRepo, categoryRepo, imageRepo, priceRepo, reviewRepo, blobStore, unitOfWork)
 	userService := application.NewUserService(userRepo)
+	categoryService := application.NewCategoryService(categoryRepo, productRepo)
+	inventoryService := application.NewInventoryService(inventoryRepo, productRepo)
+	imageService := application.NewProductImageService(imageRepo, productRepo, blobStore, envInt64("IMAGE_MAX_BYTES"))
+	importService := application.NewProductImportService(productService, envInt64("IMPORT_MAX_BYTES"))
+	priceScheduleService := application.NewPriceScheduleService(priceRepo, productService)
+	reviewService := application.NewReviewService(reviewRepo, productRepo)
+	promotionService := application.NewPromotionService(promotionRepo, productRepo, categoryRepo)
+	taxService := application.NewTaxService(taxRepo, application.TaxSettings{
+		PricesIncludeTax: os.Getenv("TAX_PRICES_INCLUDE_TAX") == "true",
+		Rounding:         domain.TaxRounding(os.Getenv("TAX_ROUNDING")),
+		DefaultCountry:   os.Getenv("TAX_DEFAULT_COUNTRY"),
+	})
+	currencyService := application.NewCurrencyService(exchangeRates, application.CurrencySettings{
+		Base:       os.Getenv("CURRENCY_BASE"),
+		Rounding:   domain.RoundingMode(os.Getenv("CURRENCY_ROUNDING")),
+		Increments: envIncrements("CURRENCY_ROUNDING_INCREMENTS"),
+	})
+	pricingService := application.NewPricingService(promotionRepo, productRepo, categoryRepo, taxService, currencyService)
+	cartService := application.NewCartService(cartRepo, productRepo, pricingService)
+	orderService := application.NewOrderService(orderRepo, cartService, inventoryService, pricingService, unitOfWork)
+	wishlistService := application.NewWishlistService(wishlistRepo, productService)
+	paymentService := application.NewPaymentService(paymentRepo, paymentGateway, orderService, currencyService.Base())
 
 	// Initialize HTTP handlers
-	productHandler := http.NewProductHandler(productService)
-	userHandler := http.NewUserHandler(userService)
+	productHandler := http.NewProductHandler(productService, currencyService, wishlistService)
+	userHandler := http.NewUserHandler(userService, cartService)
+	categoryHandler := http.NewCategoryHandler(categoryService)
+	inventoryHandler := http.NewInventoryHandler(inventoryService)
+	imageHandler := http.NewProductImageHandler(imageService)
+	importHandler := http.NewProductImportHandler(importService)
+	priceHandler := http.NewPriceHandler(productService, priceScheduleService)
+	revisionHandler := http.NewProductRevisionHandler(productService)
+	reviewHandler := http.NewReviewHandler(reviewService)
+	promotionHandler := http.NewPromotionHandler(promotionService)
+	pricingHandler := http.NewPricingHandler(pricingService)
+	cartHandler := http.NewCartHandler(cartService)
+	orderHandler := http.NewOrderHandler(orderService)
+	paymentHandler := http.NewPaymentHandler(paymentService)
+	taxHandler := http.NewTaxHandler(taxService)
+	wishlistHandler := http.NewWishlistHandler(wishlistService)
 
 	// Setup Fiber app
-	app := fiber.New()
+	// The body limit fits the largest upload; handlers enforce their own
+	// limits. Image uploads get room for multipart overhead.
+	app := fiber.New(fiber.Config{
+		BodyLimit: int(max(imageService.MaxSize()+1<<20, importService.MaxSize())),
+		// Errors are problem details unless ERROR_FORMAT=envelope keeps the
+		// response body of earlier versions
+		ErrorHandler: http.NewErrorHandler(http.ErrorConfig{
+			Format:   http.ErrorFormat(os.Getenv("ERROR_FORMAT")),
+			TypeBase: os.Getenv("PROBLEM_TYPE_BASE"),
+		}),
+	})
 
 	// CORS middleware with more secure configuration
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:3000", // เปลี่ยนเป็น domain ของ frontend จริงๆ
-		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
+		AllowMethods:     "GET,POST,PUT,PATCH,DELETE,OPTIONS",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization," + http.HeaderChangeReason,
 		AllowCredentials: true,
 		MaxAge:           300, // 5 minutes
 	}))
 
+	// Cancel the work of requests that run too long. Imports and image
+	// uploads get more time; see requestTimeouts.
+	app.Use(middleware.Timeout(middleware.TimeoutConfig{
+		Default: envDuration("REQUEST_TIMEOUT", 30*time.Second),
+		Routes:  requestTimeouts(),
+	}))
+
+	// Resolve the signed in user for handlers that record who made a change
+	app.Use(middleware.CurrentUser(userService))
+
 	// Swagger
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
+	// Serve uploaded media when it is stored on the local filesystem
+	if os.Getenv("BLOB_STORE") != "s3" {
+		app.Static("/media", mediaDir())
+	}
+
 	// Register routes
 	productHandler.RegisterRoutes(app)
 	userHandler.RegisterRoutes(app)
+	categoryHandler.RegisterRoutes(app)
+	inventoryHandler.RegisterRoutes(app)
+	imageHandler.RegisterRoutes(app)
+	importHandler.RegisterRoutes(app)
+	priceHandler.RegisterRoutes(app)
+	revisionHandler.RegisterRoutes(app)
+	reviewHandler.RegisterRoutes(app)
+	promotionHandler.RegisterRoutes(app)
+	pricingHandler.RegisterRoutes(app)
+	cartHandler.RegisterRoutes(app)
+	orderHandler.RegisterRoutes(app)
+	paymentHandler.RegisterRoutes(app)
+	taxHandler.RegisterRoutes(app)
+	wishlistHandler.RegisterRoutes(app)
+
+	// Apply scheduled price changes in the background
+	priceScheduleService.StartScheduler(time.Minute)
+
+	// Delete abandoned guest carts in the background
+	cartService.StartPruner(time.Hour, http.GuestCartLifetime)
+
+	// Catch up on payments whose webhooks were missed
+	paymentService.StartReconciler(5*time.Minute, 15*time.Minute)
 
 	// Get port from environment variable or use default
 	port := os.Getenv("PORT")
@@ -95,6 +243,137 @@ func main() {
 	log.Fatal(app.Listen(":" + port))
 }
 
+// newBlobStore creates the blob store selected by BLOB_STORE: "s3" for an
+// S3-compatible bucket, anything else for the local filesystem.
+func newBlobStore() (storage.BlobStore, error) {
+	if os.Getenv("BLOB_STORE") == "s3" {
+		return s3.NewBlobStore(s3.Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		}, nil)
+	}
+
+	baseURL := os.Getenv("MEDIA_BASE_URL")
+	if baseURL == "" {
+		baseURL = "/media"
+	}
+	return local.NewBlobStore(mediaDir(), baseURL)
+}
+
+// newPaymentGateway creates the payment gateway selected by PAYMENT_GATEWAY.
+// Only "fake" is available: the in-process provider for development, which
+// loses its payments on restart. PAYMENT_WEBHOOK_SECRET must be set, and
+// PAYMENT_AUTO_AUTHORIZE=true makes it accept every payment at once.
+func newPaymentGateway() (payment.PaymentGateway, error) {
+	switch name := os.Getenv("PAYMENT_GATEWAY"); name {
+	case "fake":
+		gateway, err := fake.NewGateway(fake.Config{
+			Secret:        os.Getenv("PAYMENT_WEBHOOK_SECRET"),
+			AutoAuthorize: os.Getenv("PAYMENT_AUTO_AUTHORIZE") == "true",
+		})
+		if err != nil {
+			return nil, err
+		}
+		return gateway, nil
+	case "":
+		return nil, errors.New("no payment gateway configured; set PAYMENT_GATEWAY=fake to use the in-process gateway for development")
+	default:
+		return nil, fmt.Errorf("unknown payment gateway %q", name)
+	}
+}
+
+// newExchangeRates reads exchange rates from the JSON file at
+// EXCHANGE_RATES_FILE, ./exchange_rates.json by default, and caches them for
+// EXCHANGE_RATES_TTL, ten minutes by default.
+func newExchangeRates() (currency.ExchangeRateProvider, error) {
+	path := os.Getenv("EXCHANGE_RATES_FILE")
+	if path == "" {
+		path = "./exchange_rates.json"
+	}
+	source, err := static.NewProvider(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, err := time.ParseDuration(os.Getenv("EXCHANGE_RATES_TTL"))
+	if err != nil || ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	return cached.NewProvider(source, ttl), nil
+}
+
+func mediaDir() string {
+	if dir := os.Getenv("MEDIA_DIR"); dir != "" {
+		return dir
+	}
+	return "./media"
+}
+
+// envInt64 reads an integer environment variable, returning zero when it is
+// unset or invalid so the service default applies.
+func envInt64(name string) int64 {
+	value, err := strconv.ParseInt(os.Getenv(name), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// envIncrements reads rounding increments per currency from an environment
+// variable such as "THB=1,JPY=1". Invalid entries are skipped.
+func envIncrements(name string) map[string]float64 {
+	increments := make(map[string]float64)
+	for _, entry := range strings.Split(os.Getenv(name), ",") {
+		code, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		increment, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || increment <= 0 {
+			continue
+		}
+		increments[strings.TrimSpace(code)] = increment
+	}
+	return increments
+}
+
+// envDuration reads a duration environment variable such as "45s",
+// returning fallback when it is unset or invalid.
+func envDuration(name string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+// requestTimeouts returns the per route timeouts: built-in ones for slow
+// routes, overridden or extended by REQUEST_TIMEOUT_ROUTES, e.g.
+// "POST /products/import=10m,GET /orders=5s". A zero duration disables the
+// timeout of a route. Invalid entries are skipped.
+func requestTimeouts() map[string]time.Duration {
+	timeouts := map[string]time.Duration{
+		"POST /products/import":     5 * time.Minute,
+		"POST /products/:id/images": 2 * time.Minute,
+	}
+	for _, entry := range strings.Split(os.Getenv("REQUEST_TIMEOUT_ROUTES"), ",") {
+		route, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || timeout < 0 {
+			continue
+		}
+		timeouts[strings.Join(strings.Fields(route), " ")] = timeout
+	}
+	return timeouts
+}
+
 func initDB() (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		os.Getenv("DB_HOST"),
@@ -104,5 +383,5 @@ func initDB() (*gorm.DB, error) {
 		os.Getenv("DB_NAME"),
 	)
 
-	return gorm.Open(postgresDB.Open(dsn), &gorm.Config{})
+	return gorm.Open(postgresDB.Open(dsn), &gorm.Config{TranslateError: true})
 }
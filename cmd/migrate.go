@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/euro1061/gohex/internal/adapters/repository/postgres"
+)
+
+// defaultMigrationsDir is where "migrate create" writes new migrations, seen
+// from the root of the repository.
+const defaultMigrationsDir = "internal/adapters/repository/postgres/migrations"
+
+const migrateUsage = `usage: %s migrate <command>
+
+commands:
+  up                  apply all pending migrations
+  down                undo the latest applied migration; 0002_initial_schema
+                      refuses, as undoing it would drop every table
+  status              list the migrations and whether they are applied
+  create [-dir DIR] NAME
+                      add empty up and down scripts for a new migration
+`
+
+// runMigrate runs the migrate subcommand with args, the arguments after
+// "migrate".
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, migrateUsage, os.Args[0])
+		return errors.New("missing migrate command")
+	}
+
+	if args[0] == "create" {
+		flags := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+		dir := flags.String("dir", defaultMigrationsDir, "directory of the migration scripts")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return fmt.Errorf("usage: %s migrate create [-dir DIR] NAME", os.Args[0])
+		}
+		up, down, err := postgres.CreateMigration(*dir, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created %s\ncreated %s\n", up, down)
+		return nil
+	}
+
+	db, err := initDB()
+	if err != nil {
+		return err
+	}
+	migrator, err := postgres.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %s\n", migration)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if reverted == nil {
+			fmt.Println("no migrations applied")
+			return nil
+		}
+		fmt.Printf("reverted %s\n", reverted)
+		return nil
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "MIGRATION\tSTATUS")
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			if status.Unknown {
+				state += " (not in this build)"
+			}
+			fmt.Fprintf(w, "%s\t%s\n", status.Migration, state)
+		}
+		return w.Flush()
+	default:
+		fmt.Fprintf(os.Stderr, migrateUsage, os.Args[0])
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}
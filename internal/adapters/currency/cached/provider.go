@@ -0,0 +1,58 @@
+package cached
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/euro1061/gohex/internal/ports/currency"
+)
+
+// Provider caches the rates of another provider for a fixed time, so a slow
+// or rate limited source is asked at most once per pair and period.
+type Provider struct {
+	source currency.ExchangeRateProvider
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	rates map[string]cachedRate
+}
+
+type cachedRate struct {
+	rate      float64
+	fetchedAt time.Time
+}
+
+func NewProvider(source currency.ExchangeRateProvider, ttl time.Duration) *Provider {
+	return &Provider{
+		source: source,
+		ttl:    ttl,
+		now:    time.Now,
+		rates:  make(map[string]cachedRate),
+	}
+}
+
+// Rate returns the cached rate while it is fresh and asks the source
+// otherwise. Failures are not cached.
+func (p *Provider) Rate(ctx context.Context, from, to string) (float64, error) {
+	key := strings.ToUpper(from) + "/" + strings.ToUpper(to)
+
+	p.mu.Lock()
+	cached, ok := p.rates[key]
+	p.mu.Unlock()
+	if ok && p.now().Sub(cached.fetchedAt) < p.ttl {
+		return cached.rate, nil
+	}
+
+	rate, err := p.source.Rate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	p.mu.Lock()
+	p.rates[key] = cachedRate{rate: rate, fetchedAt: p.now()}
+	p.mu.Unlock()
+	return rate, nil
+}
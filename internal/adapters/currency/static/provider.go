@@ -0,0 +1,94 @@
+package static
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/euro1061/gohex/internal/ports/currency"
+)
+
+// Provider reads exchange rates from a JSON file such as
+//
+//	{"base": "USD", "rates": {"THB": 35.5, "EUR": 0.92}}
+//
+// where every rate is the price of one unit of base. The file is read on
+// every call so edits apply at once; wrap the provider in a cached one to
+// avoid the reads.
+type Provider struct {
+	path string
+}
+
+type rateFile struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+func NewProvider(path string) (*Provider, error) {
+	provider := &Provider{path: path}
+	// Fail at startup rather than on the first conversion
+	if _, err := provider.load(); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+func (p *Provider) Rate(ctx context.Context, from, to string) (float64, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+	if from == to {
+		return 1, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	file, err := p.load()
+	if err != nil {
+		return 0, err
+	}
+	fromRate, ok := file.rate(from)
+	if !ok {
+		return 0, currency.ErrRateNotFound
+	}
+	toRate, ok := file.rate(to)
+	if !ok {
+		return 0, currency.ErrRateNotFound
+	}
+	return toRate / fromRate, nil
+}
+
+func (p *Provider) load() (*rateFile, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading exchange rates: %w", err)
+	}
+	var file rateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing exchange rates: %w", err)
+	}
+	if file.Base == "" {
+		return nil, fmt.Errorf("error parsing exchange rates: base currency is missing")
+	}
+	for code, rate := range file.Rates {
+		if rate <= 0 {
+			return nil, fmt.Errorf("error parsing exchange rates: rate of %s must be positive", code)
+		}
+	}
+	return &file, nil
+}
+
+// rate returns the price of one unit of base in code.
+func (f *rateFile) rate(code string) (float64, bool) {
+	if code == strings.ToUpper(f.Base) {
+		return 1, true
+	}
+	for name, rate := range f.Rates {
+		if strings.ToUpper(name) == code {
+			return rate, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,202 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/payment"
+)
+
+// Config configures a fake Gateway.
+type Config struct {
+	// Secret signs and verifies webhook payloads. It is required.
+	Secret string
+	// AutoAuthorize authorizes every intent as soon as it is created, as if
+	// the customer paid at once. Useful for local development.
+	AutoAuthorize bool
+}
+
+// Gateway is an in-process, deterministic stand-in for a payment provider,
+// for development and tests. Intents are kept in memory and lost on restart.
+// Intent and event IDs are sequential, and customer actions are simulated
+// with Authorize and Decline, which return the signed webhook the provider
+// would send.
+type Gateway struct {
+	config Config
+	now    func() time.Time
+
+	mu          sync.Mutex
+	intents     map[string]*payment.Intent
+	nextIntent  int
+	nextEventID int
+}
+
+func NewGateway(config Config) (*Gateway, error) {
+	// Without a secret anyone could sign a webhook
+	if config.Secret == "" {
+		return nil, errors.New("payment webhook secret is required")
+	}
+
+	return &Gateway{
+		config:      config,
+		now:         time.Now,
+		intents:     make(map[string]*payment.Intent),
+		nextIntent:  1,
+		nextEventID: 1,
+	}, nil
+}
+
+func (g *Gateway) CreateIntent(ctx context.Context, amount float64, currency, reference string) (*payment.Intent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("error creating payment intent: amount must be greater than 0")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := fmt.Sprintf("pi_fake_%06d", g.nextIntent)
+	g.nextIntent++
+	intent := &payment.Intent{
+		ID:           id,
+		Amount:       amount,
+		Currency:     currency,
+		Reference:    reference,
+		Status:       domain.PaymentPending,
+		ClientSecret: id + "_secret",
+		CreatedAt:    g.now(),
+	}
+	if g.config.AutoAuthorize {
+		intent.Status = domain.PaymentAuthorized
+	}
+	g.intents[id] = intent
+
+	clone := *intent
+	return &clone, nil
+}
+
+func (g *Gateway) GetIntent(ctx context.Context, id string) (*payment.Intent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	intent, exists := g.intents[id]
+	if !exists {
+		return nil, payment.ErrIntentNotFound
+	}
+	clone := *intent
+	return &clone, nil
+}
+
+func (g *Gateway) Capture(ctx context.Context, id string) (*payment.Intent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.move(id, domain.PaymentCaptured, domain.PaymentAuthorized)
+}
+
+func (g *Gateway) Refund(ctx context.Context, id string) (*payment.Intent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.move(id, domain.PaymentRefunded, domain.PaymentCaptured)
+}
+
+func (g *Gateway) Void(ctx context.Context, id string) (*payment.Intent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.move(id, domain.PaymentVoided, domain.PaymentPending, domain.PaymentAuthorized)
+}
+
+// Authorize simulates the customer paying for a pending intent and returns
+// the webhook payload and signature announcing it.
+func (g *Gateway) Authorize(id string) ([]byte, string, error) {
+	intent, err := g.move(id, domain.PaymentAuthorized, domain.PaymentPending)
+	if err != nil {
+		return nil, "", err
+	}
+	return g.event(intent)
+}
+
+// Decline simulates the customer's payment of a pending intent failing and
+// returns the webhook payload and signature announcing it.
+func (g *Gateway) Decline(id string) ([]byte, string, error) {
+	intent, err := g.move(id, domain.PaymentFailed, domain.PaymentPending)
+	if err != nil {
+		return nil, "", err
+	}
+	return g.event(intent)
+}
+
+// Webhook returns a signed webhook payload reporting the current state of an
+// intent, as the provider sends after every change.
+func (g *Gateway) Webhook(id string) ([]byte, string, error) {
+	intent, err := g.GetIntent(context.Background(), id)
+	if err != nil {
+		return nil, "", err
+	}
+	return g.event(intent)
+}
+
+func (g *Gateway) ParseEvent(payload []byte, signature string) (*payment.Event, error) {
+	if !verify(g.config.Secret, payload, signature, g.now()) {
+		return nil, payment.ErrInvalidSignature
+	}
+
+	var event payment.Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("error decoding webhook payload: %w", err)
+	}
+	return &event, nil
+}
+
+// move changes the status of an intent that is in one of from.
+func (g *Gateway) move(id string, to domain.PaymentStatus, from ...domain.PaymentStatus) (*payment.Intent, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	intent, exists := g.intents[id]
+	if !exists {
+		return nil, payment.ErrIntentNotFound
+	}
+	for _, status := range from {
+		if intent.Status == status {
+			intent.Status = to
+			clone := *intent
+			return &clone, nil
+		}
+	}
+	return nil, payment.ErrInvalidIntentState
+}
+
+// event builds the signed webhook for the current state of intent.
+func (g *Gateway) event(intent *payment.Intent) ([]byte, string, error) {
+	g.mu.Lock()
+	event := payment.Event{
+		ID:        fmt.Sprintf("evt_fake_%06d", g.nextEventID),
+		Type:      "payment_intent." + string(intent.Status),
+		Intent:    *intent,
+		CreatedAt: g.now(),
+	}
+	g.nextEventID++
+	g.mu.Unlock()
+
+	// Webhooks do not carry the client secret
+	event.Intent.ClientSecret = ""
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding webhook payload: %w", err)
+	}
+	return payload, Sign(g.config.Secret, payload, g.now()), nil
+}
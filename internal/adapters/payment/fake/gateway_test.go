@@ -0,0 +1,54 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/payment"
+)
+
+func TestNewGatewayRequiresSecret(t *testing.T) {
+	if _, err := NewGateway(Config{}); err == nil {
+		t.Fatal("NewGateway() without a secret succeeded")
+	}
+}
+
+func TestAuthorizeWebhook(t *testing.T) {
+	gateway, err := NewGateway(Config{Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	intent, err := gateway.CreateIntent(context.Background(), 25, "USD", "ORD-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, signature, err := gateway.Authorize(intent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event, err := gateway.ParseEvent(payload, signature)
+	if err != nil {
+		t.Fatalf("ParseEvent() error = %v", err)
+	}
+	if event.Intent.ID != intent.ID || event.Intent.Status != domain.PaymentAuthorized {
+		t.Errorf("event intent = %s %s, want %s authorized", event.Intent.ID, event.Intent.Status, intent.ID)
+	}
+	if event.Intent.ClientSecret != "" {
+		t.Error("webhook carries the client secret")
+	}
+
+	other, err := NewGateway(Config{Secret: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.ParseEvent(payload, signature); !errors.Is(err, payment.ErrInvalidSignature) {
+		t.Errorf("ParseEvent() with another secret error = %v, want ErrInvalidSignature", err)
+	}
+
+	if _, _, err := gateway.Authorize(intent.ID); !errors.Is(err, payment.ErrInvalidIntentState) {
+		t.Errorf("second Authorize() error = %v, want ErrInvalidIntentState", err)
+	}
+}
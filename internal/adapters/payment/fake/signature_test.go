@@ -0,0 +1,39 @@
+package fake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	payload := []byte(`{"id":"evt_fake_000001"}`)
+	header := Sign("secret", payload, now)
+
+	tests := []struct {
+		name    string
+		secret  string
+		payload []byte
+		header  string
+		now     time.Time
+		want    bool
+	}{
+		{"valid", "secret", payload, header, now, true},
+		{"within tolerance", "secret", payload, header, now.Add(signatureTolerance), true},
+		{"too old", "secret", payload, header, now.Add(signatureTolerance + time.Second), false},
+		{"from the future", "secret", payload, header, now.Add(-signatureTolerance - time.Second), false},
+		{"other secret", "other", payload, header, now, false},
+		{"changed payload", "secret", []byte(`{"id":"evt_fake_000002"}`), header, now, false},
+		{"missing signature", "secret", payload, "t=1700000000", now, false},
+		{"missing time", "secret", payload, "v1=" + signature("secret", "1700000000", payload), now, false},
+		{"empty header", "secret", payload, "", now, false},
+		{"empty secret", "", payload, Sign("", payload, now), now, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verify(tt.secret, tt.payload, tt.header, tt.now); got != tt.want {
+				t.Errorf("verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+type CartRepository struct {
+	guard
+	*cartData
+}
+
+type cartData struct {
+	carts      map[uint]*domain.Cart
+	nextID     uint
+	nextItemID uint
+}
+
+func NewCartRepository() *CartRepository {
+	return &CartRepository{
+		guard: newGuard(),
+		cartData: &cartData{
+			carts:      make(map[uint]*domain.Cart),
+			nextID:     1,
+			nextItemID: 1,
+		},
+	}
+}
+
+func (r *CartRepository) Create(ctx context.Context, cart *domain.Cart) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	for _, existing := range r.carts {
+		if (cart.UserID != nil && existing.UserID != nil && *existing.UserID == *cart.UserID) ||
+			(cart.Token != nil && existing.Token != nil && *existing.Token == *cart.Token) {
+			return repository.ErrDuplicateKey
+		}
+	}
+
+	now := time.Now()
+	cart.ID = r.nextID
+	cart.CreatedAt = now
+	cart.UpdatedAt = now
+	r.assignItemIDs(cart)
+	r.carts[cart.ID] = cloneCart(cart)
+	r.nextID++
+	return nil
+}
+
+func (r *CartRepository) GetByUser(ctx context.Context, userID uint) (*domain.Cart, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.find(func(cart *domain.Cart) bool {
+		return cart.UserID != nil && *cart.UserID == userID
+	}), nil
+}
+
+// LockByUser returns the cart of a user. Units of work on this package run
+// one at a time, so there is nothing to lock.
+func (r *CartRepository) LockByUser(ctx context.Context, userID uint) (*domain.Cart, error) {
+	return r.GetByUser(ctx, userID)
+}
+
+func (r *CartRepository) GetByToken(ctx context.Context, token string) (*domain.Cart, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.find(func(cart *domain.Cart) bool {
+		return cart.Token != nil && *cart.Token == token
+	}), nil
+}
+
+func (r *CartRepository) Save(ctx context.Context, cart *domain.Cart) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	existing, exists := r.carts[cart.ID]
+	if !exists {
+		return errors.New("cart not found")
+	}
+
+	stored := cloneCart(existing)
+	stored.UpdatedAt = time.Now()
+	stored.Items = cart.Items
+	r.assignItemIDs(stored)
+	cart.UpdatedAt = stored.UpdatedAt
+	r.carts[cart.ID] = cloneCart(stored)
+	return nil
+}
+
+func (r *CartRepository) Delete(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, exists := r.carts[id]; !exists {
+		return errors.New("cart not found")
+	}
+	delete(r.carts, id)
+	return nil
+}
+
+func (r *CartRepository) DeleteGuestCarts(ctx context.Context, before time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	var deleted int64
+	for id, cart := range r.carts {
+		if cart.UserID == nil && cart.UpdatedAt.Before(before) {
+			delete(r.carts, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *CartRepository) find(match func(cart *domain.Cart) bool) *domain.Cart {
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, cart := range r.carts {
+		if match(cart) {
+			return cloneCart(cart)
+		}
+	}
+	return nil
+}
+
+func (r *CartRepository) assignItemIDs(cart *domain.Cart) {
+	for i := range cart.Items {
+		cart.Items[i].CartID = cart.ID
+		if cart.Items[i].ID == 0 {
+			cart.Items[i].ID = r.nextItemID
+			r.nextItemID++
+		}
+	}
+}
+
+func (r *CartRepository) bind() interface{} {
+	return &CartRepository{guard: r.guard.bound(), cartData: r.cartData}
+}
+
+func (r *CartRepository) snapshot() func() {
+	r.RLock()
+	carts := cloneMap(r.carts, cloneCart)
+	nextID, nextItemID := r.nextID, r.nextItemID
+	r.RUnlock()
+
+	return func() {
+		r.Lock()
+		r.carts, r.nextID, r.nextItemID = carts, nextID, nextItemID
+		r.Unlock()
+	}
+}
+
+func cloneCart(cart *domain.Cart) *domain.Cart {
+	clone := *cart
+	clone.Items = append([]domain.CartItem{}, cart.Items...)
+	clone.Issues = nil
+	return &clone
+}
@@ -0,0 +1,256 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sort"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type CategoryRepository struct {
+	guard
+	*categoryData
+}
+
+type categoryData struct {
+	categories        map[uint]*domain.Category
+	productCategories map[uint]map[uint]struct{}
+	nextID            uint
+}
+
+func NewCategoryRepository() *CategoryRepository {
+	return &CategoryRepository{
+		guard: newGuard(),
+		categoryData: &categoryData{
+			categories:        make(map[uint]*domain.Category),
+			productCategories: make(map[uint]map[uint]struct{}),
+			nextID:            1,
+		},
+	}
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	category.ID = r.nextID
+	r.categories[category.ID] = category
+	r.nextID++
+	return nil
+}
+
+func (r *CategoryRepository) GetByID(ctx context.Context, id uint) (*domain.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	if category, exists := r.categories[id]; exists {
+		return category, nil
+	}
+	return nil, nil
+}
+
+func (r *CategoryRepository) GetAll(ctx context.Context) ([]domain.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	categories := make([]domain.Category, 0, len(r.categories))
+	for _, category := range r.categories {
+		categories = append(categories, *category)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].ID < categories[j].ID })
+	return categories, nil
+}
+
+func (r *CategoryRepository) GetChildren(ctx context.Context, id uint) ([]domain.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	categories := make([]domain.Category, 0)
+	for _, category := range r.categories {
+		if category.ParentID != nil && *category.ParentID == id {
+			categories = append(categories, *category)
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].ID < categories[j].ID })
+	return categories, nil
+}
+
+func (r *CategoryRepository) GetDescendantIDs(ctx context.Context, id uint) ([]uint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	if _, exists := r.categories[id]; !exists {
+		return []uint{}, nil
+	}
+
+	// Walk the tree breadth first so closer categories come first
+	ids := []uint{id}
+	for i := 0; i < len(ids); i++ {
+		children := make([]uint, 0)
+		for _, category := range r.categories {
+			if category.ParentID != nil && *category.ParentID == ids[i] {
+				children = append(children, category.ID)
+			}
+		}
+		sort.Slice(children, func(a, b int) bool { return children[a] < children[b] })
+		ids = append(ids, children...)
+	}
+	return ids, nil
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, exists := r.categories[category.ID]; !exists {
+		return errors.New("category not found")
+	}
+
+	r.categories[category.ID] = category
+	return nil
+}
+
+func (r *CategoryRepository) Delete(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, exists := r.categories[id]; !exists {
+		return errors.New("category not found")
+	}
+
+	delete(r.categories, id)
+	for _, categoryIDs := range r.productCategories {
+		delete(categoryIDs, id)
+	}
+	return nil
+}
+
+func (r *CategoryRepository) SetProductCategories(ctx context.Context, productID uint, categoryIDs []uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	links := make(map[uint]struct{}, len(categoryIDs))
+	for _, categoryID := range categoryIDs {
+		links[categoryID] = struct{}{}
+	}
+	r.productCategories[productID] = links
+	return nil
+}
+
+func (r *CategoryRepository) GetProductCategories(ctx context.Context, productID uint) ([]domain.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	categories := make([]domain.Category, 0)
+	for categoryID := range r.productCategories[productID] {
+		if category, exists := r.categories[categoryID]; exists {
+			categories = append(categories, *category)
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].ID < categories[j].ID })
+	return categories, nil
+}
+
+func (r *CategoryRepository) GetProductIDs(ctx context.Context, categoryIDs []uint) ([]uint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	wanted := make(map[uint]struct{}, len(categoryIDs))
+	for _, categoryID := range categoryIDs {
+		wanted[categoryID] = struct{}{}
+	}
+
+	ids := make([]uint, 0)
+	for productID, links := range r.productCategories {
+		for categoryID := range links {
+			if _, ok := wanted[categoryID]; ok {
+				ids = append(ids, productID)
+				break
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (r *CategoryRepository) GetCategoryIDsByProducts(ctx context.Context, productIDs []uint) (map[uint][]uint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	categoryIDs := make(map[uint][]uint, len(productIDs))
+	for _, productID := range productIDs {
+		for categoryID := range r.productCategories[productID] {
+			if _, exists := r.categories[categoryID]; exists {
+				categoryIDs[productID] = append(categoryIDs[productID], categoryID)
+			}
+		}
+		sort.Slice(categoryIDs[productID], func(i, j int) bool {
+			return categoryIDs[productID][i] < categoryIDs[productID][j]
+		})
+	}
+	return categoryIDs, nil
+}
+
+func (r *CategoryRepository) bind() interface{} {
+	return &CategoryRepository{guard: r.guard.bound(), categoryData: r.categoryData}
+}
+
+func (r *CategoryRepository) snapshot() func() {
+	r.RLock()
+	categories := cloneMap(r.categories, copyOf[domain.Category])
+	productCategories := cloneMap(r.productCategories, maps.Clone[map[uint]struct{}])
+	nextID := r.nextID
+	r.RUnlock()
+
+	return func() {
+		r.Lock()
+		r.categories, r.productCategories, r.nextID = categories, productCategories, nextID
+		r.Unlock()
+	}
+}
@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+type stockKey struct {
+	productID uint
+	warehouse string
+}
+
+type InventoryRepository struct {
+	guard
+	*inventoryData
+}
+
+type inventoryData struct {
+	levels         map[stockKey]*domain.StockLevel
+	movements      []domain.StockMovement
+	nextMovementID uint
+}
+
+func NewInventoryRepository() *InventoryRepository {
+	return &InventoryRepository{
+		guard: newGuard(),
+		inventoryData: &inventoryData{
+			levels:         make(map[stockKey]*domain.StockLevel),
+			nextMovementID: 1,
+		},
+	}
+}
+
+func (r *InventoryRepository) GetStock(ctx context.Context, productID uint, warehouse string) (*domain.StockLevel, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	if level, exists := r.levels[stockKey{productID, warehouse}]; exists {
+		copied := *level
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *InventoryRepository) GetProductStock(ctx context.Context, productID uint) ([]domain.StockLevel, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	levels := make([]domain.StockLevel, 0)
+	for key, level := range r.levels {
+		if key.productID == productID {
+			levels = append(levels, *level)
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Warehouse < levels[j].Warehouse })
+	return levels, nil
+}
+
+func (r *InventoryRepository) GetLowStock(ctx context.Context) ([]domain.StockLevel, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	levels := make([]domain.StockLevel, 0)
+	for _, level := range r.levels {
+		if level.IsLow() {
+			levels = append(levels, *level)
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		if levels[i].ProductID != levels[j].ProductID {
+			return levels[i].ProductID < levels[j].ProductID
+		}
+		return levels[i].Warehouse < levels[j].Warehouse
+	})
+	return levels, nil
+}
+
+func (r *InventoryRepository) GetMovements(ctx context.Context, productID uint, limit int) ([]domain.StockMovement, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	movements := make([]domain.StockMovement, 0)
+	for i := len(r.movements) - 1; i >= 0 && len(movements) < limit; i-- {
+		if r.movements[i].ProductID == productID {
+			movements = append(movements, r.movements[i])
+		}
+	}
+	return movements, nil
+}
+
+func (r *InventoryRepository) Change(ctx context.Context, productID uint, warehouse string, change repository.StockChange) (*domain.StockLevel, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	key := stockKey{productID, warehouse}
+	level := domain.StockLevel{ProductID: productID, Warehouse: warehouse}
+	if existing, exists := r.levels[key]; exists {
+		level = *existing
+	}
+
+	// Work on a copy so a failed change leaves the stored level untouched
+	movement, err := change(&level)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	level.UpdatedAt = now
+	r.levels[key] = &level
+
+	if movement != nil {
+		movement.ID = r.nextMovementID
+		movement.ProductID = productID
+		movement.Warehouse = warehouse
+		movement.CreatedAt = now
+		r.movements = append(r.movements, *movement)
+		r.nextMovementID++
+	}
+
+	copied := level
+	return &copied, nil
+}
+
+func (r *InventoryRepository) bind() interface{} {
+	return &InventoryRepository{guard: r.guard.bound(), inventoryData: r.inventoryData}
+}
+
+func (r *InventoryRepository) snapshot() func() {
+	r.RLock()
+	levels := cloneMap(r.levels, copyOf[domain.StockLevel])
+	movements := slices.Clone(r.movements)
+	nextMovementID := r.nextMovementID
+	r.RUnlock()
+
+	return func() {
+		r.Lock()
+		r.levels, r.movements, r.nextMovementID = levels, movements, nextMovementID
+		r.Unlock()
+	}
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+type OrderRepository struct {
+	guard
+	*orderData
+}
+
+type orderData struct {
+	orders      map[uint]*domain.Order
+	nextID      uint
+	nextItemID  uint
+	nextEventID uint
+}
+
+func NewOrderRepository() *OrderRepository {
+	return &OrderRepository{
+		guard: newGuard(),
+		orderData: &orderData{
+			orders:      make(map[uint]*domain.Order),
+			nextID:      1,
+			nextItemID:  1,
+			nextEventID: 1,
+		},
+	}
+}
+
+func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	for _, existing := range r.orders {
+		if existing.Number == order.Number {
+			return repository.ErrDuplicateKey
+		}
+	}
+
+	now := time.Now()
+	order.ID = r.nextID
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	for i := range order.Items {
+		order.Items[i].ID = r.nextItemID
+		order.Items[i].OrderID = order.ID
+		r.nextItemID++
+	}
+	for i := range order.History {
+		order.History[i].ID = r.nextEventID
+		order.History[i].OrderID = order.ID
+		order.History[i].CreatedAt = now
+		r.nextEventID++
+	}
+	r.orders[order.ID] = cloneOrder(order)
+	r.nextID++
+	return nil
+}
+
+func (r *OrderRepository) GetByID(ctx context.Context, id uint) (*domain.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	order, exists := r.orders[id]
+	if !exists {
+		return nil, nil
+	}
+	return cloneOrder(order), nil
+}
+
+func (r *OrderRepository) GetByUser(ctx context.Context, userID uint) ([]domain.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.find(func(order *domain.Order) bool {
+		return order.UserID == userID
+	}), nil
+}
+
+func (r *OrderRepository) GetAll(ctx context.Context, status domain.OrderStatus) ([]domain.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.find(func(order *domain.Order) bool {
+		return status == "" || order.Status == status
+	}), nil
+}
+
+func (r *OrderRepository) UpdateStatus(ctx context.Context, event *domain.OrderEvent) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	order, exists := r.orders[event.OrderID]
+	if !exists {
+		return false, errors.New("order not found")
+	}
+	if order.Status != event.From {
+		return false, nil
+	}
+
+	event.ID = r.nextEventID
+	event.CreatedAt = time.Now()
+	r.nextEventID++
+	order.Status = event.To
+	order.UpdatedAt = event.CreatedAt
+	order.History = append(order.History, *event)
+	return true, nil
+}
+
+func (r *OrderRepository) find(match func(order *domain.Order) bool) []domain.Order {
+	r.RLock()
+	defer r.RUnlock()
+
+	orders := make([]domain.Order, 0)
+	for _, order := range r.orders {
+		if match(order) {
+			orders = append(orders, *cloneOrder(order))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].ID > orders[j].ID
+	})
+	return orders
+}
+
+func (r *OrderRepository) bind() interface{} {
+	return &OrderRepository{guard: r.guard.bound(), orderData: r.orderData}
+}
+
+func (r *OrderRepository) snapshot() func() {
+	r.RLock()
+	orders := cloneMap(r.orders, cloneOrder)
+	nextID, nextItemID, nextEventID := r.nextID, r.nextItemID, r.nextEventID
+	r.RUnlock()
+
+	return func() {
+		r.Lock()
+		r.orders, r.nextID, r.nextItemID, r.nextEventID = orders, nextID, nextItemID, nextEventID
+		r.Unlock()
+	}
+}
+
+func cloneOrder(order *domain.Order) *domain.Order {
+	clone := *order
+	clone.Items = append([]domain.OrderItem{}, order.Items...)
+	clone.History = append([]domain.OrderEvent{}, order.History...)
+	clone.Promotions = append([]domain.AppliedPromotion{}, order.Promotions...)
+	return &clone
+}
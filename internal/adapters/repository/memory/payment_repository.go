@@ -0,0 +1,189 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+type PaymentRepository struct {
+	guard
+	*paymentData
+}
+
+type paymentData struct {
+	payments map[uint]*domain.Payment
+	events   map[string]domain.PaymentEvent
+	nextID   uint
+}
+
+func NewPaymentRepository() *PaymentRepository {
+	return &PaymentRepository{
+		guard: newGuard(),
+		paymentData: &paymentData{
+			payments: make(map[uint]*domain.Payment),
+			events:   make(map[string]domain.PaymentEvent),
+			nextID:   1,
+		},
+	}
+}
+
+func (r *PaymentRepository) Create(ctx context.Context, payment *domain.Payment) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	for _, existing := range r.payments {
+		if existing.IntentID == payment.IntentID {
+			return repository.ErrDuplicateKey
+		}
+	}
+
+	now := time.Now()
+	payment.ID = r.nextID
+	payment.CreatedAt = now
+	payment.UpdatedAt = now
+	stored := *payment
+	stored.ClientSecret = ""
+	r.payments[payment.ID] = &stored
+	r.nextID++
+	return nil
+}
+
+func (r *PaymentRepository) GetByIntent(ctx context.Context, intentID string) (*domain.Payment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, payment := range r.payments {
+		if payment.IntentID == intentID {
+			clone := *payment
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *PaymentRepository) GetByOrder(ctx context.Context, orderID uint) ([]domain.Payment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.find(func(payment *domain.Payment) bool {
+		return payment.OrderID == orderID
+	}, true), nil
+}
+
+func (r *PaymentRepository) UpdateStatus(ctx context.Context, id uint, from, to domain.PaymentStatus) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	payment, exists := r.payments[id]
+	if !exists {
+		return false, errors.New("payment not found")
+	}
+	if payment.Status != from {
+		return false, nil
+	}
+	payment.Status = to
+	payment.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (r *PaymentRepository) GetStale(ctx context.Context, statuses []domain.PaymentStatus, before time.Time) ([]domain.Payment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.find(func(payment *domain.Payment) bool {
+		if !payment.UpdatedAt.Before(before) {
+			return false
+		}
+		for _, status := range statuses {
+			if payment.Status == status {
+				return true
+			}
+		}
+		return false
+	}, false), nil
+}
+
+func (r *PaymentRepository) HasEvent(ctx context.Context, id string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	_, exists := r.events[id]
+	return exists, nil
+}
+
+func (r *PaymentRepository) RecordEvent(ctx context.Context, event *domain.PaymentEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, exists := r.events[event.ID]; !exists {
+		event.CreatedAt = time.Now()
+		r.events[event.ID] = *event
+	}
+	return nil
+}
+
+// find returns the matching payments ordered by ID, newest first when newestFirst is set.
+func (r *PaymentRepository) find(match func(payment *domain.Payment) bool, newestFirst bool) []domain.Payment {
+	r.RLock()
+	defer r.RUnlock()
+
+	payments := make([]domain.Payment, 0)
+	for _, payment := range r.payments {
+		if match(payment) {
+			payments = append(payments, *payment)
+		}
+	}
+	sort.Slice(payments, func(i, j int) bool {
+		if newestFirst {
+			return payments[i].ID > payments[j].ID
+		}
+		return payments[i].ID < payments[j].ID
+	})
+	return payments
+}
+
+func (r *PaymentRepository) bind() interface{} {
+	return &PaymentRepository{guard: r.guard.bound(), paymentData: r.paymentData}
+}
+
+func (r *PaymentRepository) snapshot() func() {
+	r.RLock()
+	payments := cloneMap(r.payments, copyOf[domain.Payment])
+	events := maps.Clone(r.events)
+	nextID := r.nextID
+	r.RUnlock()
+
+	return func() {
+		r.Lock()
+		r.payments, r.events, r.nextID = payments, events, nextID
+		r.Unlock()
+	}
+}
@@ -0,0 +1,201 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type PriceRepository struct {
+	guard
+	*priceData
+}
+
+type priceData struct {
+	changes        []domain.PriceChange
+	schedules      map[uint]*domain.PriceSchedule
+	nextChangeID   uint
+	nextScheduleID uint
+}
+
+func NewPriceRepository() *PriceRepository {
+	return &PriceRepository{
+		guard: newGuard(),
+		priceData: &priceData{
+			schedules:      make(map[uint]*domain.PriceSchedule),
+			nextChangeID:   1,
+			nextScheduleID: 1,
+		},
+	}
+}
+
+func (r *PriceRepository) RecordChange(ctx context.Context, change *domain.PriceChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	change.ID = r.nextChangeID
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now()
+	}
+	r.changes = append(r.changes, *change)
+	r.nextChangeID++
+	return nil
+}
+
+func (r *PriceRepository) GetHistory(ctx context.Context, productID uint, limit int) ([]domain.PriceChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	changes := make([]domain.PriceChange, 0)
+	for i := len(r.changes) - 1; i >= 0 && len(changes) < limit; i-- {
+		if r.changes[i].ProductID == productID {
+			changes = append(changes, r.changes[i])
+		}
+	}
+	return changes, nil
+}
+
+func (r *PriceRepository) CreateSchedule(ctx context.Context, schedule *domain.PriceSchedule) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	now := time.Now()
+	schedule.ID = r.nextScheduleID
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+	copied := *schedule
+	r.schedules[schedule.ID] = &copied
+	r.nextScheduleID++
+	return nil
+}
+
+func (r *PriceRepository) GetSchedule(ctx context.Context, id uint) (*domain.PriceSchedule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	if schedule, exists := r.schedules[id]; exists {
+		copied := *schedule
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *PriceRepository) GetSchedules(ctx context.Context, productID uint) ([]domain.PriceSchedule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.collect(func(schedule *domain.PriceSchedule) bool {
+		return schedule.ProductID == productID
+	}), nil
+}
+
+func (r *PriceRepository) GetDueSchedules(ctx context.Context, now time.Time) ([]domain.PriceSchedule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.collect(func(schedule *domain.PriceSchedule) bool {
+		return scheduleDue(schedule, now)
+	}), nil
+}
+
+// LockSchedule returns a copy of a schedule. Units of work on this package
+// run one at a time, so there is nothing to lock.
+func (r *PriceRepository) LockSchedule(ctx context.Context, id uint) (*domain.PriceSchedule, error) {
+	return r.GetSchedule(ctx, id)
+}
+
+func (r *PriceRepository) ClaimDueSchedule(ctx context.Context, id uint, now time.Time) (*domain.PriceSchedule, error) {
+	schedule, err := r.GetSchedule(ctx, id)
+	if err != nil || schedule == nil || !scheduleDue(schedule, now) {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (r *PriceRepository) UpdateSchedule(ctx context.Context, schedule *domain.PriceSchedule) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, exists := r.schedules[schedule.ID]; !exists {
+		return errors.New("price schedule not found")
+	}
+	schedule.UpdatedAt = time.Now()
+	copied := *schedule
+	r.schedules[schedule.ID] = &copied
+	return nil
+}
+
+func scheduleDue(schedule *domain.PriceSchedule, now time.Time) bool {
+	switch schedule.Status {
+	case domain.PriceScheduleScheduled:
+		return !schedule.StartsAt.After(now)
+	case domain.PriceScheduleActive:
+		return schedule.EndsAt != nil && !schedule.EndsAt.After(now)
+	}
+	return false
+}
+
+// collect returns copies of the schedules accepted by match, ordered by start time.
+func (r *PriceRepository) collect(match func(schedule *domain.PriceSchedule) bool) []domain.PriceSchedule {
+	r.RLock()
+	defer r.RUnlock()
+
+	schedules := make([]domain.PriceSchedule, 0)
+	for _, schedule := range r.schedules {
+		if match(schedule) {
+			schedules = append(schedules, *schedule)
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		if !schedules[i].StartsAt.Equal(schedules[j].StartsAt) {
+			return schedules[i].StartsAt.Before(schedules[j].StartsAt)
+		}
+		return schedules[i].ID < schedules[j].ID
+	})
+	return schedules
+}
+
+func (r *PriceRepository) bind() interface{} {
+	return &PriceRepository{guard: r.guard.bound(), priceData: r.priceData}
+}
+
+func (r *PriceRepository) snapshot() func() {
+	r.RLock()
+	changes := slices.Clone(r.changes)
+	schedules := cloneMap(r.schedules, copyOf[domain.PriceSchedule])
+	nextChangeID, nextScheduleID := r.nextChangeID, r.nextScheduleID
+	r.RUnlock()
+
+	return func() {
+		r.Lock()
+		r.changes, r.schedules = changes, schedules
+		r.nextChangeID, r.nextScheduleID = nextChangeID, nextScheduleID
+		r.Unlock()
+	}
+}
@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type ProductImageRepository struct {
+	guard
+	*productImageData
+}
+
+type productImageData struct {
+	images map[uint]*domain.ProductImage
+	nextID uint
+}
+
+func NewProductImageRepository() *ProductImageRepository {
+	return &ProductImageRepository{
+		guard: newGuard(),
+		productImageData: &productImageData{
+			images: make(map[uint]*domain.ProductImage),
+			nextID: 1,
+		},
+	}
+}
+
+func (r *ProductImageRepository) Create(ctx context.Context, image *domain.ProductImage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	image.ID = r.nextID
+	image.CreatedAt = time.Now()
+	stored := *image
+	r.images[image.ID] = &stored
+	r.nextID++
+	return nil
+}
+
+func (r *ProductImageRepository) GetByID(ctx context.Context, id uint) (*domain.ProductImage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	if image, exists := r.images[id]; exists {
+		copied := *image
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *ProductImageRepository) GetByProduct(ctx context.Context, productID uint) ([]domain.ProductImage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	images := make([]domain.ProductImage, 0)
+	for _, image := range r.images {
+		if image.ProductID == productID {
+			images = append(images, *image)
+		}
+	}
+	sortImages(images)
+	return images, nil
+}
+
+func (r *ProductImageRepository) GetByProducts(ctx context.Context, productIDs []uint) (map[uint][]domain.ProductImage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	wanted := make(map[uint]struct{}, len(productIDs))
+	for _, productID := range productIDs {
+		wanted[productID] = struct{}{}
+	}
+
+	images := make(map[uint][]domain.ProductImage, len(productIDs))
+	for _, image := range r.images {
+		if _, ok := wanted[image.ProductID]; ok {
+			images[image.ProductID] = append(images[image.ProductID], *image)
+		}
+	}
+	for _, productImages := range images {
+		sortImages(productImages)
+	}
+	return images, nil
+}
+
+func (r *ProductImageRepository) UpdateAll(ctx context.Context, images []domain.ProductImage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	for _, image := range images {
+		if _, exists := r.images[image.ID]; !exists {
+			return errors.New("product image not found")
+		}
+	}
+	for _, image := range images {
+		r.images[image.ID].Position = image.Position
+		r.images[image.ID].IsPrimary = image.IsPrimary
+	}
+	return nil
+}
+
+func (r *ProductImageRepository) Delete(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, exists := r.images[id]; !exists {
+		return errors.New("product image not found")
+	}
+
+	delete(r.images, id)
+	return nil
+}
+
+func (r *ProductImageRepository) DeleteByProduct(ctx context.Context, productID uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	for id, image := range r.images {
+		if image.ProductID == productID {
+			delete(r.images, id)
+		}
+	}
+	return nil
+}
+
+func (r *ProductImageRepository) bind() interface{} {
+	return &ProductImageRepository{guard: r.guard.bound(), productImageData: r.productImageData}
+}
+
+func (r *ProductImageRepository) snapshot() func() {
+	r.RLock()
+	images := cloneMap(r.images, copyOf[domain.ProductImage])
+	nextID := r.nextID
+	r.RUnlock()
+
+	return func() {
+		r.Lock()
+		r.images, r.nextID = images, nextID
+		r.Unlock()
+	}
+}
+
+func sortImages(images []domain.ProductImage) {
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Position != images[j].Position {
+			return images[i].Position < images[j].Position
+		}
+		return images[i].ID < images[j].ID
+	})
+}
@@ -1,69 +1,227 @@
 package memory
 
 import (
+	"context"
 	"errors"
-	"sync"
+	"slices"
+	"sort"
+	"time"
 
 	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
 )
 
 type ProductRepository struct {
-	sync.RWMutex
-	products map[uint]*domain.Product
-	nextID   uint
+	guard
+	*productData
+}
+
+type productData struct {
+	products       map[uint]*domain.Product
+	revisions      map[uint][]domain.ProductRevision
+	nextID         uint
+	nextVariantID  uint
+	nextRevisionID uint
 }
 
 func NewProductRepository() *ProductRepository {
 	return &ProductRepository{
-		products: make(map[uint]*domain.Product),
-		nextID:   1,
+		guard: newGuard(),
+		productData: &productData{
+			products:       make(map[uint]*domain.Product),
+			revisions:      make(map[uint][]domain.ProductRevision),
+			nextID:         1,
+			nextVariantID:  1,
+			nextRevisionID: 1,
+		},
 	}
 }
 
-func (r *ProductRepository) Create(product *domain.Product) error {
+func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.Lock()
 	defer r.Unlock()
 
+	if r.conflicts(product) {
+		return repository.ErrDuplicateKey
+	}
+
 	product.ID = r.nextID
-	r.products[product.ID] = product
+	r.assignVariantIDs(product)
+	r.products[product.ID] = cloneProduct(product)
 	r.nextID++
 	return nil
 }
 
-func (r *ProductRepository) GetByID(id uint) (*domain.Product, error) {
+func (r *ProductRepository) GetByID(ctx context.Context, id uint) (*domain.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.RLock()
 	defer r.RUnlock()
 
 	if product, exists := r.products[id]; exists {
-		return product, nil
+		return cloneProduct(product), nil
+	}
+	return nil, nil
+}
+
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, product := range r.products {
+		if product.SKU == sku {
+			return cloneProduct(product), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *ProductRepository) GetBySlug(ctx context.Context, slug string) (*domain.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, product := range r.products {
+		if product.Slug == slug {
+			return cloneProduct(product), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *ProductRepository) GetVariantBySKU(ctx context.Context, sku string) (*domain.ProductVariant, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, product := range r.products {
+		for i := range product.Variants {
+			if product.Variants[i].SKU == sku {
+				variant := product.Variants[i]
+				return &variant, nil
+			}
+		}
 	}
 	return nil, nil
 }
 
-func (r *ProductRepository) GetAll() ([]domain.Product, error) {
+func (r *ProductRepository) GetAll(ctx context.Context) ([]domain.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.RLock()
 	defer r.RUnlock()
 
 	products := make([]domain.Product, 0, len(r.products))
 	for _, product := range r.products {
-		products = append(products, *product)
+		products = append(products, *cloneProduct(product))
+	}
+	return products, nil
+}
+
+func (r *ProductRepository) GetByIDs(ctx context.Context, ids []uint) ([]domain.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	products := make([]domain.Product, 0, len(ids))
+	for _, id := range ids {
+		if product, exists := r.products[id]; exists {
+			products = append(products, *cloneProduct(product))
+		}
 	}
 	return products, nil
 }
 
-func (r *ProductRepository) Update(product *domain.Product) error {
+func (r *ProductRepository) Find(ctx context.Context, filter domain.ProductFilter) ([]domain.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	products := make([]domain.Product, 0)
+	for _, product := range r.products {
+		if matchesFilter(product, filter) {
+			products = append(products, *cloneProduct(product))
+		}
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].ID < products[j].ID })
+	return products, nil
+}
+
+func (r *ProductRepository) FindAfter(ctx context.Context, filter domain.ProductFilter, afterID uint, limit int) ([]domain.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	ids := make([]uint, 0, len(r.products))
+	for id, product := range r.products {
+		if id > afterID && matchesFilter(product, filter) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	products := make([]domain.Product, 0, len(ids))
+	for _, id := range ids {
+		products = append(products, *cloneProduct(r.products[id]))
+	}
+	return products, nil
+}
+
+func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.Lock()
 	defer r.Unlock()
 
 	if _, exists := r.products[product.ID]; !exists {
 		return errors.New("product not found")
 	}
+	if r.conflicts(product) {
+		return repository.ErrDuplicateKey
+	}
 
-	r.products[product.ID] = product
+	r.assignVariantIDs(product)
+	r.products[product.ID] = cloneProduct(product)
 	return nil
 }
 
-func (r *ProductRepository) Delete(id uint) error {
+func (r *ProductRepository) Delete(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.Lock()
 	defer r.Unlock()
 
@@ -74,3 +232,199 @@ func (r *ProductRepository) Delete(id uint) error {
 	delete(r.products, id)
 	return nil
 }
+
+func (r *ProductRepository) CreateRevision(ctx context.Context, revision *domain.ProductRevision) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	revisions := r.revisions[revision.ProductID]
+	revision.ID = r.nextRevisionID
+	revision.Number = len(revisions) + 1
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = time.Now()
+	}
+	// Copy before appending so a snapshot taken by Transaction keeps its own slice
+	r.revisions[revision.ProductID] = append(revisions[:len(revisions):len(revisions)], *revision)
+	r.nextRevisionID++
+	return nil
+}
+
+func (r *ProductRepository) GetRevision(ctx context.Context, productID uint, number int) (*domain.ProductRevision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	revisions := r.revisions[productID]
+	if number < 1 || number > len(revisions) {
+		return nil, nil
+	}
+	revision := revisions[number-1]
+	return &revision, nil
+}
+
+func (r *ProductRepository) GetRevisions(ctx context.Context, productID uint, limit int) ([]domain.ProductRevision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	stored := r.revisions[productID]
+	revisions := make([]domain.ProductRevision, 0)
+	for i := len(stored) - 1; i >= 0 && len(revisions) < limit; i-- {
+		revisions = append(revisions, stored[i])
+	}
+	return revisions, nil
+}
+
+// conflicts reports whether another product already uses the SKU, slug,
+// barcode or a variant SKU of product.
+func (r *ProductRepository) conflicts(product *domain.Product) bool {
+	variantSKUs := make(map[string]struct{}, len(product.Variants))
+	for _, variant := range product.Variants {
+		if _, ok := variantSKUs[variant.SKU]; ok {
+			return true
+		}
+		variantSKUs[variant.SKU] = struct{}{}
+	}
+
+	for id, existing := range r.products {
+		if id == product.ID {
+			continue
+		}
+		if existing.SKU == product.SKU || existing.Slug == product.Slug {
+			return true
+		}
+		if existing.Barcode != nil && product.Barcode != nil && *existing.Barcode == *product.Barcode {
+			return true
+		}
+		for _, variant := range existing.Variants {
+			if _, ok := variantSKUs[variant.SKU]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *ProductRepository) assignVariantIDs(product *domain.Product) {
+	for i := range product.Variants {
+		product.Variants[i].ProductID = product.ID
+		if product.Variants[i].ID == 0 {
+			product.Variants[i].ID = r.nextVariantID
+			r.nextVariantID++
+		}
+	}
+	for i := range product.Options {
+		product.Options[i].ProductID = product.ID
+	}
+}
+
+func (r *ProductRepository) bind() interface{} {
+	return &ProductRepository{guard: r.guard.bound(), productData: r.productData}
+}
+
+func (r *ProductRepository) snapshot() func() {
+	r.RLock()
+	products := cloneMap(r.products, cloneProduct)
+	revisions := cloneMap(r.revisions, slices.Clone[[]domain.ProductRevision])
+	nextID, nextVariantID, nextRevisionID := r.nextID, r.nextVariantID, r.nextRevisionID
+	r.RUnlock()
+
+	return func() {
+		r.Lock()
+		r.products, r.nextID, r.nextVariantID = products, nextID, nextVariantID
+		r.revisions, r.nextRevisionID = revisions, nextRevisionID
+		r.Unlock()
+	}
+}
+
+func matchesFilter(product *domain.Product, filter domain.ProductFilter) bool {
+	if filter.ProductIDs != nil && !containsID(filter.ProductIDs, product.ID) {
+		return false
+	}
+	if filter.MinPrice != nil && product.Price < *filter.MinPrice {
+		return false
+	}
+	if filter.MaxPrice != nil && product.Price > *filter.MaxPrice {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !containsStatus(filter.Statuses, product.Status) {
+		return false
+	}
+	if filter.LiveAt != nil && !product.IsLive(*filter.LiveAt) {
+		return false
+	}
+	if len(filter.Tags) == 0 {
+		return true
+	}
+
+	matched := 0
+	for _, tag := range filter.Tags {
+		for _, productTag := range product.Tags {
+			if productTag == tag {
+				matched++
+				break
+			}
+		}
+	}
+	if filter.TagMatch == domain.TagMatchAll {
+		return matched == len(filter.Tags)
+	}
+	return matched > 0
+}
+
+func containsStatus(statuses []domain.ProductStatus, status domain.ProductStatus) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneProduct copies a product deeply enough that callers cannot change the
+// stored tags, currency prices, options or variants through shared slices
+// and maps.
+func cloneProduct(product *domain.Product) *domain.Product {
+	clone := *product
+	clone.Tags = append([]string(nil), product.Tags...)
+	if product.CurrencyPrices != nil {
+		clone.CurrencyPrices = make(map[string]float64, len(product.CurrencyPrices))
+		for code, price := range product.CurrencyPrices {
+			clone.CurrencyPrices[code] = price
+		}
+	}
+	clone.Options = make([]domain.ProductOption, len(product.Options))
+	for i, option := range product.Options {
+		option.Values = append([]string(nil), option.Values...)
+		clone.Options[i] = option
+	}
+	clone.Variants = make([]domain.ProductVariant, len(product.Variants))
+	for i, variant := range product.Variants {
+		attributes := make(map[string]string, len(variant.Attributes))
+		for name, value := range variant.Attributes {
+			attributes[name] = value
+		}
+		variant.Attributes = attributes
+		clone.Variants[i] = variant
+	}
+	return &clone
+}
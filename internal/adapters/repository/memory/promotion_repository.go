@@ -0,0 +1,277 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+type PromotionRepository struct {
+	guard
+	*promotionData
+}
+
+type promotionData struct {
+	promotions       map[uint]*domain.Promotion
+	redemptions      []domain.PromotionRedemption
+	nextID           uint
+	nextRedemptionID uint
+}
+
+func NewPromotionRepository() *PromotionRepository {
+	return &PromotionRepository{
+		guard: newGuard(),
+		promotionData: &promotionData{
+			promotions:       make(map[uint]*domain.Promotion),
+			nextID:           1,
+			nextRedemptionID: 1,
+		},
+	}
+}
+
+func (r *PromotionRepository) Create(ctx context.Context, promotion *domain.Promotion) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if r.codeTaken(promotion) {
+		return repository.ErrDuplicateKey
+	}
+
+	now := time.Now()
+	promotion.ID = r.nextID
+	promotion.CreatedAt = now
+	promotion.UpdatedAt = now
+	r.promotions[promotion.ID] = clonePromotion(promotion)
+	r.nextID++
+	return nil
+}
+
+func (r *PromotionRepository) GetByID(ctx context.Context, id uint) (*domain.Promotion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	if promotion, exists := r.promotions[id]; exists {
+		return clonePromotion(promotion), nil
+	}
+	return nil, nil
+}
+
+func (r *PromotionRepository) GetByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, promotion := range r.promotions {
+		if promotion.Code != nil && *promotion.Code == code {
+			return clonePromotion(promotion), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *PromotionRepository) GetAll(ctx context.Context) ([]domain.Promotion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.collect(func(*domain.Promotion) bool { return true }), nil
+}
+
+func (r *PromotionRepository) GetAutomatic(ctx context.Context, now time.Time) ([]domain.Promotion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.collect(func(promotion *domain.Promotion) bool {
+		return promotion.Code == nil && promotion.InEffect(now)
+	}), nil
+}
+
+func (r *PromotionRepository) Update(ctx context.Context, promotion *domain.Promotion) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	existing, exists := r.promotions[promotion.ID]
+	if !exists {
+		return errors.New("promotion not found")
+	}
+	if r.codeTaken(promotion) {
+		return repository.ErrDuplicateKey
+	}
+
+	promotion.UsageCount = existing.UsageCount
+	promotion.CreatedAt = existing.CreatedAt
+	promotion.UpdatedAt = time.Now()
+	r.promotions[promotion.ID] = clonePromotion(promotion)
+	return nil
+}
+
+func (r *PromotionRepository) Delete(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, exists := r.promotions[id]; !exists {
+		return errors.New("promotion not found")
+	}
+	delete(r.promotions, id)
+
+	redemptions := r.redemptions[:0]
+	for _, redemption := range r.redemptions {
+		if redemption.PromotionID != id {
+			redemptions = append(redemptions, redemption)
+		}
+	}
+	r.redemptions = redemptions
+	return nil
+}
+
+func (r *PromotionRepository) CountRedemptions(ctx context.Context, promotionID, userID uint) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.countRedemptions(promotionID, userID), nil
+}
+
+func (r *PromotionRepository) Redeem(ctx context.Context, redemption *domain.PromotionRedemption) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	promotion, exists := r.promotions[redemption.PromotionID]
+	if !exists {
+		return false, errors.New("promotion not found")
+	}
+	if promotion.Exhausted() {
+		return false, nil
+	}
+	if promotion.PerUserLimit != nil && r.countRedemptions(promotion.ID, redemption.UserID) >= *promotion.PerUserLimit {
+		return false, nil
+	}
+
+	redemption.ID = r.nextRedemptionID
+	if redemption.CreatedAt.IsZero() {
+		redemption.CreatedAt = time.Now()
+	}
+	r.redemptions = append(r.redemptions, *redemption)
+	r.nextRedemptionID++
+	promotion.UsageCount++
+	return true, nil
+}
+
+func (r *PromotionRepository) Unredeem(ctx context.Context, reference string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	redemptions := r.redemptions[:0]
+	for _, redemption := range r.redemptions {
+		if redemption.Reference != reference {
+			redemptions = append(redemptions, redemption)
+			continue
+		}
+		if promotion, exists := r.promotions[redemption.PromotionID]; exists && promotion.UsageCount > 0 {
+			promotion.UsageCount--
+		}
+	}
+	r.redemptions = redemptions
+	return nil
+}
+
+func (r *PromotionRepository) countRedemptions(promotionID, userID uint) int {
+	count := 0
+	for _, redemption := range r.redemptions {
+		if redemption.PromotionID == promotionID && redemption.UserID == userID {
+			count++
+		}
+	}
+	return count
+}
+
+// codeTaken reports whether another promotion already uses the coupon code
+// of promotion.
+func (r *PromotionRepository) codeTaken(promotion *domain.Promotion) bool {
+	if promotion.Code == nil {
+		return false
+	}
+	for id, existing := range r.promotions {
+		if id != promotion.ID && existing.Code != nil && *existing.Code == *promotion.Code {
+			return true
+		}
+	}
+	return false
+}
+
+// collect returns copies of the promotions accepted by match, ordered by ID.
+func (r *PromotionRepository) collect(match func(promotion *domain.Promotion) bool) []domain.Promotion {
+	r.RLock()
+	defer r.RUnlock()
+
+	promotions := make([]domain.Promotion, 0)
+	for _, promotion := range r.promotions {
+		if match(promotion) {
+			promotions = append(promotions, *clonePromotion(promotion))
+		}
+	}
+	sort.Slice(promotions, func(i, j int) bool { return promotions[i].ID < promotions[j].ID })
+	return promotions
+}
+
+func (r *PromotionRepository) bind() interface{} {
+	return &PromotionRepository{guard: r.guard.bound(), promotionData: r.promotionData}
+}
+
+func (r *PromotionRepository) snapshot() func() {
+	r.RLock()
+	promotions := cloneMap(r.promotions, clonePromotion)
+	redemptions := slices.Clone(r.redemptions)
+	nextID, nextRedemptionID := r.nextID, r.nextRedemptionID
+	r.RUnlock()
+
+	return func() {
+		r.Lock()
+		r.promotions, r.redemptions = promotions, redemptions
+		r.nextID, r.nextRedemptionID = nextID, nextRedemptionID
+		r.Unlock()
+	}
+}
+
+func clonePromotion(promotion *domain.Promotion) *domain.Promotion {
+	clone := *promotion
+	clone.ProductIDs = append([]uint(nil), promotion.ProductIDs...)
+	clone.CategoryIDs = append([]uint(nil), promotion.CategoryIDs...)
+	return &clone
+}
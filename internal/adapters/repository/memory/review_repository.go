@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+type ReviewRepository struct {
+	guard
+	*reviewData
+}
+
+type reviewData struct {
+	reviews map[uint]*domain.Review
+	nextID  uint
+}
+
+func NewReviewRepository() *ReviewRepository {
+	return &ReviewRepository{
+		guard: newGuard(),
+		reviewData: &reviewData{
+			reviews: make(map[uint]*domain.Review),
+			nextID:  1,
+		},
+	}
+}
+
+func (r *ReviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	for _, existing := range r.reviews {
+		if existing.ProductID == review.ProductID && existing.UserID == review.UserID {
+			return repository.ErrDuplicateKey
+		}
+	}
+
+	now := time.Now()
+	review.ID = r.nextID
+	review.CreatedAt = now
+	review.UpdatedAt = now
+	copied := *review
+	r.reviews[review.ID] = &copied
+	r.nextID++
+	return nil
+}
+
+func (r *ReviewRepository) GetByID(ctx context.Context, id uint) (*domain.Review, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	if review, exists := r.reviews[id]; exists {
+		copied := *review
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *ReviewRepository) GetByProduct(ctx context.Context, productID uint, status domain.ReviewStatus) ([]domain.Review, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	reviews := make([]domain.Review, 0)
+	for _, review := range r.reviews {
+		if review.ProductID == productID && (status == "" || review.Status == status) {
+			reviews = append(reviews, *review)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		if !reviews[i].CreatedAt.Equal(reviews[j].CreatedAt) {
+			return reviews[i].CreatedAt.After(reviews[j].CreatedAt)
+		}
+		return reviews[i].ID > reviews[j].ID
+	})
+	return reviews, nil
+}
+
+func (r *ReviewRepository) Update(ctx context.Context, review *domain.Review) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, exists := r.reviews[review.ID]; !exists {
+		return errors.New("review not found")
+	}
+	review.UpdatedAt = time.Now()
+	copied := *review
+	r.reviews[review.ID] = &copied
+	return nil
+}
+
+func (r *ReviewRepository) Delete(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, exists := r.reviews[id]; !exists {
+		return errors.New("review not found")
+	}
+	delete(r.reviews, id)
+	return nil
+}
+
+func (r *ReviewRepository) GetRatingCounts(ctx context.Context, productIDs []uint) (map[uint]map[int]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	counts := make(map[uint]map[int]int)
+	for _, review := range r.reviews {
+		if review.Status != domain.ReviewApproved || !containsID(productIDs, review.ProductID) {
+			continue
+		}
+		if counts[review.ProductID] == nil {
+			counts[review.ProductID] = make(map[int]int)
+		}
+		counts[review.ProductID][review.Rating]++
+	}
+	return counts, nil
+}
+
+func (r *ReviewRepository) bind() interface{} {
+	return &ReviewRepository{guard: r.guard.bound(), reviewData: r.reviewData}
+}
+
+func (r *ReviewRepository) snapshot() func() {
+	r.RLock()
+	reviews := cloneMap(r.reviews, copyOf[domain.Review])
+	nextID := r.nextID
+	r.RUnlock()
+
+	return func() {
+		r.Lock()
+		r.reviews, r.nextID = reviews, nextID
+		r.Unlock()
+	}
+}
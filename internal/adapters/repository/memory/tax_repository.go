@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+type TaxRateRepository struct {
+	guard
+	*taxRateData
+}
+
+type taxRateData struct {
+	rates  map[uint]domain.TaxRate
+	nextID uint
+}
+
+func NewTaxRateRepository() *TaxRateRepository {
+	return &TaxRateRepository{
+		guard: newGuard(),
+		taxRateData: &taxRateData{
+			rates:  make(map[uint]domain.TaxRate),
+			nextID: 1,
+		},
+	}
+}
+
+func (r *TaxRateRepository) Create(ctx context.Context, rate *domain.TaxRate) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if r.jurisdictionTaken(rate) {
+		return repository.ErrDuplicateKey
+	}
+
+	now := time.Now()
+	rate.ID = r.nextID
+	rate.CreatedAt = now
+	rate.UpdatedAt = now
+	r.rates[rate.ID] = *rate
+	r.nextID++
+	return nil
+}
+
+func (r *TaxRateRepository) GetByID(ctx context.Context, id uint) (*domain.TaxRate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	if rate, exists := r.rates[id]; exists {
+		return &rate, nil
+	}
+	return nil, nil
+}
+
+func (r *TaxRateRepository) GetAll(ctx context.Context) ([]domain.TaxRate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.collect(func(*domain.TaxRate) bool { return true }), nil
+}
+
+func (r *TaxRateRepository) GetByCountry(ctx context.Context, country string) ([]domain.TaxRate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.collect(func(rate *domain.TaxRate) bool {
+		return rate.Country == country
+	}), nil
+}
+
+func (r *TaxRateRepository) Update(ctx context.Context, rate *domain.TaxRate) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	existing, exists := r.rates[rate.ID]
+	if !exists {
+		return errors.New("tax rate not found")
+	}
+	if r.jurisdictionTaken(rate) {
+		return repository.ErrDuplicateKey
+	}
+
+	rate.CreatedAt = existing.CreatedAt
+	rate.UpdatedAt = time.Now()
+	r.rates[rate.ID] = *rate
+	return nil
+}
+
+func (r *TaxRateRepository) Delete(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, exists := r.rates[id]; !exists {
+		return errors.New("tax rate not found")
+	}
+	delete(r.rates, id)
+	return nil
+}
+
+// jurisdictionTaken reports whether another rate has the jurisdiction and
+// tax class of rate.
+func (r *TaxRateRepository) jurisdictionTaken(rate *domain.TaxRate) bool {
+	for id, existing := range r.rates {
+		if id != rate.ID && existing.Country == rate.Country &&
+			existing.Region == rate.Region && existing.TaxClass == rate.TaxClass {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *TaxRateRepository) collect(match func(rate *domain.TaxRate) bool) []domain.TaxRate {
+	r.RLock()
+	defer r.RUnlock()
+
+	rates := make([]domain.TaxRate, 0)
+	for _, rate := range r.rates {
+		if match(&rate) {
+			rates = append(rates, rate)
+		}
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Country != rates[j].Country {
+			return rates[i].Country < rates[j].Country
+		}
+		if rates[i].Region != rates[j].Region {
+			return rates[i].Region < rates[j].Region
+		}
+		return rates[i].TaxClass < rates[j].TaxClass
+	})
+	return rates
+}
+
+func (r *TaxRateRepository) bind() interface{} {
+	return &TaxRateRepository{guard: r.guard.bound(), taxRateData: r.taxRateData}
+}
+
+func (r *TaxRateRepository) snapshot() func() {
+	r.RLock()
+	rates := maps.Clone(r.rates)
+	nextID := r.nextID
+	r.RUnlock()
+
+	return func() {
+		r.Lock()
+		r.rates, r.nextID = rates, nextID
+		r.Unlock()
+	}
+}
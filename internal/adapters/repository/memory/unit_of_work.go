@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+// member is implemented by the repositories a UnitOfWork can roll back.
+type member interface {
+	// snapshot copies the stored data and returns a function that restores
+	// the copy.
+	snapshot() (restore func())
+	// join makes the repository wait for the transactions of gate.
+	join(gate *sync.RWMutex)
+	// bind returns a handle on the same data for use in a transaction.
+	bind() interface{}
+}
+
+// guard locks the data of a repository. Outside a transaction it also holds
+// the gate of the unit of work the repository joined for reading, so reads
+// and writes wait for a running transaction and a transaction waits for
+// them. Handles bound to a transaction have no gate.
+type guard struct {
+	mu   *sync.RWMutex
+	gate *sync.RWMutex
+}
+
+func newGuard() guard {
+	return guard{mu: new(sync.RWMutex)}
+}
+
+func (g *guard) join(gate *sync.RWMutex) {
+	g.gate = gate
+}
+
+// bound returns the guard of a handle bound to a transaction.
+func (g guard) bound() guard {
+	return guard{mu: g.mu}
+}
+
+func (g guard) Lock() {
+	if g.gate != nil {
+		g.gate.RLock()
+	}
+	g.mu.Lock()
+}
+
+func (g guard) Unlock() {
+	g.mu.Unlock()
+	if g.gate != nil {
+		g.gate.RUnlock()
+	}
+}
+
+func (g guard) RLock() {
+	if g.gate != nil {
+		g.gate.RLock()
+	}
+	g.mu.RLock()
+}
+
+func (g guard) RUnlock() {
+	g.mu.RUnlock()
+	if g.gate != nil {
+		g.gate.RUnlock()
+	}
+}
+
+// UnitOfWork runs transactions over the repositories of this package. It
+// snapshots every repository before running a function and restores the
+// snapshots if the function fails. Transactions run one at a time, and the
+// repositories wait for them outside a transaction, so a rollback never
+// discards writes made alongside it.
+type UnitOfWork struct {
+	gate  sync.RWMutex
+	repos repository.Repositories
+}
+
+// NewUnitOfWork returns a unit of work over repos. Repositories that are not
+// of this package are handed to transactions as they are and not rolled back.
+func NewUnitOfWork(repos repository.Repositories) *UnitOfWork {
+	u := &UnitOfWork{repos: repos}
+	for _, repo := range members(repos) {
+		repo.join(&u.gate)
+	}
+	return u
+}
+
+func (u *UnitOfWork) Transaction(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	u.gate.Lock()
+	defer u.gate.Unlock()
+	return u.run(fn)
+}
+
+// run calls fn with handles bound to the transaction and rolls back what it
+// changed if it fails. The caller must hold u.gate.
+func (u *UnitOfWork) run(fn func(repos repository.Repositories) error) error {
+	repos := repository.Repositories{
+		Products:   bound(u.repos.Products),
+		Users:      bound(u.repos.Users),
+		Categories: bound(u.repos.Categories),
+		Inventory:  bound(u.repos.Inventory),
+		Images:     bound(u.repos.Images),
+		Prices:     bound(u.repos.Prices),
+		Reviews:    bound(u.repos.Reviews),
+		Promotions: bound(u.repos.Promotions),
+		Carts:      bound(u.repos.Carts),
+		Orders:     bound(u.repos.Orders),
+		Payments:   bound(u.repos.Payments),
+		TaxRates:   bound(u.repos.TaxRates),
+		Wishlists:  bound(u.repos.Wishlists),
+		UnitOfWork: nestedUnitOfWork{u},
+	}
+
+	var restores []func()
+	for _, repo := range members(repos) {
+		restores = append(restores, repo.snapshot())
+	}
+	if err := fn(repos); err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+		return err
+	}
+	return nil
+}
+
+// members returns the repositories of repos that are of this package.
+func members(repos repository.Repositories) []member {
+	var found []member
+	for _, repo := range []interface{}{
+		repos.Products, repos.Users, repos.Categories, repos.Inventory,
+		repos.Images, repos.Prices, repos.Reviews, repos.Promotions,
+		repos.Carts, repos.Orders, repos.Payments, repos.TaxRates,
+		repos.Wishlists,
+	} {
+		if m, ok := repo.(member); ok {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// bound returns the handle of repo for a transaction, or repo itself when it
+// is not of this package.
+func bound[T any](repo T) T {
+	if m, ok := any(repo).(member); ok {
+		return m.bind().(T)
+	}
+	return repo
+}
+
+// nestedUnitOfWork runs transactions inside a transaction of its UnitOfWork,
+// which already holds the gate.
+type nestedUnitOfWork struct {
+	parent *UnitOfWork
+}
+
+func (u nestedUnitOfWork) Transaction(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return u.parent.run(fn)
+}
+
+// cloneMap copies m, copying each value with clone.
+func cloneMap[K comparable, V any](m map[K]V, clone func(V) V) map[K]V {
+	copied := make(map[K]V, len(m))
+	for key, value := range m {
+		copied[key] = clone(value)
+	}
+	return copied
+}
+
+// copyOf returns a pointer to a copy of *value.
+func copyOf[T any](value *T) *T {
+	copied := *value
+	return &copied
+}
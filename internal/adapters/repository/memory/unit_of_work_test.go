@@ -0,0 +1,250 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+func TestRollbackKeepsOutsideWrites(t *testing.T) {
+	ctx := context.Background()
+	carts := NewCartRepository()
+	uow := NewUnitOfWork(repository.Repositories{Carts: carts})
+	inside, outside := uint(1), uint(2)
+	errFailed := errors.New("failed")
+
+	written := make(chan error, 1)
+	err := uow.Transaction(ctx, func(repos repository.Repositories) error {
+		if err := repos.Carts.Create(ctx, &domain.Cart{UserID: &inside}); err != nil {
+			return err
+		}
+		go func() {
+			written <- carts.Create(ctx, &domain.Cart{UserID: &outside})
+		}()
+		// Give the outside write time to run; it has to wait for the rollback
+		time.Sleep(20 * time.Millisecond)
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("Transaction() error = %v, want %v", err, errFailed)
+	}
+	if err := <-written; err != nil {
+		t.Fatalf("outside Create() error = %v", err)
+	}
+
+	if cart, err := carts.GetByUser(ctx, inside); err != nil || cart != nil {
+		t.Errorf("cart written in the transaction = %+v, %v, want rolled back", cart, err)
+	}
+	if cart, err := carts.GetByUser(ctx, outside); err != nil || cart == nil {
+		t.Errorf("cart written outside the transaction = %+v, %v, want kept", cart, err)
+	}
+}
+
+func TestNestedRollback(t *testing.T) {
+	ctx := context.Background()
+	carts := NewCartRepository()
+	uow := NewUnitOfWork(repository.Repositories{Carts: carts})
+	outer, inner := uint(1), uint(2)
+
+	err := uow.Transaction(ctx, func(repos repository.Repositories) error {
+		if err := repos.Carts.Create(ctx, &domain.Cart{UserID: &outer}); err != nil {
+			return err
+		}
+		nested := repos.UnitOfWork.Transaction(ctx, func(repos repository.Repositories) error {
+			if err := repos.Carts.Create(ctx, &domain.Cart{UserID: &inner}); err != nil {
+				return err
+			}
+			return errors.New("failed")
+		})
+		if nested == nil {
+			t.Error("nested Transaction() succeeded")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction() error = %v", err)
+	}
+
+	if cart, _ := carts.GetByUser(ctx, outer); cart == nil {
+		t.Error("outer cart rolled back")
+	}
+	if cart, _ := carts.GetByUser(ctx, inner); cart != nil {
+		t.Error("inner cart kept")
+	}
+}
+
+// stockedRepositories returns repositories holding a 20.00 tee with 10 units
+// in stock.
+func stockedRepositories(t *testing.T) (repository.Repositories, *domain.Product) {
+	t.Helper()
+	ctx := context.Background()
+	repos := repository.Repositories{
+		Products:  NewProductRepository(),
+		Inventory: NewInventoryRepository(),
+		Orders:    NewOrderRepository(),
+	}
+	product := &domain.Product{SKU: "TEE-1", Slug: "tee", Name: "Tee", Price: 20}
+	if err := repos.Products.Create(ctx, product); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repos.Inventory.Change(ctx, product.ID, "", addStock(10)); err != nil {
+		t.Fatal(err)
+	}
+	return repos, product
+}
+
+func addStock(units int) repository.StockChange {
+	return func(level *domain.StockLevel) (*domain.StockMovement, error) {
+		level.OnHand += units
+		return &domain.StockMovement{OnHandDelta: units}, nil
+	}
+}
+
+// sell updates the price, takes two units of stock and creates an order,
+// everything a transaction can touch in these repositories.
+func sell(ctx context.Context, repos repository.Repositories, productID uint) error {
+	product, err := repos.Products.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	product.Price = 25
+	if err := repos.Products.Update(ctx, product); err != nil {
+		return err
+	}
+	if _, err := repos.Inventory.Change(ctx, productID, "", addStock(-2)); err != nil {
+		return err
+	}
+	return repos.Orders.Create(ctx, &domain.Order{Number: "ORD-1", UserID: 1, Status: domain.OrderPending})
+}
+
+func TestTransaction(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name      string
+		err       error
+		wantPrice float64
+		wantStock int
+		wantOrder bool
+	}{
+		{"commit keeps every change", nil, 25, 8, true},
+		{"rollback undoes every change", errFailed, 20, 10, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repos, product := stockedRepositories(t)
+			uow := NewUnitOfWork(repos)
+
+			err := uow.Transaction(ctx, func(repos repository.Repositories) error {
+				if err := sell(ctx, repos, product.ID); err != nil {
+					return err
+				}
+				return tt.err
+			})
+			if err != tt.err {
+				t.Fatalf("Transaction() error = %v, want %v", err, tt.err)
+			}
+
+			stored, err := repos.Products.GetByID(ctx, product.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Price != tt.wantPrice {
+				t.Errorf("price = %v, want %v", stored.Price, tt.wantPrice)
+			}
+			level, err := repos.Inventory.GetStock(ctx, product.ID, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if level.OnHand != tt.wantStock {
+				t.Errorf("stock = %d, want %d", level.OnHand, tt.wantStock)
+			}
+			orders, err := repos.Orders.GetByUser(ctx, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (len(orders) == 1) != tt.wantOrder {
+				t.Errorf("orders = %d, want order %v", len(orders), tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestRollbackRestoresIDs(t *testing.T) {
+	ctx := context.Background()
+	products := NewProductRepository()
+	uow := NewUnitOfWork(repository.Repositories{Products: products})
+
+	err := uow.Transaction(ctx, func(repos repository.Repositories) error {
+		if err := repos.Products.Create(ctx, &domain.Product{SKU: "TEE-1", Slug: "tee", Name: "Tee", Price: 20}); err != nil {
+			return err
+		}
+		return errors.New("failed")
+	})
+	if err == nil {
+		t.Fatal("Transaction() succeeded")
+	}
+
+	product := &domain.Product{SKU: "TEE-1", Slug: "tee", Name: "Tee", Price: 20}
+	if err := products.Create(ctx, product); err != nil {
+		t.Fatalf("Create() after rollback error = %v", err)
+	}
+	if product.ID != 1 {
+		t.Errorf("ID after rollback = %d, want 1", product.ID)
+	}
+}
+
+func TestTransactionsAreSerialized(t *testing.T) {
+	ctx := context.Background()
+	repos, product := stockedRepositories(t)
+	uow := NewUnitOfWork(repos)
+
+	// Read, pause and write back: concurrent transactions would lose updates
+	const n = 20
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			errs <- uow.Transaction(ctx, func(repos repository.Repositories) error {
+				stored, err := repos.Products.GetByID(ctx, product.ID)
+				if err != nil {
+					return err
+				}
+				time.Sleep(time.Millisecond)
+				stored.Price++
+				return repos.Products.Update(ctx, stored)
+			})
+		}()
+	}
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("Transaction() error = %v", err)
+		}
+	}
+
+	stored, err := repos.Products.GetByID(ctx, product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Price != 20+n {
+		t.Errorf("price = %v, want %v", stored.Price, 20+n)
+	}
+}
+
+func TestTransactionCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	uow := NewUnitOfWork(repository.Repositories{Carts: NewCartRepository()})
+
+	called := false
+	err := uow.Transaction(ctx, func(repos repository.Repositories) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || called {
+		t.Errorf("Transaction() error = %v, called = %v, want %v without calling", err, called, context.Canceled)
+	}
+}
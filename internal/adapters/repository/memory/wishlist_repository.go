@@ -0,0 +1,270 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+type WishlistRepository struct {
+	guard
+	*wishlistData
+}
+
+type wishlistData struct {
+	wishlists  map[uint]*domain.Wishlist
+	nextID     uint
+	nextItemID uint
+}
+
+func NewWishlistRepository() *WishlistRepository {
+	return &WishlistRepository{
+		guard: newGuard(),
+		wishlistData: &wishlistData{
+			wishlists:  make(map[uint]*domain.Wishlist),
+			nextID:     1,
+			nextItemID: 1,
+		},
+	}
+}
+
+func (r *WishlistRepository) Create(ctx context.Context, wishlist *domain.Wishlist) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	for _, existing := range r.wishlists {
+		if existing.ShareToken == wishlist.ShareToken {
+			return repository.ErrDuplicateKey
+		}
+	}
+
+	now := time.Now()
+	wishlist.ID = r.nextID
+	wishlist.CreatedAt = now
+	wishlist.UpdatedAt = now
+	stored := cloneWishlist(wishlist)
+	stored.Items = []domain.WishlistItem{}
+	r.wishlists[wishlist.ID] = stored
+	r.nextID++
+	return nil
+}
+
+func (r *WishlistRepository) GetByID(ctx context.Context, id uint) (*domain.Wishlist, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	if wishlist, exists := r.wishlists[id]; exists {
+		return cloneWishlist(wishlist), nil
+	}
+	return nil, nil
+}
+
+func (r *WishlistRepository) GetByShareToken(ctx context.Context, token string) (*domain.Wishlist, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, wishlist := range r.wishlists {
+		if wishlist.ShareToken == token {
+			return cloneWishlist(wishlist), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *WishlistRepository) GetByUser(ctx context.Context, userID uint) ([]domain.Wishlist, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	wishlists := make([]domain.Wishlist, 0)
+	for _, wishlist := range r.wishlists {
+		if wishlist.UserID == userID {
+			wishlists = append(wishlists, *cloneWishlist(wishlist))
+		}
+	}
+	sort.Slice(wishlists, func(i, j int) bool {
+		return wishlists[i].ID < wishlists[j].ID
+	})
+	return wishlists, nil
+}
+
+func (r *WishlistRepository) CountByUser(ctx context.Context, userID uint) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	var count int64
+	for _, wishlist := range r.wishlists {
+		if wishlist.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *WishlistRepository) Update(ctx context.Context, wishlist *domain.Wishlist) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	existing, exists := r.wishlists[wishlist.ID]
+	if !exists {
+		return errors.New("wishlist not found")
+	}
+	for id, other := range r.wishlists {
+		if id != wishlist.ID && other.ShareToken == wishlist.ShareToken {
+			return repository.ErrDuplicateKey
+		}
+	}
+
+	existing.Name = wishlist.Name
+	existing.Public = wishlist.Public
+	existing.ShareToken = wishlist.ShareToken
+	existing.UpdatedAt = time.Now()
+	wishlist.UpdatedAt = existing.UpdatedAt
+	return nil
+}
+
+func (r *WishlistRepository) Delete(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, exists := r.wishlists[id]; !exists {
+		return errors.New("wishlist not found")
+	}
+	delete(r.wishlists, id)
+	return nil
+}
+
+func (r *WishlistRepository) AddItem(ctx context.Context, item *domain.WishlistItem) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	wishlist, exists := r.wishlists[item.WishlistID]
+	if !exists {
+		return errors.New("wishlist not found")
+	}
+	for _, existing := range wishlist.Items {
+		if existing.ProductID == item.ProductID {
+			return repository.ErrDuplicateKey
+		}
+	}
+
+	item.ID = r.nextItemID
+	r.nextItemID++
+	stored := *item
+	stored.Product = nil
+	wishlist.Items = append(wishlist.Items, stored)
+	wishlist.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *WishlistRepository) RemoveItem(ctx context.Context, wishlistID, productID uint) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	wishlist, exists := r.wishlists[wishlistID]
+	if !exists {
+		return false, nil
+	}
+	for i, item := range wishlist.Items {
+		if item.ProductID == productID {
+			wishlist.Items = append(wishlist.Items[:i:i], wishlist.Items[i+1:]...)
+			wishlist.UpdatedAt = time.Now()
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *WishlistRepository) GetSavedProductIDs(ctx context.Context, userID uint, productIDs []uint) ([]uint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	wanted := make(map[uint]struct{}, len(productIDs))
+	for _, id := range productIDs {
+		wanted[id] = struct{}{}
+	}
+	saved := make(map[uint]struct{})
+	for _, wishlist := range r.wishlists {
+		if wishlist.UserID != userID {
+			continue
+		}
+		for _, item := range wishlist.Items {
+			if _, ok := wanted[item.ProductID]; ok {
+				saved[item.ProductID] = struct{}{}
+			}
+		}
+	}
+
+	ids := make([]uint, 0, len(saved))
+	for id := range saved {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (r *WishlistRepository) bind() interface{} {
+	return &WishlistRepository{guard: r.guard.bound(), wishlistData: r.wishlistData}
+}
+
+func (r *WishlistRepository) snapshot() func() {
+	r.RLock()
+	wishlists := cloneMap(r.wishlists, cloneWishlist)
+	nextID, nextItemID := r.nextID, r.nextItemID
+	r.RUnlock()
+
+	return func() {
+		r.Lock()
+		r.wishlists, r.nextID, r.nextItemID = wishlists, nextID, nextItemID
+		r.Unlock()
+	}
+}
+
+func cloneWishlist(wishlist *domain.Wishlist) *domain.Wishlist {
+	clone := *wishlist
+	clone.Items = append([]domain.WishlistItem{}, wishlist.Items...)
+	return &clone
+}
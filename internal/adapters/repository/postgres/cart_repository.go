@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CartRepository struct {
+	db *gorm.DB
+}
+
+func NewCartRepository(db *gorm.DB) *CartRepository {
+	return &CartRepository{db: db}
+}
+
+func (r *CartRepository) Create(ctx context.Context, cart *domain.Cart) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(cart).Error; err != nil {
+			return err
+		}
+		return r.saveItems(tx, cart)
+	})
+	if err != nil {
+		return fmt.Errorf("error creating cart: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *CartRepository) GetByUser(ctx context.Context, userID uint) (*domain.Cart, error) {
+	return r.getBy(ctx, r.db, "user_id = ?", userID)
+}
+
+func (r *CartRepository) LockByUser(ctx context.Context, userID uint) (*domain.Cart, error) {
+	return r.getBy(ctx, r.db.Clauses(clause.Locking{Strength: "UPDATE"}), "user_id = ?", userID)
+}
+
+func (r *CartRepository) GetByToken(ctx context.Context, token string) (*domain.Cart, error) {
+	return r.getBy(ctx, r.db, "token = ?", token)
+}
+
+func (r *CartRepository) getBy(ctx context.Context, db *gorm.DB, query string, args ...interface{}) (*domain.Cart, error) {
+	var cart domain.Cart
+	result := db.WithContext(ctx).Where(query, args...).First(&cart)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting cart: %w", result.Error)
+	}
+
+	if err := r.db.WithContext(ctx).Where("cart_id = ?", cart.ID).Order("id").Find(&cart.Items).Error; err != nil {
+		return nil, fmt.Errorf("error getting cart items: %w", err)
+	}
+	return &cart, nil
+}
+
+func (r *CartRepository) Save(ctx context.Context, cart *domain.Cart) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(cart).Update("updated_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("cart not found")
+		}
+		return r.saveItems(tx, cart)
+	})
+	if err != nil {
+		return fmt.Errorf("error saving cart: %w", err)
+	}
+	return nil
+}
+
+func (r *CartRepository) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cart_id = ?", id).Delete(&domain.CartItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&domain.Cart{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("cart not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting cart: %w", err)
+	}
+	return nil
+}
+
+func (r *CartRepository) DeleteGuestCarts(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		stale := tx.Model(&domain.Cart{}).Select("id").Where("user_id IS NULL AND updated_at < ?", before)
+		if err := tx.Where("cart_id IN (?)", stale).Delete(&domain.CartItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("user_id IS NULL AND updated_at < ?", before).Delete(&domain.Cart{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error deleting guest carts: %w", err)
+	}
+	return deleted, nil
+}
+
+// saveItems updates the items of a cart that already have an ID, creates the
+// new ones and deletes those no longer listed.
+func (r *CartRepository) saveItems(tx *gorm.DB, cart *domain.Cart) error {
+	keep := make([]uint, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		if item.ID != 0 {
+			keep = append(keep, item.ID)
+		}
+	}
+
+	stale := tx.Where("cart_id = ?", cart.ID)
+	if len(keep) > 0 {
+		stale = stale.Where("id NOT IN ?", keep)
+	}
+	if err := stale.Delete(&domain.CartItem{}).Error; err != nil {
+		return err
+	}
+
+	for i := range cart.Items {
+		cart.Items[i].CartID = cart.ID
+		if err := tx.Save(&cart.Items[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
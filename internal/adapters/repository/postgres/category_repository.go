@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"gorm.io/gorm"
+)
+
+// categoryClosure stores every ancestor/descendant pair of the category tree,
+// including a zero-depth row for each category pointing at itself.
+type categoryClosure struct {
+	AncestorID   uint `gorm:"primaryKey;autoIncrement:false"`
+	DescendantID uint `gorm:"primaryKey;autoIncrement:false;index"`
+	Depth        int  `gorm:"not null"`
+}
+
+func (categoryClosure) TableName() string {
+	return "category_closures"
+}
+
+type productCategory struct {
+	ProductID  uint `gorm:"primaryKey;autoIncrement:false"`
+	CategoryID uint `gorm:"primaryKey;autoIncrement:false;index"`
+}
+
+func (productCategory) TableName() string {
+	return "product_categories"
+}
+
+type CategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(category).Error; err != nil {
+			return err
+		}
+		return r.linkAncestors(tx, category)
+	})
+	if err != nil {
+		return fmt.Errorf("error creating category: %w", err)
+	}
+	return nil
+}
+
+func (r *CategoryRepository) GetByID(ctx context.Context, id uint) (*domain.Category, error) {
+	var category domain.Category
+	result := r.db.WithContext(ctx).First(&category, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting category: %w", result.Error)
+	}
+	return &category, nil
+}
+
+func (r *CategoryRepository) GetAll(ctx context.Context) ([]domain.Category, error) {
+	var categories []domain.Category
+	result := r.db.WithContext(ctx).Order("id").Find(&categories)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting categories: %w", result.Error)
+	}
+	return categories, nil
+}
+
+func (r *CategoryRepository) GetChildren(ctx context.Context, id uint) ([]domain.Category, error) {
+	var categories []domain.Category
+	result := r.db.WithContext(ctx).Where("parent_id = ?", id).Order("id").Find(&categories)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting categories: %w", result.Error)
+	}
+	return categories, nil
+}
+
+func (r *CategoryRepository) GetDescendantIDs(ctx context.Context, id uint) ([]uint, error) {
+	var ids []uint
+	result := r.db.WithContext(ctx).Model(&categoryClosure{}).
+		Where("ancestor_id = ?", id).
+		Order("depth, descendant_id").
+		Pluck("descendant_id", &ids)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting category descendants: %w", result.Error)
+	}
+	return ids, nil
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Save(category)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("category not found")
+		}
+
+		// Detach the subtree from its old ancestors and attach it below the new parent
+		subtree := tx.Model(&categoryClosure{}).Select("descendant_id").Where("ancestor_id = ?", category.ID)
+		err := tx.Where("descendant_id IN (?) AND ancestor_id NOT IN (?)", subtree, subtree).
+			Delete(&categoryClosure{}).Error
+		if err != nil {
+			return err
+		}
+		if category.ParentID == nil {
+			return nil
+		}
+		return tx.Exec(`INSERT INTO category_closures (ancestor_id, descendant_id, depth)
+			SELECT super.ancestor_id, sub.descendant_id, super.depth + sub.depth + 1
+			FROM category_closures super
+			CROSS JOIN category_closures sub
+			WHERE super.descendant_id = ? AND sub.ancestor_id = ?`,
+			*category.ParentID, category.ID).Error
+	})
+	if err != nil {
+		return fmt.Errorf("error updating category: %w", err)
+	}
+	return nil
+}
+
+func (r *CategoryRepository) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", id).Delete(&productCategory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ancestor_id = ? OR descendant_id = ?", id, id).Delete(&categoryClosure{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&domain.Category{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("category not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting category: %w", err)
+	}
+	return nil
+}
+
+func (r *CategoryRepository) SetProductCategories(ctx context.Context, productID uint, categoryIDs []uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&productCategory{}).Error; err != nil {
+			return err
+		}
+		if len(categoryIDs) == 0 {
+			return nil
+		}
+		links := make([]productCategory, 0, len(categoryIDs))
+		for _, categoryID := range categoryIDs {
+			links = append(links, productCategory{ProductID: productID, CategoryID: categoryID})
+		}
+		return tx.Create(&links).Error
+	})
+	if err != nil {
+		return fmt.Errorf("error setting product categories: %w", err)
+	}
+	return nil
+}
+
+func (r *CategoryRepository) GetProductCategories(ctx context.Context, productID uint) ([]domain.Category, error) {
+	var categories []domain.Category
+	result := r.db.WithContext(ctx).
+		Joins("JOIN product_categories ON product_categories.category_id = categories.id").
+		Where("product_categories.product_id = ?", productID).
+		Order("categories.id").
+		Find(&categories)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting product categories: %w", result.Error)
+	}
+	return categories, nil
+}
+
+func (r *CategoryRepository) GetProductIDs(ctx context.Context, categoryIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(categoryIDs) == 0 {
+		return ids, nil
+	}
+	result := r.db.WithContext(ctx).Model(&productCategory{}).
+		Distinct("product_id").
+		Where("category_id IN ?", categoryIDs).
+		Order("product_id").
+		Pluck("product_id", &ids)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting category products: %w", result.Error)
+	}
+	return ids, nil
+}
+
+func (r *CategoryRepository) GetCategoryIDsByProducts(ctx context.Context, productIDs []uint) (map[uint][]uint, error) {
+	categoryIDs := make(map[uint][]uint, len(productIDs))
+	if len(productIDs) == 0 {
+		return categoryIDs, nil
+	}
+
+	var links []productCategory
+	result := r.db.WithContext(ctx).Where("product_id IN ?", productIDs).Order("product_id, category_id").Find(&links)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting product categories: %w", result.Error)
+	}
+	for _, link := range links {
+		categoryIDs[link.ProductID] = append(categoryIDs[link.ProductID], link.CategoryID)
+	}
+	return categoryIDs, nil
+}
+
+// linkAncestors inserts the closure rows for a newly created category.
+func (r *CategoryRepository) linkAncestors(tx *gorm.DB, category *domain.Category) error {
+	if err := tx.Create(&categoryClosure{AncestorID: category.ID, DescendantID: category.ID}).Error; err != nil {
+		return err
+	}
+	if category.ParentID == nil {
+		return nil
+	}
+	return tx.Exec(`INSERT INTO category_closures (ancestor_id, descendant_id, depth)
+		SELECT ancestor_id, ?, depth + 1 FROM category_closures WHERE descendant_id = ?`,
+		category.ID, *category.ParentID).Error
+}
@@ -0,0 +1,17 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/euro1061/gohex/internal/ports/repository"
+	"gorm.io/gorm"
+)
+
+// translateError converts GORM errors into the errors declared by the repository ports.
+// It relies on the connection being opened with gorm.Config.TranslateError.
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return repository.ErrDuplicateKey
+	}
+	return err
+}
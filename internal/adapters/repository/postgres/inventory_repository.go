@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) *InventoryRepository {
+	return &InventoryRepository{db: db}
+}
+
+func (r *InventoryRepository) GetStock(ctx context.Context, productID uint, warehouse string) (*domain.StockLevel, error) {
+	var level domain.StockLevel
+	result := r.db.WithContext(ctx).Where("product_id = ? AND warehouse = ?", productID, warehouse).First(&level)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting stock level: %w", result.Error)
+	}
+	return &level, nil
+}
+
+func (r *InventoryRepository) GetProductStock(ctx context.Context, productID uint) ([]domain.StockLevel, error) {
+	var levels []domain.StockLevel
+	result := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("warehouse").Find(&levels)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting stock levels: %w", result.Error)
+	}
+	return levels, nil
+}
+
+func (r *InventoryRepository) GetLowStock(ctx context.Context) ([]domain.StockLevel, error) {
+	var levels []domain.StockLevel
+	result := r.db.WithContext(ctx).Where("on_hand - reserved <= low_stock_threshold").
+		Order("product_id, warehouse").
+		Find(&levels)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting low stock levels: %w", result.Error)
+	}
+	return levels, nil
+}
+
+func (r *InventoryRepository) GetMovements(ctx context.Context, productID uint, limit int) ([]domain.StockMovement, error) {
+	var movements []domain.StockMovement
+	result := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("id DESC").Limit(limit).Find(&movements)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting stock movements: %w", result.Error)
+	}
+	return movements, nil
+}
+
+func (r *InventoryRepository) Change(ctx context.Context, productID uint, warehouse string, change repository.StockChange) (*domain.StockLevel, error) {
+	var level domain.StockLevel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Make sure the row exists, then lock it for the rest of the transaction
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&domain.StockLevel{ProductID: productID, Warehouse: warehouse}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ? AND warehouse = ?", productID, warehouse).
+			First(&level).Error
+		if err != nil {
+			return err
+		}
+
+		movement, err := change(&level)
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(&level).Error; err != nil {
+			return err
+		}
+		if movement == nil {
+			return nil
+		}
+		movement.ProductID = productID
+		movement.Warehouse = warehouse
+		return tx.Create(movement).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error changing stock level: %w", err)
+	}
+	return &level, nil
+}
@@ -0,0 +1,3 @@
+-- The adopted columns and the filled in SKUs and slugs are kept: the tables
+-- they belong to predate migrations, and removing them would lose data.
+SELECT 1;
@@ -0,0 +1,382 @@
+-- Adopts databases created by the AutoMigrate calls of earlier versions,
+-- which only ever created the tables and columns of the release that ran
+-- them. Every column of 0002_initial_schema that such a table may lack is
+-- added, and the product SKUs and slugs the application now requires are
+-- filled in, so 0002 can create its unique indexes. On a new database none
+-- of the tables exist yet and nothing happens.
+--
+-- NOT NULL columns get a default for the rows that already exist, which is
+-- then dropped to match the model.
+
+ALTER TABLE IF EXISTS "users"
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "updated_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "deleted_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "name" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "username" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "password" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "gender" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "email" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "role" text NOT NULL DEFAULT 'customer',
+    ADD COLUMN IF NOT EXISTS "last_login_at" timestamptz,
+    ALTER COLUMN "name" DROP DEFAULT,
+    ALTER COLUMN "username" DROP DEFAULT,
+    ALTER COLUMN "password" DROP DEFAULT,
+    ALTER COLUMN "gender" DROP DEFAULT,
+    ALTER COLUMN "email" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "products"
+    ADD COLUMN IF NOT EXISTS "sku" text,
+    ADD COLUMN IF NOT EXISTS "barcode" text,
+    ADD COLUMN IF NOT EXISTS "slug" text,
+    ADD COLUMN IF NOT EXISTS "name" text,
+    ADD COLUMN IF NOT EXISTS "description" text,
+    ADD COLUMN IF NOT EXISTS "price" decimal,
+    ADD COLUMN IF NOT EXISTS "tax_class" text NOT NULL DEFAULT 'standard',
+    ADD COLUMN IF NOT EXISTS "currency_prices" text,
+    ADD COLUMN IF NOT EXISTS "status" text NOT NULL DEFAULT 'published',
+    ADD COLUMN IF NOT EXISTS "publish_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "unpublish_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "review_note" text;
+
+ALTER TABLE IF EXISTS "product_options"
+    ADD COLUMN IF NOT EXISTS "product_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "name" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "values" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "position" bigint NOT NULL DEFAULT 0,
+    ALTER COLUMN "product_id" DROP DEFAULT,
+    ALTER COLUMN "name" DROP DEFAULT,
+    ALTER COLUMN "values" DROP DEFAULT,
+    ALTER COLUMN "position" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "product_variants"
+    ADD COLUMN IF NOT EXISTS "product_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "sku" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "price" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "stock" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "attributes" text NOT NULL DEFAULT '',
+    ALTER COLUMN "product_id" DROP DEFAULT,
+    ALTER COLUMN "sku" DROP DEFAULT,
+    ALTER COLUMN "price" DROP DEFAULT,
+    ALTER COLUMN "stock" DROP DEFAULT,
+    ALTER COLUMN "attributes" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "product_revisions"
+    ADD COLUMN IF NOT EXISTS "product_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "number" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "snapshot" jsonb NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS "actor" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "reason" text,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ALTER COLUMN "product_id" DROP DEFAULT,
+    ALTER COLUMN "number" DROP DEFAULT,
+    ALTER COLUMN "snapshot" DROP DEFAULT,
+    ALTER COLUMN "actor" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "categories"
+    ADD COLUMN IF NOT EXISTS "name" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "description" text,
+    ADD COLUMN IF NOT EXISTS "parent_id" bigint,
+    ALTER COLUMN "name" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "category_closures"
+    ADD COLUMN IF NOT EXISTS "depth" bigint NOT NULL DEFAULT 0,
+    ALTER COLUMN "depth" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "stock_levels"
+    ADD COLUMN IF NOT EXISTS "on_hand" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "reserved" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "low_stock_threshold" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "updated_at" timestamptz,
+    ALTER COLUMN "on_hand" DROP DEFAULT,
+    ALTER COLUMN "reserved" DROP DEFAULT,
+    ALTER COLUMN "low_stock_threshold" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "stock_movements"
+    ADD COLUMN IF NOT EXISTS "product_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "warehouse" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "type" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "on_hand_delta" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "reserved_delta" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "reference" text,
+    ADD COLUMN IF NOT EXISTS "reason" text,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ALTER COLUMN "product_id" DROP DEFAULT,
+    ALTER COLUMN "warehouse" DROP DEFAULT,
+    ALTER COLUMN "type" DROP DEFAULT,
+    ALTER COLUMN "on_hand_delta" DROP DEFAULT,
+    ALTER COLUMN "reserved_delta" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "product_images"
+    ADD COLUMN IF NOT EXISTS "product_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "key" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "thumbnail_key" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "url" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "thumbnail_url" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "content_type" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "size" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "width" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "height" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "position" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "is_primary" boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ALTER COLUMN "product_id" DROP DEFAULT,
+    ALTER COLUMN "key" DROP DEFAULT,
+    ALTER COLUMN "thumbnail_key" DROP DEFAULT,
+    ALTER COLUMN "url" DROP DEFAULT,
+    ALTER COLUMN "thumbnail_url" DROP DEFAULT,
+    ALTER COLUMN "content_type" DROP DEFAULT,
+    ALTER COLUMN "size" DROP DEFAULT,
+    ALTER COLUMN "width" DROP DEFAULT,
+    ALTER COLUMN "height" DROP DEFAULT,
+    ALTER COLUMN "position" DROP DEFAULT,
+    ALTER COLUMN "is_primary" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "price_changes"
+    ADD COLUMN IF NOT EXISTS "product_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "old_price" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "new_price" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "actor" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "reason" text,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ALTER COLUMN "product_id" DROP DEFAULT,
+    ALTER COLUMN "old_price" DROP DEFAULT,
+    ALTER COLUMN "new_price" DROP DEFAULT,
+    ALTER COLUMN "actor" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "price_schedules"
+    ADD COLUMN IF NOT EXISTS "product_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "price" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "starts_at" timestamptz NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS "ends_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "reason" text,
+    ADD COLUMN IF NOT EXISTS "actor" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "status" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "original_price" decimal,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "updated_at" timestamptz,
+    ALTER COLUMN "product_id" DROP DEFAULT,
+    ALTER COLUMN "price" DROP DEFAULT,
+    ALTER COLUMN "starts_at" DROP DEFAULT,
+    ALTER COLUMN "actor" DROP DEFAULT,
+    ALTER COLUMN "status" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "reviews"
+    ADD COLUMN IF NOT EXISTS "product_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "user_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "author" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "rating" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "title" text,
+    ADD COLUMN IF NOT EXISTS "body" text,
+    ADD COLUMN IF NOT EXISTS "status" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "moderation_note" text,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "updated_at" timestamptz,
+    ALTER COLUMN "product_id" DROP DEFAULT,
+    ALTER COLUMN "user_id" DROP DEFAULT,
+    ALTER COLUMN "author" DROP DEFAULT,
+    ALTER COLUMN "rating" DROP DEFAULT,
+    ALTER COLUMN "status" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "promotions"
+    ADD COLUMN IF NOT EXISTS "name" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "type" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "value" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "buy_quantity" bigint,
+    ADD COLUMN IF NOT EXISTS "get_quantity" bigint,
+    ADD COLUMN IF NOT EXISTS "code" text,
+    ADD COLUMN IF NOT EXISTS "usage_limit" bigint,
+    ADD COLUMN IF NOT EXISTS "per_user_limit" bigint,
+    ADD COLUMN IF NOT EXISTS "usage_count" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "starts_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "ends_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "active" boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS "priority" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "product_ids" text,
+    ADD COLUMN IF NOT EXISTS "category_ids" text,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "updated_at" timestamptz,
+    ALTER COLUMN "name" DROP DEFAULT,
+    ALTER COLUMN "type" DROP DEFAULT,
+    ALTER COLUMN "value" DROP DEFAULT,
+    ALTER COLUMN "active" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "promotion_redemptions"
+    ADD COLUMN IF NOT EXISTS "promotion_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "user_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "reference" text,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ALTER COLUMN "promotion_id" DROP DEFAULT,
+    ALTER COLUMN "user_id" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "carts"
+    ADD COLUMN IF NOT EXISTS "user_id" bigint,
+    ADD COLUMN IF NOT EXISTS "token" text,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "updated_at" timestamptz;
+
+ALTER TABLE IF EXISTS "cart_items"
+    ADD COLUMN IF NOT EXISTS "cart_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "product_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "variant_id" bigint,
+    ADD COLUMN IF NOT EXISTS "sku" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "name" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "quantity" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "unit_price" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "added_at" timestamptz,
+    ALTER COLUMN "cart_id" DROP DEFAULT,
+    ALTER COLUMN "product_id" DROP DEFAULT,
+    ALTER COLUMN "sku" DROP DEFAULT,
+    ALTER COLUMN "name" DROP DEFAULT,
+    ALTER COLUMN "quantity" DROP DEFAULT,
+    ALTER COLUMN "unit_price" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "orders"
+    ADD COLUMN IF NOT EXISTS "number" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "user_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "status" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "coupon" text,
+    ADD COLUMN IF NOT EXISTS "promotions" text,
+    ADD COLUMN IF NOT EXISTS "subtotal" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "discount" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "tax" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "total" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "tax_country" text,
+    ADD COLUMN IF NOT EXISTS "tax_region" text,
+    ADD COLUMN IF NOT EXISTS "prices_include_tax" boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS "currency" varchar(3),
+    ADD COLUMN IF NOT EXISTS "base_currency" varchar(3),
+    ADD COLUMN IF NOT EXISTS "exchange_rate" decimal NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "updated_at" timestamptz,
+    ALTER COLUMN "number" DROP DEFAULT,
+    ALTER COLUMN "user_id" DROP DEFAULT,
+    ALTER COLUMN "status" DROP DEFAULT,
+    ALTER COLUMN "subtotal" DROP DEFAULT,
+    ALTER COLUMN "discount" DROP DEFAULT,
+    ALTER COLUMN "total" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "order_items"
+    ADD COLUMN IF NOT EXISTS "order_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "product_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "variant_id" bigint,
+    ADD COLUMN IF NOT EXISTS "sku" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "name" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "quantity" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "unit_price" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "subtotal" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "discount" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "tax_rate" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "tax" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "total" decimal NOT NULL DEFAULT 0,
+    ALTER COLUMN "order_id" DROP DEFAULT,
+    ALTER COLUMN "product_id" DROP DEFAULT,
+    ALTER COLUMN "sku" DROP DEFAULT,
+    ALTER COLUMN "name" DROP DEFAULT,
+    ALTER COLUMN "quantity" DROP DEFAULT,
+    ALTER COLUMN "unit_price" DROP DEFAULT,
+    ALTER COLUMN "subtotal" DROP DEFAULT,
+    ALTER COLUMN "discount" DROP DEFAULT,
+    ALTER COLUMN "total" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "order_events"
+    ADD COLUMN IF NOT EXISTS "order_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "from" text,
+    ADD COLUMN IF NOT EXISTS "to" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "actor" text,
+    ADD COLUMN IF NOT EXISTS "note" text,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ALTER COLUMN "order_id" DROP DEFAULT,
+    ALTER COLUMN "to" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "payments"
+    ADD COLUMN IF NOT EXISTS "order_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "intent_id" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "amount" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "currency" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "status" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "updated_at" timestamptz,
+    ALTER COLUMN "order_id" DROP DEFAULT,
+    ALTER COLUMN "intent_id" DROP DEFAULT,
+    ALTER COLUMN "amount" DROP DEFAULT,
+    ALTER COLUMN "currency" DROP DEFAULT,
+    ALTER COLUMN "status" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "payment_events"
+    ADD COLUMN IF NOT EXISTS "type" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "intent_id" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ALTER COLUMN "type" DROP DEFAULT,
+    ALTER COLUMN "intent_id" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "tax_rates"
+    ADD COLUMN IF NOT EXISTS "country" varchar(2) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "region" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "tax_class" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "name" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "rate" decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "updated_at" timestamptz,
+    ALTER COLUMN "country" DROP DEFAULT,
+    ALTER COLUMN "region" DROP DEFAULT,
+    ALTER COLUMN "tax_class" DROP DEFAULT,
+    ALTER COLUMN "name" DROP DEFAULT,
+    ALTER COLUMN "rate" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "wishlists"
+    ADD COLUMN IF NOT EXISTS "user_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "name" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "public" boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS "share_token" text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "updated_at" timestamptz,
+    ALTER COLUMN "user_id" DROP DEFAULT,
+    ALTER COLUMN "name" DROP DEFAULT,
+    ALTER COLUMN "share_token" DROP DEFAULT;
+
+ALTER TABLE IF EXISTS "wishlist_items"
+    ADD COLUMN IF NOT EXISTS "wishlist_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "product_id" bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "added_at" timestamptz,
+    ALTER COLUMN "wishlist_id" DROP DEFAULT,
+    ALTER COLUMN "product_id" DROP DEFAULT;
+
+-- Products created before SKUs and slugs: the SKU becomes PRODUCT-<id> and
+-- the slug is made from the name much as the application makes it, with
+-- -2, -3 and so on appended until it is free.
+DO $$
+DECLARE
+    product RECORD;
+    base text;
+    candidate text;
+    suffix int;
+BEGIN
+    IF to_regclass('products') IS NULL THEN
+        RETURN;
+    END IF;
+
+    FOR product IN SELECT "id", "name" FROM "products" WHERE "sku" IS NULL OR "sku" = '' ORDER BY "id" LOOP
+        candidate := 'PRODUCT-' || product."id";
+        suffix := 2;
+        WHILE EXISTS (SELECT 1 FROM "products" WHERE "sku" = candidate) LOOP
+            candidate := 'PRODUCT-' || product."id" || '-' || suffix;
+            suffix := suffix + 1;
+        END LOOP;
+        UPDATE "products" SET "sku" = candidate WHERE "id" = product."id";
+    END LOOP;
+
+    FOR product IN SELECT "id", "name", "sku" FROM "products" WHERE "slug" IS NULL OR "slug" = '' ORDER BY "id" LOOP
+        base := left(trim(BOTH '-' FROM regexp_replace(lower(coalesce(product."name", '')), '[^[:alnum:]]+', '-', 'g')), 100);
+        IF base = '' THEN
+            base := left(trim(BOTH '-' FROM regexp_replace(lower(product."sku"), '[^[:alnum:]]+', '-', 'g')), 100);
+        END IF;
+        candidate := base;
+        suffix := 2;
+        WHILE EXISTS (SELECT 1 FROM "products" WHERE "slug" = candidate) LOOP
+            candidate := base || '-' || suffix;
+            suffix := suffix + 1;
+        END LOOP;
+        UPDATE "products" SET "slug" = candidate WHERE "id" = product."id";
+    END LOOP;
+END $$;
@@ -0,0 +1,6 @@
+-- Undoing the initial schema would drop every table and all the data in it,
+-- so it is refused. To start over, drop and recreate the database instead.
+DO $$
+BEGIN
+    RAISE EXCEPTION 'refusing to drop the initial schema: this would delete all data; drop and recreate the database instead';
+END $$;
@@ -0,0 +1,372 @@
+-- The schema as created by the AutoMigrate calls of earlier versions. IF NOT
+-- EXISTS skips what databases created that way already have, once
+-- 0001_adopt_automigrate_schema added their missing columns.
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text NOT NULL,
+    "username" text NOT NULL,
+    "password" text NOT NULL,
+    "gender" text NOT NULL,
+    "email" text NOT NULL,
+    "role" text NOT NULL DEFAULT 'customer',
+    "last_login_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_username" ON "users" ("username");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "products" (
+    "id" bigserial,
+    "sku" text,
+    "barcode" text,
+    "slug" text,
+    "name" text,
+    "description" text,
+    "price" decimal,
+    "tax_class" text NOT NULL DEFAULT 'standard',
+    "currency_prices" text,
+    "status" text NOT NULL DEFAULT 'published',
+    "publish_at" timestamptz,
+    "unpublish_at" timestamptz,
+    "review_note" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_products_status" ON "products" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_products_slug" ON "products" ("slug");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_products_barcode" ON "products" ("barcode");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_products_sku" ON "products" ("sku");
+
+CREATE TABLE IF NOT EXISTS "product_tags" (
+    "product_id" bigint,
+    "tag" text,
+    PRIMARY KEY ("product_id", "tag")
+);
+CREATE INDEX IF NOT EXISTS "idx_product_tags_tag" ON "product_tags" ("tag");
+
+CREATE TABLE IF NOT EXISTS "product_options" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "values" text NOT NULL,
+    "position" bigint NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_product_options_product_id" ON "product_options" ("product_id");
+
+CREATE TABLE IF NOT EXISTS "product_variants" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "sku" text NOT NULL,
+    "price" decimal NOT NULL,
+    "stock" bigint NOT NULL,
+    "attributes" text NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_product_variants_sku" ON "product_variants" ("sku");
+CREATE INDEX IF NOT EXISTS "idx_product_variants_product_id" ON "product_variants" ("product_id");
+
+CREATE TABLE IF NOT EXISTS "product_revisions" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "number" bigint NOT NULL,
+    "snapshot" jsonb NOT NULL,
+    "actor" text NOT NULL,
+    "reason" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_product_revision" ON "product_revisions" ("product_id", "number");
+
+CREATE TABLE IF NOT EXISTS "categories" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "description" text,
+    "parent_id" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_categories_parent_id" ON "categories" ("parent_id");
+
+CREATE TABLE IF NOT EXISTS "category_closures" (
+    "ancestor_id" bigint,
+    "descendant_id" bigint,
+    "depth" bigint NOT NULL,
+    PRIMARY KEY ("ancestor_id", "descendant_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_category_closures_descendant_id" ON "category_closures" ("descendant_id");
+
+CREATE TABLE IF NOT EXISTS "product_categories" (
+    "product_id" bigint,
+    "category_id" bigint,
+    PRIMARY KEY ("product_id", "category_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_product_categories_category_id" ON "product_categories" ("category_id");
+
+CREATE TABLE IF NOT EXISTS "stock_levels" (
+    "product_id" bigint,
+    "warehouse" text,
+    "on_hand" bigint NOT NULL,
+    "reserved" bigint NOT NULL,
+    "low_stock_threshold" bigint NOT NULL,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("product_id", "warehouse")
+);
+
+CREATE TABLE IF NOT EXISTS "stock_movements" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "warehouse" text NOT NULL,
+    "type" text NOT NULL,
+    "on_hand_delta" bigint NOT NULL,
+    "reserved_delta" bigint NOT NULL,
+    "reference" text,
+    "reason" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_stock_movements_product_id" ON "stock_movements" ("product_id");
+
+CREATE TABLE IF NOT EXISTS "product_images" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "key" text NOT NULL,
+    "thumbnail_key" text NOT NULL,
+    "url" text NOT NULL,
+    "thumbnail_url" text NOT NULL,
+    "content_type" text NOT NULL,
+    "size" bigint NOT NULL,
+    "width" bigint NOT NULL,
+    "height" bigint NOT NULL,
+    "position" bigint NOT NULL,
+    "is_primary" boolean NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_product_images_product_id" ON "product_images" ("product_id");
+
+CREATE TABLE IF NOT EXISTS "price_changes" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "old_price" decimal NOT NULL,
+    "new_price" decimal NOT NULL,
+    "actor" text NOT NULL,
+    "reason" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_price_changes_created_at" ON "price_changes" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_price_changes_product_id" ON "price_changes" ("product_id");
+
+CREATE TABLE IF NOT EXISTS "price_schedules" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "price" decimal NOT NULL,
+    "starts_at" timestamptz NOT NULL,
+    "ends_at" timestamptz,
+    "reason" text,
+    "actor" text NOT NULL,
+    "status" text NOT NULL,
+    "original_price" decimal,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_price_schedules_product_id" ON "price_schedules" ("product_id");
+CREATE INDEX IF NOT EXISTS "idx_price_schedules_status" ON "price_schedules" ("status");
+CREATE INDEX IF NOT EXISTS "idx_price_schedules_starts_at" ON "price_schedules" ("starts_at");
+
+CREATE TABLE IF NOT EXISTS "reviews" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "author" text NOT NULL,
+    "rating" bigint NOT NULL,
+    "title" text,
+    "body" text,
+    "status" text NOT NULL,
+    "moderation_note" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_reviews_status" ON "reviews" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_review_product_user" ON "reviews" ("product_id", "user_id");
+
+CREATE TABLE IF NOT EXISTS "promotions" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "type" text NOT NULL,
+    "value" decimal NOT NULL,
+    "buy_quantity" bigint,
+    "get_quantity" bigint,
+    "code" text,
+    "usage_limit" bigint,
+    "per_user_limit" bigint,
+    "usage_count" bigint NOT NULL DEFAULT 0,
+    "starts_at" timestamptz,
+    "ends_at" timestamptz,
+    "active" boolean NOT NULL,
+    "priority" bigint NOT NULL DEFAULT 0,
+    "product_ids" text,
+    "category_ids" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_promotions_code" ON "promotions" ("code");
+
+CREATE TABLE IF NOT EXISTS "promotion_redemptions" (
+    "id" bigserial,
+    "promotion_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "reference" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_promotion_redemptions_user_id" ON "promotion_redemptions" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_promotion_redemptions_promotion_id" ON "promotion_redemptions" ("promotion_id");
+
+CREATE TABLE IF NOT EXISTS "carts" (
+    "id" bigserial,
+    "user_id" bigint,
+    "token" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_carts_token" ON "carts" ("token");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_carts_user_id" ON "carts" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "cart_items" (
+    "id" bigserial,
+    "cart_id" bigint NOT NULL,
+    "product_id" bigint NOT NULL,
+    "variant_id" bigint,
+    "sku" text NOT NULL,
+    "name" text NOT NULL,
+    "quantity" bigint NOT NULL,
+    "unit_price" decimal NOT NULL,
+    "added_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_cart_items_cart_id" ON "cart_items" ("cart_id");
+
+CREATE TABLE IF NOT EXISTS "orders" (
+    "id" bigserial,
+    "number" text NOT NULL,
+    "user_id" bigint NOT NULL,
+    "status" text NOT NULL,
+    "coupon" text,
+    "promotions" text,
+    "subtotal" decimal NOT NULL,
+    "discount" decimal NOT NULL,
+    "tax" decimal NOT NULL DEFAULT 0,
+    "total" decimal NOT NULL,
+    "tax_country" text,
+    "tax_region" text,
+    "prices_include_tax" boolean NOT NULL DEFAULT false,
+    "currency" varchar(3),
+    "base_currency" varchar(3),
+    "exchange_rate" decimal NOT NULL DEFAULT 1,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_status" ON "orders" ("status");
+CREATE INDEX IF NOT EXISTS "idx_orders_user_id" ON "orders" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_orders_number" ON "orders" ("number");
+
+CREATE TABLE IF NOT EXISTS "order_items" (
+    "id" bigserial,
+    "order_id" bigint NOT NULL,
+    "product_id" bigint NOT NULL,
+    "variant_id" bigint,
+    "sku" text NOT NULL,
+    "name" text NOT NULL,
+    "quantity" bigint NOT NULL,
+    "unit_price" decimal NOT NULL,
+    "subtotal" decimal NOT NULL,
+    "discount" decimal NOT NULL,
+    "tax_rate" decimal NOT NULL DEFAULT 0,
+    "tax" decimal NOT NULL DEFAULT 0,
+    "total" decimal NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_order_items_product_id" ON "order_items" ("product_id");
+CREATE INDEX IF NOT EXISTS "idx_order_items_order_id" ON "order_items" ("order_id");
+
+CREATE TABLE IF NOT EXISTS "order_events" (
+    "id" bigserial,
+    "order_id" bigint NOT NULL,
+    "from" text,
+    "to" text NOT NULL,
+    "actor" text,
+    "note" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_order_events_order_id" ON "order_events" ("order_id");
+
+CREATE TABLE IF NOT EXISTS "payments" (
+    "id" bigserial,
+    "order_id" bigint NOT NULL,
+    "intent_id" text NOT NULL,
+    "amount" decimal NOT NULL,
+    "currency" text NOT NULL,
+    "status" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_payments_status" ON "payments" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_payments_intent_id" ON "payments" ("intent_id");
+CREATE INDEX IF NOT EXISTS "idx_payments_order_id" ON "payments" ("order_id");
+
+CREATE TABLE IF NOT EXISTS "payment_events" (
+    "id" text,
+    "type" text NOT NULL,
+    "intent_id" text NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_payment_events_intent_id" ON "payment_events" ("intent_id");
+
+CREATE TABLE IF NOT EXISTS "tax_rates" (
+    "id" bigserial,
+    "country" varchar(2) NOT NULL,
+    "region" text NOT NULL,
+    "tax_class" text NOT NULL,
+    "name" text NOT NULL,
+    "rate" decimal NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tax_rate" ON "tax_rates" ("country", "region", "tax_class");
+
+CREATE TABLE IF NOT EXISTS "wishlists" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "public" boolean NOT NULL DEFAULT false,
+    "share_token" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_wishlists_share_token" ON "wishlists" ("share_token");
+CREATE INDEX IF NOT EXISTS "idx_wishlists_user_id" ON "wishlists" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "wishlist_items" (
+    "id" bigserial,
+    "wishlist_id" bigint NOT NULL,
+    "product_id" bigint NOT NULL,
+    "added_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_wishlist_items_product_id" ON "wishlist_items" ("product_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_wishlist_item" ON "wishlist_items" ("wishlist_id", "product_id");
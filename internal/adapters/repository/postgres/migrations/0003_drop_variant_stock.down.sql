@@ -0,0 +1 @@
+ALTER TABLE "product_variants" ADD COLUMN IF NOT EXISTS "stock" bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Variants share the stock of their product, which inventory tracks.
+ALTER TABLE "product_variants" DROP COLUMN IF EXISTS "stock";
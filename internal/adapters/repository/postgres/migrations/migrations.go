@@ -0,0 +1,18 @@
+// Package migrations holds the SQL migrations of the postgres repositories.
+//
+// Each version has two scripts: NNNN_name.up.sql applies it and
+// NNNN_name.down.sql undoes it. Versions are applied in order, each in a
+// transaction of its own. Add new ones with "migrate create"; never edit a
+// migration that has been released.
+//
+// The first two versions adopt databases created by the AutoMigrate calls of
+// earlier releases and create the initial schema. Undoing the initial schema
+// is refused, since it would drop every table.
+package migrations
+
+import "embed"
+
+// FS holds the migration scripts.
+//
+//go:embed *.sql
+var FS embed.FS
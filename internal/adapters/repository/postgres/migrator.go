@@ -0,0 +1,328 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/euro1061/gohex/internal/adapters/repository/postgres/migrations"
+	"gorm.io/gorm"
+)
+
+// ErrSchemaOutOfDate is returned by Migrator.Check when migrations are
+// pending.
+var ErrSchemaOutOfDate = errors.New("database schema is out of date")
+
+// migrationLockKey identifies the advisory lock that serializes migrations
+// across processes.
+const migrationLockKey int64 = 0x676f686578 // "gohex"
+
+var (
+	migrationFile     = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+	migrationNameSkip = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Migration is a versioned change to the database schema.
+type Migration struct {
+	Version uint
+	Name    string
+	up      string
+	down    string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// MigrationStatus says whether and when a migration was applied.
+type MigrationStatus struct {
+	Migration
+	// AppliedAt is nil for a pending migration.
+	AppliedAt *time.Time
+	// Unknown is set for a migration the database has but this build does
+	// not, such as one applied by a newer version of the application.
+	Unknown bool
+}
+
+// schemaMigration is a row of the migration state table.
+type schemaMigration struct {
+	Version   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies the embedded migrations and records them in the
+// schema_migrations table. Changes hold a Postgres advisory lock, so replicas
+// that migrate at the same time apply each migration once.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator returns a migrator for the migrations embedded in the binary.
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	loaded, err := loadMigrations(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: loaded}, nil
+}
+
+// Up applies the pending migrations in order and returns them. It stops at
+// the first one that fails, which is rolled back.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		done, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.up).Error; err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("error applying migration %s: %w", migration, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down undoes the latest applied migration and returns it, or nil when none
+// is applied. Migrations whose down script raises an error cannot be undone:
+// 0002_initial_schema refuses, since it would drop every table, so Down never
+// goes below it.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	var reverted *Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		var latest schemaMigration
+		result := conn.Order("version DESC").Limit(1).Find(&latest)
+		if result.Error != nil {
+			return fmt.Errorf("error reading applied migrations: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		i := slices.IndexFunc(m.migrations, func(migration Migration) bool {
+			return migration.Version == latest.Version
+		})
+		if i < 0 {
+			return fmt.Errorf("migration %04d_%s is not part of this build", latest.Version, latest.Name)
+		}
+		migration := m.migrations[i]
+
+		err := conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("error reverting migration %s: %w", migration, err)
+		}
+		reverted = &migration
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists the migrations of this build, and those the database has that
+// this build does not, ordered by version.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	db := m.db.WithContext(ctx)
+	done := make(map[uint]schemaMigration)
+	if db.Migrator().HasTable(&schemaMigration{}) {
+		var err error
+		if done, err = appliedMigrations(db); err != nil {
+			return nil, err
+		}
+	}
+	return migrationStatuses(m.migrations, done), nil
+}
+
+// migrationStatuses matches the migrations of this build with the applied
+// ones in done, which it consumes.
+func migrationStatuses(migrations []Migration, done map[uint]schemaMigration) []MigrationStatus {
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Migration: migration}
+		if row, ok := done[migration.Version]; ok {
+			status.AppliedAt = &row.AppliedAt
+			delete(done, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range done {
+		statuses = append(statuses, MigrationStatus{
+			Migration: Migration{Version: row.Version, Name: row.Name},
+			AppliedAt: &row.AppliedAt,
+			Unknown:   true,
+		})
+	}
+	slices.SortFunc(statuses, func(a, b MigrationStatus) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return statuses
+}
+
+// Check returns an error wrapping ErrSchemaOutOfDate when migrations of this
+// build are pending. Migrations the build does not know about are allowed, so
+// an older replica keeps running while a newer one migrates.
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	var pending []string
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending = append(pending, status.String())
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: pending migrations %s", ErrSchemaOutOfDate, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// locked runs fn on a single connection that holds the migration lock, after
+// creating the state table if needed.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return fmt.Errorf("error locking migrations: %w", err)
+		}
+		// Release the lock even when ctx is done, or it stays with the
+		// pooled connection
+		defer conn.WithContext(context.WithoutCancel(ctx)).Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+
+		if err := conn.Exec(`CREATE TABLE IF NOT EXISTS "schema_migrations" (
+			"version" bigint PRIMARY KEY,
+			"name" text NOT NULL,
+			"applied_at" timestamptz NOT NULL
+		)`).Error; err != nil {
+			return fmt.Errorf("error creating migration table: %w", err)
+		}
+		return fn(conn)
+	})
+}
+
+func appliedMigrations(db *gorm.DB) (map[uint]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error reading applied migrations: %w", err)
+	}
+	done := make(map[uint]schemaMigration, len(rows))
+	for _, row := range rows {
+		done[row.Version] = row
+	}
+	return done, nil
+}
+
+// loadMigrations reads the migration scripts in fsys, ordered by version.
+// Every version needs an up and a down script.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migration %s has an invalid version", entry.Name())
+		}
+		script, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", migration, entry.Name(), version)
+		}
+		if match[3] == "up" {
+			migration.up = string(script)
+		} else {
+			migration.down = string(script)
+		}
+	}
+
+	loaded := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.up) == "" || strings.TrimSpace(migration.down) == "" {
+			return nil, fmt.Errorf("migration %s needs an up and a down script", migration)
+		}
+		loaded = append(loaded, *migration)
+	}
+	slices.SortFunc(loaded, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return loaded, nil
+}
+
+// CreateMigration writes empty up and down scripts for a new migration to
+// dir, numbered after the latest one there, and returns their paths. name is
+// reduced to lower case letters, digits and underscores.
+func CreateMigration(dir, name string) (up, down string, err error) {
+	name = strings.Trim(migrationNameSkip.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", errors.New("migration name is empty")
+	}
+
+	existing, err := loadMigrations(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	var version uint = 1
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+
+	migration := Migration{Version: version, Name: name}
+	up = filepath.Join(dir, migration.String()+".up.sql")
+	down = filepath.Join(dir, migration.String()+".down.sql")
+	if err := os.WriteFile(up, []byte("-- "+name+"\n"), 0o644); err != nil {
+		return "", "", fmt.Errorf("error creating migration: %w", err)
+	}
+	if err := os.WriteFile(down, []byte("-- Undo "+name+"\n"), 0o644); err != nil {
+		return "", "", fmt.Errorf("error creating migration: %w", err)
+	}
+	return up, down, nil
+}
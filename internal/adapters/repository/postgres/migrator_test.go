@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/euro1061/gohex/internal/adapters/repository/postgres/migrations"
+)
+
+func TestLoadEmbeddedMigrations(t *testing.T) {
+	loaded, err := loadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	want := []string{"0001_adopt_automigrate_schema", "0002_initial_schema", "0003_drop_variant_stock"}
+	if len(loaded) < len(want) {
+		t.Fatalf("loadMigrations() = %v, want at least %v", loaded, want)
+	}
+	for i, name := range want {
+		if got := loaded[i].String(); got != name {
+			t.Errorf("migration %d = %s, want %s", i, got, name)
+		}
+	}
+	for i := 1; i < len(loaded); i++ {
+		if loaded[i].Version != loaded[i-1].Version+1 {
+			t.Errorf("migration %s follows %s, want consecutive versions", loaded[i], loaded[i-1])
+		}
+	}
+}
+
+func TestInitialSchemaRefusesDown(t *testing.T) {
+	loaded, err := loadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(loaded[1].down, "RAISE EXCEPTION") {
+		t.Errorf("%s down script does not refuse to run", loaded[1])
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	script := &fstest.MapFile{Data: []byte("SELECT 1;")}
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "ordered by version, not by name",
+			files: fstest.MapFS{
+				"0010_tenth.up.sql":    script,
+				"0010_tenth.down.sql":  script,
+				"0002_second.up.sql":   script,
+				"0002_second.down.sql": script,
+				"0001_first.up.sql":    script,
+				"0001_first.down.sql":  script,
+			},
+			want: []string{"0001_first", "0002_second", "0010_tenth"},
+		},
+		{
+			name: "other files are skipped",
+			files: fstest.MapFS{
+				"0001_first.up.sql":   script,
+				"0001_first.down.sql": script,
+				"README.md":           script,
+				"0002_notes.txt":      script,
+				"migrations.go":       script,
+			},
+			want: []string{"0001_first"},
+		},
+		{
+			name:  "empty",
+			files: fstest.MapFS{},
+			want:  []string{},
+		},
+		{
+			name:    "missing down script",
+			files:   fstest.MapFS{"0001_first.up.sql": script},
+			wantErr: true,
+		},
+		{
+			name: "blank down script",
+			files: fstest.MapFS{
+				"0001_first.up.sql":   script,
+				"0001_first.down.sql": &fstest.MapFile{Data: []byte("\n")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "version shared by two names",
+			files: fstest.MapFS{
+				"0001_first.up.sql":   script,
+				"0001_first.down.sql": script,
+				"0001_other.up.sql":   script,
+				"0001_other.down.sql": script,
+			},
+			wantErr: true,
+		},
+		{
+			name: "version zero",
+			files: fstest.MapFS{
+				"0000_zero.up.sql":   script,
+				"0000_zero.down.sql": script,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := loadMigrations(tt.files)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("loadMigrations() = %v, want an error", loaded)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadMigrations() error = %v", err)
+			}
+			got := make([]string, len(loaded))
+			for i, migration := range loaded {
+				got[i] = migration.String()
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("loadMigrations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrationStatuses(t *testing.T) {
+	applied := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	known := []Migration{{Version: 1, Name: "first"}, {Version: 2, Name: "second"}, {Version: 3, Name: "third"}}
+	done := map[uint]schemaMigration{
+		1: {Version: 1, Name: "first", AppliedAt: applied},
+		2: {Version: 2, Name: "second", AppliedAt: applied},
+		5: {Version: 5, Name: "newer", AppliedAt: applied},
+	}
+
+	statuses := migrationStatuses(known, done)
+
+	want := []struct {
+		name    string
+		applied bool
+		unknown bool
+	}{
+		{"0001_first", true, false},
+		{"0002_second", true, false},
+		{"0003_third", false, false},
+		{"0005_newer", true, true},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("migrationStatuses() = %v, want %d statuses", statuses, len(want))
+	}
+	for i, w := range want {
+		status := statuses[i]
+		if status.String() != w.name || (status.AppliedAt != nil) != w.applied || status.Unknown != w.unknown {
+			t.Errorf("status %d = %s applied %v unknown %v, want %s applied %v unknown %v",
+				i, status, status.AppliedAt != nil, status.Unknown, w.name, w.applied, w.unknown)
+		}
+		if w.applied && !status.AppliedAt.Equal(applied) {
+			t.Errorf("status %d applied at %v, want %v", i, status.AppliedAt, applied)
+		}
+	}
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		want     string
+		wantErr  bool
+		wantNext int
+	}{
+		{"add gift cards", "0001_add_gift_cards", false, 1},
+		{"  Drop Legacy-Columns! ", "0002_drop_legacy_columns", false, 2},
+		{"index_orders_by_user", "0003_index_orders_by_user", false, 3},
+		{"!!!", "", true, 3},
+	}
+	for _, tt := range tests {
+		up, down, err := CreateMigration(dir, tt.name)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("CreateMigration(%q) = %s, %s, want an error", tt.name, up, down)
+			}
+		} else {
+			if err != nil {
+				t.Fatalf("CreateMigration(%q) error = %v", tt.name, err)
+			}
+			if up != filepath.Join(dir, tt.want+".up.sql") || down != filepath.Join(dir, tt.want+".down.sql") {
+				t.Errorf("CreateMigration(%q) = %s, %s, want %s scripts", tt.name, up, down, tt.want)
+			}
+		}
+
+		loaded, err := loadMigrations(os.DirFS(dir))
+		if err != nil {
+			t.Fatalf("created migrations do not load: %v", err)
+		}
+		if len(loaded) != tt.wantNext {
+			t.Errorf("after CreateMigration(%q) %d migrations load, want %d", tt.name, len(loaded), tt.wantNext)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"gorm.io/gorm"
+)
+
+type OrderRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderRepository(db *gorm.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		for i := range order.Items {
+			order.Items[i].OrderID = order.ID
+		}
+		for i := range order.History {
+			order.History[i].OrderID = order.ID
+		}
+		if len(order.Items) > 0 {
+			if err := tx.Create(&order.Items).Error; err != nil {
+				return err
+			}
+		}
+		if len(order.History) > 0 {
+			return tx.Create(&order.History).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error creating order: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *OrderRepository) GetByID(ctx context.Context, id uint) (*domain.Order, error) {
+	var order domain.Order
+	result := r.db.WithContext(ctx).First(&order, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting order: %w", result.Error)
+	}
+
+	orders := []domain.Order{order}
+	if err := r.loadDetails(ctx, orders); err != nil {
+		return nil, err
+	}
+	return &orders[0], nil
+}
+
+func (r *OrderRepository) GetByUser(ctx context.Context, userID uint) ([]domain.Order, error) {
+	return r.find(ctx, r.db.WithContext(ctx).Where("user_id = ?", userID))
+}
+
+func (r *OrderRepository) GetAll(ctx context.Context, status domain.OrderStatus) ([]domain.Order, error) {
+	query := r.db.WithContext(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return r.find(ctx, query)
+}
+
+func (r *OrderRepository) find(ctx context.Context, query *gorm.DB) ([]domain.Order, error) {
+	var orders []domain.Order
+	if err := query.Order("id DESC").Find(&orders).Error; err != nil {
+		return nil, fmt.Errorf("error getting orders: %w", err)
+	}
+	if err := r.loadDetails(ctx, orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *OrderRepository) UpdateStatus(ctx context.Context, event *domain.OrderEvent) (bool, error) {
+	updated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Order{}).
+			Where("id = ? AND status = ?", event.OrderID, event.From).
+			Updates(map[string]interface{}{"status": event.To, "updated_at": time.Now()})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		updated = true
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("error updating order status: %w", err)
+	}
+	return updated, nil
+}
+
+// loadDetails fills the Items and History fields of every order in place.
+func (r *OrderRepository) loadDetails(ctx context.Context, orders []domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(orders))
+	for i := range orders {
+		ids[i] = orders[i].ID
+	}
+
+	var items []domain.OrderItem
+	if err := r.db.WithContext(ctx).Where("order_id IN ?", ids).Order("id").Find(&items).Error; err != nil {
+		return fmt.Errorf("error getting order items: %w", err)
+	}
+	var events []domain.OrderEvent
+	if err := r.db.WithContext(ctx).Where("order_id IN ?", ids).Order("id").Find(&events).Error; err != nil {
+		return fmt.Errorf("error getting order history: %w", err)
+	}
+
+	itemsByOrder := make(map[uint][]domain.OrderItem)
+	for _, item := range items {
+		itemsByOrder[item.OrderID] = append(itemsByOrder[item.OrderID], item)
+	}
+	eventsByOrder := make(map[uint][]domain.OrderEvent)
+	for _, event := range events {
+		eventsByOrder[event.OrderID] = append(eventsByOrder[event.OrderID], event)
+	}
+	for i := range orders {
+		orders[i].Items = itemsByOrder[orders[i].ID]
+		if orders[i].Items == nil {
+			orders[i].Items = []domain.OrderItem{}
+		}
+		orders[i].History = eventsByOrder[orders[i].ID]
+		if orders[i].History == nil {
+			orders[i].History = []domain.OrderEvent{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+func (r *PaymentRepository) Create(ctx context.Context, payment *domain.Payment) error {
+	if err := r.db.WithContext(ctx).Create(payment).Error; err != nil {
+		return fmt.Errorf("error creating payment: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *PaymentRepository) GetByIntent(ctx context.Context, intentID string) (*domain.Payment, error) {
+	var payment domain.Payment
+	result := r.db.WithContext(ctx).Where("intent_id = ?", intentID).First(&payment)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting payment: %w", result.Error)
+	}
+	return &payment, nil
+}
+
+func (r *PaymentRepository) GetByOrder(ctx context.Context, orderID uint) ([]domain.Payment, error) {
+	var payments []domain.Payment
+	result := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id DESC").Find(&payments)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting payments: %w", result.Error)
+	}
+	return payments, nil
+}
+
+func (r *PaymentRepository) UpdateStatus(ctx context.Context, id uint, from, to domain.PaymentStatus) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.Payment{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{"status": to, "updated_at": time.Now()})
+	if result.Error != nil {
+		return false, fmt.Errorf("error updating payment status: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *PaymentRepository) GetStale(ctx context.Context, statuses []domain.PaymentStatus, before time.Time) ([]domain.Payment, error) {
+	var payments []domain.Payment
+	result := r.db.WithContext(ctx).Where("status IN ? AND updated_at < ?", statuses, before).Order("id").Find(&payments)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting stale payments: %w", result.Error)
+	}
+	return payments, nil
+}
+
+func (r *PaymentRepository) HasEvent(ctx context.Context, id string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.PaymentEvent{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("error getting payment event: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *PaymentRepository) RecordEvent(ctx context.Context, event *domain.PaymentEvent) error {
+	// A redelivered event may be recorded concurrently; the first one wins
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(event).Error; err != nil {
+		return fmt.Errorf("error recording payment event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dueSchedules matches scheduled entries that have started and active
+// entries that have ended.
+const dueSchedules = "(status = @scheduled AND starts_at <= @now) OR (status = @active AND ends_at <= @now)"
+
+type PriceRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceRepository(db *gorm.DB) *PriceRepository {
+	return &PriceRepository{db: db}
+}
+
+func (r *PriceRepository) RecordChange(ctx context.Context, change *domain.PriceChange) error {
+	if err := r.db.WithContext(ctx).Create(change).Error; err != nil {
+		return fmt.Errorf("error recording price change: %w", err)
+	}
+	return nil
+}
+
+func (r *PriceRepository) GetHistory(ctx context.Context, productID uint, limit int) ([]domain.PriceChange, error) {
+	var changes []domain.PriceChange
+	result := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("id DESC").Limit(limit).Find(&changes)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting price history: %w", result.Error)
+	}
+	return changes, nil
+}
+
+func (r *PriceRepository) CreateSchedule(ctx context.Context, schedule *domain.PriceSchedule) error {
+	if err := r.db.WithContext(ctx).Create(schedule).Error; err != nil {
+		return fmt.Errorf("error creating price schedule: %w", err)
+	}
+	return nil
+}
+
+func (r *PriceRepository) GetSchedule(ctx context.Context, id uint) (*domain.PriceSchedule, error) {
+	var schedule domain.PriceSchedule
+	result := r.db.WithContext(ctx).First(&schedule, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting price schedule: %w", result.Error)
+	}
+	return &schedule, nil
+}
+
+func (r *PriceRepository) GetSchedules(ctx context.Context, productID uint) ([]domain.PriceSchedule, error) {
+	var schedules []domain.PriceSchedule
+	result := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("starts_at, id").Find(&schedules)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting price schedules: %w", result.Error)
+	}
+	return schedules, nil
+}
+
+func (r *PriceRepository) GetDueSchedules(ctx context.Context, now time.Time) ([]domain.PriceSchedule, error) {
+	var schedules []domain.PriceSchedule
+	result := r.db.WithContext(ctx).
+		Where(dueSchedules, dueScheduleArgs(now)).
+		Order("starts_at, id").
+		Find(&schedules)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting due price schedules: %w", result.Error)
+	}
+	return schedules, nil
+}
+
+func (r *PriceRepository) LockSchedule(ctx context.Context, id uint) (*domain.PriceSchedule, error) {
+	var schedule domain.PriceSchedule
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Limit(1).Find(&schedule, id)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error locking price schedule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &schedule, nil
+}
+
+func (r *PriceRepository) ClaimDueSchedule(ctx context.Context, id uint, now time.Time) (*domain.PriceSchedule, error) {
+	var schedule domain.PriceSchedule
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("id = ?", id).
+		Where(dueSchedules, dueScheduleArgs(now)).
+		Limit(1).
+		Find(&schedule)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error claiming price schedule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &schedule, nil
+}
+
+func dueScheduleArgs(now time.Time) map[string]any {
+	return map[string]any{
+		"scheduled": domain.PriceScheduleScheduled,
+		"active":    domain.PriceScheduleActive,
+		"now":       now,
+	}
+}
+
+func (r *PriceRepository) UpdateSchedule(ctx context.Context, schedule *domain.PriceSchedule) error {
+	if err := r.db.WithContext(ctx).Save(schedule).Error; err != nil {
+		return fmt.Errorf("error updating price schedule: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"gorm.io/gorm"
+)
+
+type ProductImageRepository struct {
+	db *gorm.DB
+}
+
+func NewProductImageRepository(db *gorm.DB) *ProductImageRepository {
+	return &ProductImageRepository{db: db}
+}
+
+func (r *ProductImageRepository) Create(ctx context.Context, image *domain.ProductImage) error {
+	result := r.db.WithContext(ctx).Create(image)
+	if result.Error != nil {
+		return fmt.Errorf("error creating product image: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *ProductImageRepository) GetByID(ctx context.Context, id uint) (*domain.ProductImage, error) {
+	var image domain.ProductImage
+	result := r.db.WithContext(ctx).First(&image, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting product image: %w", result.Error)
+	}
+	return &image, nil
+}
+
+func (r *ProductImageRepository) GetByProduct(ctx context.Context, productID uint) ([]domain.ProductImage, error) {
+	var images []domain.ProductImage
+	result := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("position, id").Find(&images)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting product images: %w", result.Error)
+	}
+	return images, nil
+}
+
+func (r *ProductImageRepository) GetByProducts(ctx context.Context, productIDs []uint) (map[uint][]domain.ProductImage, error) {
+	images := make(map[uint][]domain.ProductImage, len(productIDs))
+	if len(productIDs) == 0 {
+		return images, nil
+	}
+
+	var rows []domain.ProductImage
+	result := r.db.WithContext(ctx).Where("product_id IN ?", productIDs).Order("product_id, position, id").Find(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting product images: %w", result.Error)
+	}
+	for _, image := range rows {
+		images[image.ProductID] = append(images[image.ProductID], image)
+	}
+	return images, nil
+}
+
+func (r *ProductImageRepository) UpdateAll(ctx context.Context, images []domain.ProductImage) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, image := range images {
+			result := tx.Model(&domain.ProductImage{}).Where("id = ?", image.ID).
+				Updates(map[string]interface{}{"position": image.Position, "is_primary": image.IsPrimary})
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error updating product images: %w", err)
+	}
+	return nil
+}
+
+func (r *ProductImageRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&domain.ProductImage{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting product image: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("product image not found")
+	}
+	return nil
+}
+
+func (r *ProductImageRepository) DeleteByProduct(ctx context.Context, productID uint) error {
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Delete(&domain.ProductImage{}).Error; err != nil {
+		return fmt.Errorf("error deleting product images: %w", err)
+	}
+	return nil
+}
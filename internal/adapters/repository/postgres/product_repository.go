@@ -1,72 +1,409 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/euro1061/gohex/internal/domain"
 	"gorm.io/gorm"
 )
 
+type productTag struct {
+	ProductID uint   `gorm:"primaryKey;autoIncrement:false"`
+	Tag       string `gorm:"primaryKey;index"`
+}
+
+func (productTag) TableName() string {
+	return "product_tags"
+}
+
 type ProductRepository struct {
 	db *gorm.DB
 }
 
 func NewProductRepository(db *gorm.DB) *ProductRepository {
-	// Auto Migrate the schema
-	if err := db.AutoMigrate(&domain.Product{}); err != nil {
-		panic(fmt.Sprintf("error migrating database: %v", err))
+	return &ProductRepository{db: db}
+}
+
+func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		return r.saveDetails(tx, product)
+	})
+	if err != nil {
+		return fmt.Errorf("error creating product: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *ProductRepository) GetByID(ctx context.Context, id uint) (*domain.Product, error) {
+	var product domain.Product
+	result := r.db.WithContext(ctx).First(&product, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting product: %w", result.Error)
 	}
 
-	return &ProductRepository{db: db}
+	products := []domain.Product{product}
+	if err := r.loadDetails(ctx, products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	return r.getBy(ctx, "sku = ?", sku)
+}
+
+func (r *ProductRepository) GetBySlug(ctx context.Context, slug string) (*domain.Product, error) {
+	return r.getBy(ctx, "slug = ?", slug)
 }
 
-func (r *ProductRepository) Create(product *domain.Product) error {
-	result := r.db.Create(product)
+func (r *ProductRepository) GetVariantBySKU(ctx context.Context, sku string) (*domain.ProductVariant, error) {
+	var variant domain.ProductVariant
+	result := r.db.WithContext(ctx).Where("sku = ?", sku).First(&variant)
 	if result.Error != nil {
-		return fmt.Errorf("error creating product: %v", result.Error)
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting product variant: %w", result.Error)
 	}
-	return nil
+	return &variant, nil
 }
 
-func (r *ProductRepository) GetByID(id uint) (*domain.Product, error) {
+func (r *ProductRepository) getBy(ctx context.Context, query string, args ...interface{}) (*domain.Product, error) {
 	var product domain.Product
-	result := r.db.First(&product, id)
+	result := r.db.WithContext(ctx).Where(query, args...).First(&product)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("error getting product: %v", result.Error)
+		return nil, fmt.Errorf("error getting product: %w", result.Error)
 	}
-	return &product, nil
+
+	products := []domain.Product{product}
+	if err := r.loadDetails(ctx, products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
 }
 
-func (r *ProductRepository) GetAll() ([]domain.Product, error) {
+func (r *ProductRepository) GetAll(ctx context.Context) ([]domain.Product, error) {
 	var products []domain.Product
-	result := r.db.Find(&products)
+	result := r.db.WithContext(ctx).Find(&products)
 	if result.Error != nil {
-		return nil, fmt.Errorf("error getting products: %v", result.Error)
+		return nil, fmt.Errorf("error getting products: %w", result.Error)
+	}
+	if err := r.loadDetails(ctx, products); err != nil {
+		return nil, err
 	}
 	return products, nil
 }
 
-func (r *ProductRepository) Update(product *domain.Product) error {
-	result := r.db.Save(product)
+func (r *ProductRepository) GetByIDs(ctx context.Context, ids []uint) ([]domain.Product, error) {
+	var products []domain.Product
+	if len(ids) == 0 {
+		return products, nil
+	}
+	result := r.db.WithContext(ctx).Where("id IN ?", ids).Order("id").Find(&products)
 	if result.Error != nil {
-		return fmt.Errorf("error updating product: %v", result.Error)
+		return nil, fmt.Errorf("error getting products: %w", result.Error)
+	}
+	if err := r.loadDetails(ctx, products); err != nil {
+		return nil, err
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("product not found")
+	return products, nil
+}
+
+func (r *ProductRepository) Find(ctx context.Context, filter domain.ProductFilter) ([]domain.Product, error) {
+	var products []domain.Product
+	if filter.ProductIDs != nil && len(filter.ProductIDs) == 0 {
+		return products, nil
+	}
+
+	result := r.applyFilter(r.db.WithContext(ctx), filter).Order("id").Find(&products)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting products: %w", result.Error)
+	}
+	if err := r.loadDetails(ctx, products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *ProductRepository) FindAfter(ctx context.Context, filter domain.ProductFilter, afterID uint, limit int) ([]domain.Product, error) {
+	var products []domain.Product
+	if filter.ProductIDs != nil && len(filter.ProductIDs) == 0 {
+		return products, nil
+	}
+
+	result := r.applyFilter(r.db.WithContext(ctx), filter).Where("id > ?", afterID).Order("id").Limit(limit).Find(&products)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting products: %w", result.Error)
+	}
+	if err := r.loadDetails(ctx, products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Save(product)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("product not found")
+		}
+		return r.saveDetails(tx, product)
+	})
+	if err != nil {
+		return fmt.Errorf("error updating product: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *ProductRepository) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&productTag{}, &domain.ProductOption{}, &domain.ProductVariant{}} {
+			if err := tx.Where("product_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Delete(&domain.Product{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("product not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting product: %w", err)
+	}
+	return nil
+}
+
+// CreateRevision locks the product row so concurrent changes number their
+// revisions one after the other.
+func (r *ProductRepository) CreateRevision(ctx context.Context, revision *domain.ProductRevision) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT id FROM products WHERE id = ? FOR UPDATE", revision.ProductID).Error; err != nil {
+			return err
+		}
+		var latest int
+		err := tx.Model(&domain.ProductRevision{}).
+			Where("product_id = ?", revision.ProductID).
+			Select("COALESCE(MAX(number), 0)").
+			Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+		revision.Number = latest + 1
+		return tx.Create(revision).Error
+	})
+	if err != nil {
+		return fmt.Errorf("error creating product revision: %w", err)
 	}
 	return nil
 }
 
-func (r *ProductRepository) Delete(id uint) error {
-	result := r.db.Delete(&domain.Product{}, id)
+func (r *ProductRepository) GetRevision(ctx context.Context, productID uint, number int) (*domain.ProductRevision, error) {
+	var revision domain.ProductRevision
+	result := r.db.WithContext(ctx).Where("product_id = ? AND number = ?", productID, number).First(&revision)
 	if result.Error != nil {
-		return fmt.Errorf("error deleting product: %v", result.Error)
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting product revision: %w", result.Error)
+	}
+	return &revision, nil
+}
+
+func (r *ProductRepository) GetRevisions(ctx context.Context, productID uint, limit int) ([]domain.ProductRevision, error) {
+	var revisions []domain.ProductRevision
+	result := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("number DESC").Limit(limit).Find(&revisions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting product revisions: %w", result.Error)
+	}
+	return revisions, nil
+}
+
+func (r *ProductRepository) applyFilter(db *gorm.DB, filter domain.ProductFilter) *gorm.DB {
+	if filter.ProductIDs != nil {
+		db = db.Where("id IN ?", filter.ProductIDs)
+	}
+	if filter.MinPrice != nil {
+		db = db.Where("price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		db = db.Where("price <= ?", *filter.MaxPrice)
+	}
+	if len(filter.Statuses) > 0 {
+		db = db.Where("status IN ?", filter.Statuses)
+	}
+	if filter.LiveAt != nil {
+		db = db.Where("status = ? AND (publish_at IS NULL OR publish_at <= ?) AND (unpublish_at IS NULL OR unpublish_at > ?)",
+			domain.ProductPublished, *filter.LiveAt, *filter.LiveAt)
+	}
+	if len(filter.Tags) > 0 {
+		tagged := r.db.Model(&productTag{}).Select("product_id").Where("tag IN ?", filter.Tags)
+		if filter.TagMatch == domain.TagMatchAll {
+			tagged = tagged.Group("product_id").Having("COUNT(DISTINCT tag) = ?", len(filter.Tags))
+		}
+		db = db.Where("id IN (?)", tagged)
+	}
+	return db
+}
+
+// saveDetails stores the tags, options and variants of a product.
+func (r *ProductRepository) saveDetails(tx *gorm.DB, product *domain.Product) error {
+	if err := r.saveTags(tx, product); err != nil {
+		return err
+	}
+	if err := r.saveOptions(tx, product); err != nil {
+		return err
+	}
+	return r.saveVariants(tx, product)
+}
+
+// saveOptions replaces the stored options of a product with product.Options.
+func (r *ProductRepository) saveOptions(tx *gorm.DB, product *domain.Product) error {
+	if err := tx.Where("product_id = ?", product.ID).Delete(&domain.ProductOption{}).Error; err != nil {
+		return err
+	}
+	for i := range product.Options {
+		product.Options[i].ID = 0
+		product.Options[i].ProductID = product.ID
+	}
+	if len(product.Options) == 0 {
+		return nil
+	}
+	return tx.Create(&product.Options).Error
+}
+
+// saveVariants updates the variants of a product that already have an ID,
+// creates the new ones and deletes those no longer listed.
+func (r *ProductRepository) saveVariants(tx *gorm.DB, product *domain.Product) error {
+	keep := make([]uint, 0, len(product.Variants))
+	for _, variant := range product.Variants {
+		if variant.ID != 0 {
+			keep = append(keep, variant.ID)
+		}
+	}
+
+	stale := tx.Where("product_id = ?", product.ID)
+	if len(keep) > 0 {
+		stale = stale.Where("id NOT IN ?", keep)
+	}
+	if err := stale.Delete(&domain.ProductVariant{}).Error; err != nil {
+		return err
+	}
+
+	for i := range product.Variants {
+		product.Variants[i].ProductID = product.ID
+		if err := tx.Save(&product.Variants[i]).Error; err != nil {
+			return err
+		}
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("product not found")
+	return nil
+}
+
+// loadDetails fills the tags, options and variants of every product in place.
+func (r *ProductRepository) loadDetails(ctx context.Context, products []domain.Product) error {
+	if err := r.loadTags(ctx, products); err != nil {
+		return err
+	}
+	return r.loadVariants(ctx, products)
+}
+
+// loadVariants fills the Options and Variants fields of every product in place.
+func (r *ProductRepository) loadVariants(ctx context.Context, products []domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	var options []domain.ProductOption
+	if err := r.db.WithContext(ctx).Where("product_id IN ?", ids).Order("position, id").Find(&options).Error; err != nil {
+		return fmt.Errorf("error getting product options: %w", err)
+	}
+	var variants []domain.ProductVariant
+	if err := r.db.WithContext(ctx).Where("product_id IN ?", ids).Order("id").Find(&variants).Error; err != nil {
+		return fmt.Errorf("error getting product variants: %w", err)
+	}
+
+	optionsByProduct := make(map[uint][]domain.ProductOption, len(products))
+	for _, option := range options {
+		optionsByProduct[option.ProductID] = append(optionsByProduct[option.ProductID], option)
+	}
+	variantsByProduct := make(map[uint][]domain.ProductVariant, len(products))
+	for _, variant := range variants {
+		variantsByProduct[variant.ProductID] = append(variantsByProduct[variant.ProductID], variant)
+	}
+	for i := range products {
+		products[i].Options = optionsByProduct[products[i].ID]
+		if products[i].Options == nil {
+			products[i].Options = []domain.ProductOption{}
+		}
+		products[i].Variants = variantsByProduct[products[i].ID]
+		if products[i].Variants == nil {
+			products[i].Variants = []domain.ProductVariant{}
+		}
+	}
+	return nil
+}
+
+// saveTags replaces the stored tags of a product with product.Tags.
+func (r *ProductRepository) saveTags(tx *gorm.DB, product *domain.Product) error {
+	if err := tx.Where("product_id = ?", product.ID).Delete(&productTag{}).Error; err != nil {
+		return err
+	}
+	if len(product.Tags) == 0 {
+		return nil
+	}
+	tags := make([]productTag, 0, len(product.Tags))
+	for _, tag := range product.Tags {
+		tags = append(tags, productTag{ProductID: product.ID, Tag: tag})
+	}
+	return tx.Create(&tags).Error
+}
+
+// loadTags fills the Tags field of every product in place.
+func (r *ProductRepository) loadTags(ctx context.Context, products []domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	var tags []productTag
+	if err := r.db.WithContext(ctx).Where("product_id IN ?", ids).Order("tag").Find(&tags).Error; err != nil {
+		return fmt.Errorf("error getting product tags: %w", err)
+	}
+
+	byProduct := make(map[uint][]string, len(products))
+	for _, tag := range tags {
+		byProduct[tag.ProductID] = append(byProduct[tag.ProductID], tag.Tag)
+	}
+	for i := range products {
+		products[i].Tags = byProduct[products[i].ID]
+		if products[i].Tags == nil {
+			products[i].Tags = []string{}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PromotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+func (r *PromotionRepository) Create(ctx context.Context, promotion *domain.Promotion) error {
+	if err := r.db.WithContext(ctx).Create(promotion).Error; err != nil {
+		return fmt.Errorf("error creating promotion: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *PromotionRepository) GetByID(ctx context.Context, id uint) (*domain.Promotion, error) {
+	return r.getBy(ctx, "id = ?", id)
+}
+
+func (r *PromotionRepository) GetByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	return r.getBy(ctx, "code = ?", code)
+}
+
+func (r *PromotionRepository) getBy(ctx context.Context, query string, args ...interface{}) (*domain.Promotion, error) {
+	var promotion domain.Promotion
+	result := r.db.WithContext(ctx).Where(query, args...).First(&promotion)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting promotion: %w", result.Error)
+	}
+	return &promotion, nil
+}
+
+func (r *PromotionRepository) GetAll(ctx context.Context) ([]domain.Promotion, error) {
+	var promotions []domain.Promotion
+	if err := r.db.WithContext(ctx).Order("id").Find(&promotions).Error; err != nil {
+		return nil, fmt.Errorf("error getting promotions: %w", err)
+	}
+	return promotions, nil
+}
+
+func (r *PromotionRepository) GetAutomatic(ctx context.Context, now time.Time) ([]domain.Promotion, error) {
+	var promotions []domain.Promotion
+	result := r.db.WithContext(ctx).
+		Where("code IS NULL AND active").
+		Where("starts_at IS NULL OR starts_at <= ?", now).
+		Where("ends_at IS NULL OR ends_at > ?", now).
+		Order("id").
+		Find(&promotions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting automatic promotions: %w", result.Error)
+	}
+	return promotions, nil
+}
+
+func (r *PromotionRepository) Update(ctx context.Context, promotion *domain.Promotion) error {
+	result := r.db.WithContext(ctx).Model(promotion).Select("*").Omit("usage_count", "created_at").Updates(promotion)
+	if result.Error != nil {
+		return fmt.Errorf("error updating promotion: %w", translateError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("promotion not found")
+	}
+	return nil
+}
+
+func (r *PromotionRepository) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("promotion_id = ?", id).Delete(&domain.PromotionRedemption{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&domain.Promotion{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("promotion not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting promotion: %w", err)
+	}
+	return nil
+}
+
+func (r *PromotionRepository) CountRedemptions(ctx context.Context, promotionID, userID uint) (int, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&domain.PromotionRedemption{}).
+		Where("promotion_id = ? AND user_id = ?", promotionID, userID).
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("error counting promotion redemptions: %w", result.Error)
+	}
+	return int(count), nil
+}
+
+// Redeem locks the promotion row so concurrent redemptions cannot exceed
+// its limits.
+func (r *PromotionRepository) Redeem(ctx context.Context, redemption *domain.PromotionRedemption) (bool, error) {
+	redeemed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var promotion domain.Promotion
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&promotion, redemption.PromotionID).Error
+		if err != nil {
+			return err
+		}
+		if promotion.Exhausted() {
+			return nil
+		}
+		if promotion.PerUserLimit != nil {
+			var count int64
+			err := tx.Model(&domain.PromotionRedemption{}).
+				Where("promotion_id = ? AND user_id = ?", promotion.ID, redemption.UserID).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if int(count) >= *promotion.PerUserLimit {
+				return nil
+			}
+		}
+
+		if err := tx.Create(redemption).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&promotion).UpdateColumn("usage_count", gorm.Expr("usage_count + 1")).Error
+		if err != nil {
+			return err
+		}
+		redeemed = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("error redeeming promotion: %w", err)
+	}
+	return redeemed, nil
+}
+
+func (r *PromotionRepository) Unredeem(ctx context.Context, reference string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var redemptions []domain.PromotionRedemption
+		err := tx.Clauses(clause.Returning{}).
+			Where("reference = ?", reference).
+			Delete(&redemptions).Error
+		if err != nil {
+			return err
+		}
+		uses := make(map[uint]int)
+		for _, redemption := range redemptions {
+			uses[redemption.PromotionID]++
+		}
+		for promotionID, count := range uses {
+			err := tx.Model(&domain.Promotion{}).Where("id = ?", promotionID).
+				UpdateColumn("usage_count", gorm.Expr("GREATEST(usage_count - ?, 0)", count)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error unredeeming promotions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"gorm.io/gorm"
+)
+
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+func (r *ReviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	if err := r.db.WithContext(ctx).Create(review).Error; err != nil {
+		return fmt.Errorf("error creating review: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *ReviewRepository) GetByID(ctx context.Context, id uint) (*domain.Review, error) {
+	var review domain.Review
+	result := r.db.WithContext(ctx).First(&review, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting review: %w", result.Error)
+	}
+	return &review, nil
+}
+
+func (r *ReviewRepository) GetByProduct(ctx context.Context, productID uint, status domain.ReviewStatus) ([]domain.Review, error) {
+	var reviews []domain.Review
+	query := r.db.WithContext(ctx).Where("product_id = ?", productID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC, id DESC").Find(&reviews).Error; err != nil {
+		return nil, fmt.Errorf("error getting reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+func (r *ReviewRepository) Update(ctx context.Context, review *domain.Review) error {
+	if err := r.db.WithContext(ctx).Save(review).Error; err != nil {
+		return fmt.Errorf("error updating review: %w", err)
+	}
+	return nil
+}
+
+func (r *ReviewRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&domain.Review{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting review: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("review not found")
+	}
+	return nil
+}
+
+func (r *ReviewRepository) GetRatingCounts(ctx context.Context, productIDs []uint) (map[uint]map[int]int, error) {
+	counts := make(map[uint]map[int]int)
+	if len(productIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ProductID uint
+		Rating    int
+		Count     int
+	}
+	result := r.db.WithContext(ctx).Model(&domain.Review{}).
+		Select("product_id, rating, COUNT(*) AS count").
+		Where("product_id IN ? AND status = ?", productIDs, domain.ReviewApproved).
+		Group("product_id, rating").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error counting ratings: %w", result.Error)
+	}
+
+	for _, row := range rows {
+		if counts[row.ProductID] == nil {
+			counts[row.ProductID] = make(map[int]int)
+		}
+		counts[row.ProductID][row.Rating] = row.Count
+	}
+	return counts, nil
+}
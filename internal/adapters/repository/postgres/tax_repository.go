@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"gorm.io/gorm"
+)
+
+type TaxRateRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxRateRepository(db *gorm.DB) *TaxRateRepository {
+	return &TaxRateRepository{db: db}
+}
+
+func (r *TaxRateRepository) Create(ctx context.Context, rate *domain.TaxRate) error {
+	if err := r.db.WithContext(ctx).Create(rate).Error; err != nil {
+		return fmt.Errorf("error creating tax rate: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *TaxRateRepository) GetByID(ctx context.Context, id uint) (*domain.TaxRate, error) {
+	var rate domain.TaxRate
+	result := r.db.WithContext(ctx).First(&rate, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting tax rate: %w", result.Error)
+	}
+	return &rate, nil
+}
+
+func (r *TaxRateRepository) GetAll(ctx context.Context) ([]domain.TaxRate, error) {
+	var rates []domain.TaxRate
+	if err := r.db.WithContext(ctx).Order("country, region, tax_class").Find(&rates).Error; err != nil {
+		return nil, fmt.Errorf("error getting tax rates: %w", err)
+	}
+	return rates, nil
+}
+
+func (r *TaxRateRepository) GetByCountry(ctx context.Context, country string) ([]domain.TaxRate, error) {
+	var rates []domain.TaxRate
+	result := r.db.WithContext(ctx).Where("country = ?", country).Order("region, tax_class").Find(&rates)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error getting tax rates: %w", result.Error)
+	}
+	return rates, nil
+}
+
+func (r *TaxRateRepository) Update(ctx context.Context, rate *domain.TaxRate) error {
+	result := r.db.WithContext(ctx).Model(rate).Select("*").Omit("created_at").Updates(rate)
+	if result.Error != nil {
+		return fmt.Errorf("error updating tax rate: %w", translateError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("tax rate not found")
+	}
+	return nil
+}
+
+func (r *TaxRateRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&domain.TaxRate{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting tax rate: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("tax rate not found")
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/euro1061/gohex/internal/ports/repository"
+	"gorm.io/gorm"
+)
+
+// UnitOfWork runs transactions that span the repositories of this package.
+type UnitOfWork struct {
+	db *gorm.DB
+}
+
+// NewUnitOfWork returns a unit of work on db.
+func NewUnitOfWork(db *gorm.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Transaction runs fn with repositories bound to a database transaction. The
+// repository methods that use transactions of their own, and nested units of
+// work, run as savepoints inside it, so their failures do not abort it.
+func (u *UnitOfWork) Transaction(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(repository.Repositories{
+			Products:   &ProductRepository{db: tx},
+			Users:      &UserRepository{db: tx},
+			Categories: &CategoryRepository{db: tx},
+			Inventory:  &InventoryRepository{db: tx},
+			Images:     &ProductImageRepository{db: tx},
+			Prices:     &PriceRepository{db: tx},
+			Reviews:    &ReviewRepository{db: tx},
+			Promotions: &PromotionRepository{db: tx},
+			Carts:      &CartRepository{db: tx},
+			Orders:     &OrderRepository{db: tx},
+			Payments:   &PaymentRepository{db: tx},
+			TaxRates:   &TaxRateRepository{db: tx},
+			Wishlists:  &WishlistRepository{db: tx},
+			UnitOfWork: &UnitOfWork{db: tx},
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/euro1061/gohex/internal/domain"
@@ -12,62 +13,57 @@ type UserRepository struct {
 }
 
 func NewUserRepository(db *gorm.DB) *UserRepository {
-	// Auto Migrate the schema
-	if err := db.AutoMigrate(&domain.User{}); err != nil {
-		panic(fmt.Sprintf("error migrating database: %v", err))
-	}
-
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) Create(user *domain.User) error {
-	result := r.db.Create(user)
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	result := r.db.WithContext(ctx).Create(user)
 	if result.Error != nil {
-		return fmt.Errorf("error creating user: %v", result.Error)
+		return fmt.Errorf("error creating user: %w", result.Error)
 	}
 	return nil
 }
 
-func (r *UserRepository) GetByID(id uint) (*domain.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id uint) (*domain.User, error) {
 	var user domain.User
-	result := r.db.First(&user, id)
+	result := r.db.WithContext(ctx).First(&user, id)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("error getting user: %v", result.Error)
+		return nil, fmt.Errorf("error getting user: %w", result.Error)
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) GetByUsername(username string) (*domain.User, error) {
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	var user domain.User
-	result := r.db.Where("username = ?", username).First(&user)
+	result := r.db.WithContext(ctx).Where("username = ?", username).First(&user)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("error getting user: %v", result.Error)
+		return nil, fmt.Errorf("error getting user: %w", result.Error)
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) GetByEmail(email string) (*domain.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	result := r.db.Where("email = ?", email).First(&user)
+	result := r.db.WithContext(ctx).Where("email = ?", email).First(&user)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("error getting user: %v", result.Error)
+		return nil, fmt.Errorf("error getting user: %w", result.Error)
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) Update(user *domain.User) error {
-	result := r.db.Save(user)
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	result := r.db.WithContext(ctx).Save(user)
 	if result.Error != nil {
-		return fmt.Errorf("error updating user: %v", result.Error)
+		return fmt.Errorf("error updating user: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
 		return fmt.Errorf("user not found")
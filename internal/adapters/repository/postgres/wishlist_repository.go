@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"gorm.io/gorm"
+)
+
+type WishlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWishlistRepository(db *gorm.DB) *WishlistRepository {
+	return &WishlistRepository{db: db}
+}
+
+func (r *WishlistRepository) Create(ctx context.Context, wishlist *domain.Wishlist) error {
+	if err := r.db.WithContext(ctx).Create(wishlist).Error; err != nil {
+		return fmt.Errorf("error creating wishlist: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *WishlistRepository) GetByID(ctx context.Context, id uint) (*domain.Wishlist, error) {
+	return r.getBy(ctx, "id = ?", id)
+}
+
+func (r *WishlistRepository) GetByShareToken(ctx context.Context, token string) (*domain.Wishlist, error) {
+	return r.getBy(ctx, "share_token = ?", token)
+}
+
+func (r *WishlistRepository) getBy(ctx context.Context, query string, args ...interface{}) (*domain.Wishlist, error) {
+	var wishlist domain.Wishlist
+	result := r.db.WithContext(ctx).Where(query, args...).First(&wishlist)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting wishlist: %w", result.Error)
+	}
+
+	wishlists := []domain.Wishlist{wishlist}
+	if err := r.loadItems(ctx, wishlists); err != nil {
+		return nil, err
+	}
+	return &wishlists[0], nil
+}
+
+func (r *WishlistRepository) GetByUser(ctx context.Context, userID uint) ([]domain.Wishlist, error) {
+	var wishlists []domain.Wishlist
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&wishlists).Error; err != nil {
+		return nil, fmt.Errorf("error getting wishlists: %w", err)
+	}
+	if err := r.loadItems(ctx, wishlists); err != nil {
+		return nil, err
+	}
+	return wishlists, nil
+}
+
+func (r *WishlistRepository) CountByUser(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Wishlist{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("error counting wishlists: %w", err)
+	}
+	return count, nil
+}
+
+func (r *WishlistRepository) Update(ctx context.Context, wishlist *domain.Wishlist) error {
+	result := r.db.WithContext(ctx).Model(wishlist).Select("name", "public", "share_token", "updated_at").Updates(wishlist)
+	if result.Error != nil {
+		return fmt.Errorf("error updating wishlist: %w", translateError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("wishlist not found")
+	}
+	return nil
+}
+
+func (r *WishlistRepository) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("wishlist_id = ?", id).Delete(&domain.WishlistItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&domain.Wishlist{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("wishlist not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting wishlist: %w", err)
+	}
+	return nil
+}
+
+func (r *WishlistRepository) AddItem(ctx context.Context, item *domain.WishlistItem) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Wishlist{}).Where("id = ?", item.WishlistID).Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		return fmt.Errorf("error adding wishlist item: %w", translateError(err))
+	}
+	return nil
+}
+
+func (r *WishlistRepository) RemoveItem(ctx context.Context, wishlistID, productID uint) (bool, error) {
+	var removed bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("wishlist_id = ? AND product_id = ?", wishlistID, productID).Delete(&domain.WishlistItem{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		removed = true
+		return tx.Model(&domain.Wishlist{}).Where("id = ?", wishlistID).Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("error removing wishlist item: %w", err)
+	}
+	return removed, nil
+}
+
+func (r *WishlistRepository) GetSavedProductIDs(ctx context.Context, userID uint, productIDs []uint) ([]uint, error) {
+	if len(productIDs) == 0 {
+		return []uint{}, nil
+	}
+
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&domain.WishlistItem{}).
+		Joins("JOIN wishlists ON wishlists.id = wishlist_items.wishlist_id").
+		Where("wishlists.user_id = ? AND wishlist_items.product_id IN ?", userID, productIDs).
+		Distinct().
+		Pluck("wishlist_items.product_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("error getting saved products: %w", err)
+	}
+	return ids, nil
+}
+
+// loadItems fills the Items field of every wishlist in place.
+func (r *WishlistRepository) loadItems(ctx context.Context, wishlists []domain.Wishlist) error {
+	if len(wishlists) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(wishlists))
+	for i, wishlist := range wishlists {
+		ids[i] = wishlist.ID
+	}
+	var items []domain.WishlistItem
+	if err := r.db.WithContext(ctx).Where("wishlist_id IN ?", ids).Order("id").Find(&items).Error; err != nil {
+		return fmt.Errorf("error getting wishlist items: %w", err)
+	}
+
+	byWishlist := make(map[uint][]domain.WishlistItem, len(wishlists))
+	for _, item := range items {
+		byWishlist[item.WishlistID] = append(byWishlist[item.WishlistID], item)
+	}
+	for i := range wishlists {
+		wishlists[i].Items = byWishlist[wishlists[i].ID]
+		if wishlists[i].Items == nil {
+			wishlists[i].Items = []domain.WishlistItem{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/euro1061/gohex/internal/ports/storage"
+)
+
+func TestBlobStore(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := NewBlobStore(root, "/uploads/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const key = "products/1/abc.txt"
+	if err := store.Put(ctx, key, strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "products", "1", "abc.txt")); err != nil {
+		t.Errorf("stored file: %v", err)
+	}
+
+	body, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("Get() = %q, want %q", data, "hello")
+	}
+	if got, want := store.URL(key), "/uploads/products/1/abc.txt"; got != want {
+		t.Errorf("URL() = %q, want %q", got, want)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, storage.ErrBlobNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrBlobNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of a missing blob error = %v", err)
+	}
+}
+
+func TestBlobStoreRejectsEscapingKeys(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewBlobStore(filepath.Join(t.TempDir(), "blobs"), "/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"", ".", "..", "../outside.txt", "products/../../outside.txt", "/etc/passwd"} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("Put(%q) succeeded", key)
+		}
+	}
+}
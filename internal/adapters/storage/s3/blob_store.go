@@ -0,0 +1,144 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/euro1061/gohex/internal/ports/storage"
+)
+
+// Config describes an S3-compatible bucket. Requests use path-style URLs
+// ({Endpoint}/{Bucket}/{key}), which AWS S3, MinIO and most stand-ins accept.
+type Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL is the base URL objects are served from. It defaults to
+	// {Endpoint}/{Bucket}.
+	PublicURL string
+}
+
+// BlobStore stores objects in an S3-compatible bucket using Signature Version 4.
+type BlobStore struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewBlobStore(config Config, client *http.Client) (*BlobStore, error) {
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, errors.New("s3 endpoint and bucket are required")
+	}
+	if config.AccessKey == "" || config.SecretKey == "" {
+		return nil, errors.New("s3 access key and secret key are required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	if config.PublicURL == "" {
+		config.PublicURL = config.Endpoint + "/" + config.Bucket
+	}
+	config.PublicURL = strings.TrimRight(config.PublicURL, "/")
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &BlobStore{
+		config: config,
+		client: client,
+		now:    time.Now,
+	}, nil
+}
+
+func (s *BlobStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), body)
+	if err != nil {
+		return fmt.Errorf("error creating s3 request: %w", err)
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req, unsignedPayload)
+	if err != nil {
+		return fmt.Errorf("error uploading blob: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error uploading blob: %s", responseError(resp))
+	}
+	return nil
+}
+
+func (s *BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating s3 request: %w", err)
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, fmt.Errorf("error reading blob: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, storage.ErrBlobNotFound
+	}
+	defer resp.Body.Close()
+	return nil, fmt.Errorf("error reading blob: %s", responseError(resp))
+}
+
+func (s *BlobStore) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("error creating s3 request: %w", err)
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return fmt.Errorf("error deleting blob: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// S3 answers 204 whether or not the object existed
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("error deleting blob: %s", responseError(resp))
+	}
+	return nil
+}
+
+func (s *BlobStore) URL(key string) string {
+	return s.config.PublicURL + "/" + encodePath(key)
+}
+
+func (s *BlobStore) objectURL(key string) string {
+	return s.config.Endpoint + "/" + s.config.Bucket + "/" + encodePath(key)
+}
+
+func (s *BlobStore) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	sign(req, payloadHash, s.config.Region, s.config.AccessKey, s.config.SecretKey, s.now().UTC())
+	return s.client.Do(req)
+}
+
+// responseError summarizes a failed S3 response for error messages.
+func responseError(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if len(body) == 0 {
+		return resp.Status
+	}
+	return resp.Status + ": " + strings.TrimSpace(string(body))
+}
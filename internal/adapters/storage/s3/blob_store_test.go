@@ -0,0 +1,79 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/euro1061/gohex/internal/ports/storage"
+)
+
+func newTestStore(t *testing.T, server *httptest.Server, secretKey string) *BlobStore {
+	t.Helper()
+	store, err := NewBlobStore(Config{
+		Endpoint:  server.URL,
+		Bucket:    "bucket",
+		AccessKey: "access",
+		SecretKey: secretKey,
+	}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestBlobStore(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(newFakeServer("bucket", "", "access", "secret"))
+	defer server.Close()
+	store := newTestStore(t, server, "secret")
+
+	const key = "products/1/a b.txt"
+	if err := store.Put(ctx, key, strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	body, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("Get() = %q, want %q", data, "hello")
+	}
+	if got, want := store.URL(key), server.URL+"/bucket/products/1/a%20b.txt"; got != want {
+		t.Errorf("URL() = %q, want %q", got, want)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, storage.ErrBlobNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrBlobNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of a missing blob error = %v", err)
+	}
+}
+
+func TestBlobStoreWrongSecret(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeServer("bucket", "", "access", "secret")
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	store := newTestStore(t, server, "wrong")
+
+	if err := store.Put(ctx, "key", strings.NewReader("hello"), 5, "text/plain"); err == nil {
+		t.Error("Put() with a wrong secret succeeded")
+	}
+	if objects := fake.Objects(); len(objects) != 0 {
+		t.Errorf("stored objects = %v, want none", objects)
+	}
+}
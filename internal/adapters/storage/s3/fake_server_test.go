@@ -0,0 +1,124 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// fakeServer is an in-process stand-in for an S3-compatible service. It
+// supports path-style PUT, GET and DELETE object requests against a single
+// bucket and verifies their Signature Version 4 headers. Mount it with
+// httptest.NewServer to run BlobStore without a real bucket.
+type fakeServer struct {
+	bucket    string
+	region    string
+	accessKey string
+	secretKey string
+
+	mu      sync.RWMutex
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	body        []byte
+	contentType string
+}
+
+func newFakeServer(bucket, region, accessKey, secretKey string) *fakeServer {
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return &fakeServer{
+		bucket:    bucket,
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		objects:   make(map[string]fakeObject),
+	}
+}
+
+// Objects returns the keys currently stored, mainly for assertions.
+func (f *fakeServer) Objects() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !f.authorized(r) {
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+
+	prefix := "/" + f.bucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) || len(r.URL.Path) == len(prefix) {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		f.objects[key] = fakeObject{body: body, contentType: r.Header.Get("Content-Type")}
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		f.mu.RLock()
+		object, exists := f.objects[key]
+		f.mu.RUnlock()
+		if !exists {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		if object.contentType != "" {
+			w.Header().Set("Content-Type", object.contentType)
+		}
+		w.Write(object.body)
+	case http.MethodDelete:
+		f.mu.Lock()
+		delete(f.objects, key)
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authorized recomputes the request signature and compares it with the
+// Authorization header.
+func (f *fakeServer) authorized(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	amzDate := r.Header.Get("X-Amz-Date")
+	if !strings.HasPrefix(auth, signingAlgorithm+" ") || len(amzDate) != len(amzDateFormat) {
+		return false
+	}
+
+	fields := make(map[string]string)
+	for _, part := range strings.Split(strings.TrimPrefix(auth, signingAlgorithm+" "), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			fields[name] = value
+		}
+	}
+	if fields["Credential"] != f.accessKey+"/"+credentialScope(amzDate, f.region) {
+		return false
+	}
+
+	signedHeaders, expected := signature(r, r.Header.Get("X-Amz-Content-Sha256"), f.region, f.secretKey, amzDate)
+	return fields["SignedHeaders"] == signedHeaders && hmac.Equal([]byte(fields["Signature"]), []byte(expected))
+}
@@ -0,0 +1,414 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+var (
+	ErrCartItemNotFound    = notFoundError("cart item not found")
+	ErrInvalidCartQuantity = fieldError("quantity", "range", "cart item quantity must be between 1 and 1000")
+	ErrCartFull            = conflictError("a cart can hold at most 100 items")
+	ErrVariantRequired     = fieldError("variant_id", "required", "choose a variant of this product")
+	ErrEmptyCart           = validationError("cart is empty")
+)
+
+// CartOwner identifies a cart: the cart of UserID when it is set, otherwise
+// the guest cart of Token.
+type CartOwner struct {
+	UserID uint
+	Token  string
+}
+
+type CartService struct {
+	repo        repository.CartRepository
+	productRepo repository.ProductRepository
+	pricing     *PricingService
+	now         func() time.Time
+
+	// mu serializes changes to carts so concurrent requests do not lose items
+	mu sync.Mutex
+}
+
+func NewCartService(repo repository.CartRepository, productRepo repository.ProductRepository, pricing *PricingService) *CartService {
+	return &CartService{
+		repo:        repo,
+		productRepo: productRepo,
+		pricing:     pricing,
+		now:         time.Now,
+	}
+}
+
+// find returns the cart of owner, or nil when it has none yet.
+func (s *CartService) find(ctx context.Context, owner CartOwner) (*domain.Cart, error) {
+	if owner.UserID != 0 {
+		return s.repo.GetByUser(ctx, owner.UserID)
+	}
+	if owner.Token != "" {
+		return s.repo.GetByToken(ctx, owner.Token)
+	}
+	return nil, nil
+}
+
+// findOrCreate returns the cart of owner, creating it when needed. A new
+// guest cart gets a new token, which the caller hands to the guest.
+func (s *CartService) findOrCreate(ctx context.Context, owner CartOwner) (*domain.Cart, error) {
+	cart, err := s.find(ctx, owner)
+	if err != nil || cart != nil {
+		return cart, err
+	}
+
+	cart = &domain.Cart{}
+	if owner.UserID != 0 {
+		cart.UserID = &owner.UserID
+	} else {
+		token, err := newCartToken()
+		if err != nil {
+			return nil, err
+		}
+		cart.Token = &token
+	}
+	if err := s.repo.Create(ctx, cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// GetCart returns the cart of owner with its subtotal and the result of the
+// re-pricing check. An owner without a cart gets an empty one.
+func (s *CartService) GetCart(ctx context.Context, owner CartOwner) (*domain.Cart, error) {
+	cart, err := s.find(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		cart = emptyCart(owner)
+	}
+	if err := s.check(ctx, cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// emptyCart stands in for the cart of an owner who has none yet.
+func emptyCart(owner CartOwner) *domain.Cart {
+	cart := &domain.Cart{Items: []domain.CartItem{}}
+	if owner.UserID != 0 {
+		cart.UserID = &owner.UserID
+	}
+	return cart
+}
+
+// AddItem adds quantity units of a live product, or of one of its variants,
+// at the current catalog price. Adding an item already in the cart increases
+// its quantity and keeps its price.
+func (s *CartService) AddItem(ctx context.Context, owner CartOwner, item domain.LineItem) (*domain.Cart, error) {
+	if item.Quantity < 1 || item.Quantity > maxLineQuantity {
+		return nil, ErrInvalidCartQuantity
+	}
+	product, err := s.productRepo.GetByID(ctx, item.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	line, err := s.catalogItem(product, item.VariantID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cart, err := s.findOrCreate(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing := findCartItem(cart.Items, item.ProductID, item.VariantID); existing != nil {
+		if existing.Quantity+item.Quantity > maxLineQuantity {
+			return nil, ErrInvalidCartQuantity
+		}
+		existing.Quantity += item.Quantity
+	} else {
+		if len(cart.Items) >= maxLineItems {
+			return nil, ErrCartFull
+		}
+		line.Quantity = item.Quantity
+		line.AddedAt = s.now()
+		cart.Items = append(cart.Items, line)
+	}
+
+	if err := s.repo.Save(ctx, cart); err != nil {
+		return nil, err
+	}
+	return cart, s.check(ctx, cart)
+}
+
+// UpdateItem sets the quantity of a cart item.
+func (s *CartService) UpdateItem(ctx context.Context, owner CartOwner, itemID uint, quantity int) (*domain.Cart, error) {
+	if quantity < 1 || quantity > maxLineQuantity {
+		return nil, ErrInvalidCartQuantity
+	}
+	return s.change(ctx, owner, func(cart *domain.Cart) error {
+		for i := range cart.Items {
+			if cart.Items[i].ID == itemID {
+				cart.Items[i].Quantity = quantity
+				return nil
+			}
+		}
+		return ErrCartItemNotFound
+	})
+}
+
+func (s *CartService) RemoveItem(ctx context.Context, owner CartOwner, itemID uint) (*domain.Cart, error) {
+	return s.change(ctx, owner, func(cart *domain.Cart) error {
+		for i := range cart.Items {
+			if cart.Items[i].ID == itemID {
+				cart.Items = append(cart.Items[:i], cart.Items[i+1:]...)
+				return nil
+			}
+		}
+		return ErrCartItemNotFound
+	})
+}
+
+func (s *CartService) Clear(ctx context.Context, owner CartOwner) (*domain.Cart, error) {
+	return s.change(ctx, owner, func(cart *domain.Cart) error {
+		cart.Items = []domain.CartItem{}
+		return nil
+	})
+}
+
+// Reprice updates every available item to the current catalog price, so the
+// cart no longer reports price changes. Unavailable items are kept for the
+// shopper to remove.
+func (s *CartService) Reprice(ctx context.Context, owner CartOwner) (*domain.Cart, error) {
+	return s.change(ctx, owner, func(cart *domain.Cart) error {
+		products, err := s.products(ctx, cart)
+		if err != nil {
+			return err
+		}
+		for i := range cart.Items {
+			item := &cart.Items[i]
+			current, err := s.catalogItem(products[item.ProductID], item.VariantID)
+			if err == nil {
+				item.UnitPrice = current.UnitPrice
+				item.SKU = current.SKU
+				item.Name = current.Name
+			}
+		}
+		return nil
+	})
+}
+
+// Quote prices the cart through the pricing service, applying the
+// promotions in effect and coupon, and taxes it for address. The quote is in
+// currency, or in the base currency when it is empty.
+func (s *CartService) Quote(ctx context.Context, owner CartOwner, coupon string, address domain.TaxAddress, currency string) (*domain.PriceQuote, error) {
+	cart, err := s.find(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil || len(cart.Items) == 0 {
+		return nil, ErrEmptyCart
+	}
+
+	items := make([]domain.LineItem, len(cart.Items))
+	for i, item := range cart.Items {
+		items[i] = domain.LineItem{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity}
+	}
+	return s.pricing.PriceItems(ctx, items, coupon, owner.UserID, address, currency)
+}
+
+// MergeGuestCart moves the items of the guest cart of token into the cart of
+// a user and deletes the guest cart. Items already in the user cart keep
+// their price and add up their quantities, up to the item limit.
+func (s *CartService) MergeGuestCart(ctx context.Context, token string, userID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	guest, err := s.repo.GetByToken(ctx, token)
+	if err != nil || guest == nil {
+		return err
+	}
+	if len(guest.Items) > 0 {
+		cart, err := s.findOrCreate(ctx, CartOwner{UserID: userID})
+		if err != nil {
+			return err
+		}
+		for _, item := range guest.Items {
+			if existing := findCartItem(cart.Items, item.ProductID, item.VariantID); existing != nil {
+				existing.Quantity = min(existing.Quantity+item.Quantity, maxLineQuantity)
+				continue
+			}
+			if len(cart.Items) < maxLineItems {
+				item.ID = 0
+				cart.Items = append(cart.Items, item)
+			}
+		}
+		if err := s.repo.Save(ctx, cart); err != nil {
+			return err
+		}
+	}
+	return s.repo.Delete(ctx, guest.ID)
+}
+
+// StartPruner deletes guest carts left untouched for maxAge every interval
+// until the returned stop function is called, which also cancels a pass in
+// progress.
+func (s *CartService) StartPruner(interval, maxAge time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			if _, err := s.repo.DeleteGuestCarts(ctx, s.now().Add(-maxAge)); err != nil {
+				log.Printf("error deleting guest carts: %v", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return cancel
+}
+
+// change applies fn to the existing cart of owner and saves it.
+func (s *CartService) change(ctx context.Context, owner CartOwner, fn func(cart *domain.Cart) error) (*domain.Cart, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cart, err := s.find(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		// Nothing to save, but fn still reports missing items
+		cart = emptyCart(owner)
+		if err := fn(cart); err != nil {
+			return nil, err
+		}
+		return cart, s.check(ctx, cart)
+	}
+	if err := fn(cart); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, cart); err != nil {
+		return nil, err
+	}
+	return cart, s.check(ctx, cart)
+}
+
+// check computes the subtotal of cart and compares its items with the
+// catalog, listing price changes and items that can no longer be bought.
+func (s *CartService) check(ctx context.Context, cart *domain.Cart) error {
+	products, err := s.products(ctx, cart)
+	if err != nil {
+		return err
+	}
+
+	cart.Subtotal = 0
+	cart.Issues = []domain.CartIssue{}
+	for _, item := range cart.Items {
+		cart.Subtotal = domain.RoundMoney(cart.Subtotal + item.UnitPrice*float64(item.Quantity))
+
+		current, err := s.catalogItem(products[item.ProductID], item.VariantID)
+		if err != nil {
+			cart.Issues = append(cart.Issues, domain.CartIssue{
+				ItemID:    item.ID,
+				Type:      domain.CartItemUnavailable,
+				UnitPrice: item.UnitPrice,
+			})
+		} else if current.UnitPrice != item.UnitPrice {
+			price := current.UnitPrice
+			cart.Issues = append(cart.Issues, domain.CartIssue{
+				ItemID:       item.ID,
+				Type:         domain.CartPriceChanged,
+				UnitPrice:    item.UnitPrice,
+				CurrentPrice: &price,
+			})
+		}
+	}
+	return nil
+}
+
+// products loads the products in cart by ID.
+func (s *CartService) products(ctx context.Context, cart *domain.Cart) (map[uint]*domain.Product, error) {
+	ids := make([]uint, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		ids = append(ids, item.ProductID)
+	}
+	products, err := s.productRepo.GetByIDs(ctx, uniqueIDs(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*domain.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+	return byID, nil
+}
+
+// catalogItem returns a cart item for product, or its variant, at the
+// current catalog price. Only live products can be bought, and a product with
+// variants is bought as one of them.
+func (s *CartService) catalogItem(product *domain.Product, variantID *uint) (domain.CartItem, error) {
+	if product == nil || !product.IsLive(s.now()) {
+		return domain.CartItem{}, ErrProductNotFound
+	}
+
+	item := domain.CartItem{
+		ProductID: product.ID,
+		SKU:       product.SKU,
+		Name:      product.Name,
+		UnitPrice: product.Price,
+	}
+	if variantID == nil {
+		if len(product.Variants) > 0 {
+			return domain.CartItem{}, ErrVariantRequired
+		}
+		return item, nil
+	}
+
+	variant := findVariant(product.Variants, *variantID)
+	if variant == nil {
+		return domain.CartItem{}, ErrVariantNotFound
+	}
+	id := variant.ID
+	item.VariantID = &id
+	item.SKU = variant.SKU
+	item.UnitPrice = variant.Price
+	return item, nil
+}
+
+func findCartItem(items []domain.CartItem, productID uint, variantID *uint) *domain.CartItem {
+	for i := range items {
+		sameVariant := (items[i].VariantID == nil && variantID == nil) ||
+			(items[i].VariantID != nil && variantID != nil && *items[i].VariantID == *variantID)
+		if items[i].ProductID == productID && sameVariant {
+			return &items[i]
+		}
+	}
+	return nil
+}
+
+// newCartToken returns a random token identifying a guest cart.
+func newCartToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating cart token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,223 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+var (
+	ErrInvalidCategoryName    = fieldError("name", "required", "category name cannot be empty")
+	ErrCategoryNotFound       = notFoundError("category not found")
+	ErrInvalidCategoryID      = validationError("invalid category ID")
+	ErrParentCategoryNotFound = fieldError("parent_id", "exists", "parent category not found")
+	ErrCategoryCycle          = fieldError("parent_id", "acyclic", "category cannot be moved below itself")
+	ErrCategoryHasChildren    = conflictError("category has child categories")
+)
+
+type CategoryService struct {
+	repo        repository.CategoryRepository
+	productRepo repository.ProductRepository
+}
+
+func NewCategoryService(repo repository.CategoryRepository, productRepo repository.ProductRepository) *CategoryService {
+	return &CategoryService{
+		repo:        repo,
+		productRepo: productRepo,
+	}
+}
+
+func (s *CategoryService) validateCategory(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrInvalidCategoryName
+	}
+	return nil
+}
+
+// validateParent checks that parentID exists and, for an existing category,
+// that it is not the category itself or one of its descendants.
+func (s *CategoryService) validateParent(ctx context.Context, id uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+
+	parent, err := s.repo.GetByID(ctx, *parentID)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return ErrParentCategoryNotFound
+	}
+
+	if id == 0 {
+		return nil
+	}
+	descendants, err := s.repo.GetDescendantIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, descendantID := range descendants {
+		if descendantID == *parentID {
+			return ErrCategoryCycle
+		}
+	}
+	return nil
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, name, description string, parentID *uint) (*domain.Category, error) {
+	if err := s.validateCategory(name); err != nil {
+		return nil, err
+	}
+	if err := s.validateParent(ctx, 0, parentID); err != nil {
+		return nil, err
+	}
+
+	category := &domain.Category{
+		Name:        strings.TrimSpace(name),
+		Description: strings.TrimSpace(description),
+		ParentID:    parentID,
+	}
+
+	if err := s.repo.Create(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *CategoryService) GetCategory(ctx context.Context, id uint) (*domain.Category, error) {
+	if id == 0 {
+		return nil, ErrInvalidCategoryID
+	}
+
+	category, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+func (s *CategoryService) GetAllCategories(ctx context.Context) ([]domain.Category, error) {
+	categories, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if categories == nil {
+		return []domain.Category{}, nil
+	}
+	return categories, nil
+}
+
+func (s *CategoryService) GetChildren(ctx context.Context, id uint) ([]domain.Category, error) {
+	if _, err := s.GetCategory(ctx, id); err != nil {
+		return nil, err
+	}
+
+	children, err := s.repo.GetChildren(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if children == nil {
+		return []domain.Category{}, nil
+	}
+	return children, nil
+}
+
+func (s *CategoryService) UpdateCategory(ctx context.Context, category *domain.Category) error {
+	if category == nil {
+		return errors.New("category cannot be nil")
+	}
+
+	if err := s.validateCategory(category.Name); err != nil {
+		return err
+	}
+
+	if _, err := s.GetCategory(ctx, category.ID); err != nil {
+		return err
+	}
+	if err := s.validateParent(ctx, category.ID, category.ParentID); err != nil {
+		return err
+	}
+
+	category.Name = strings.TrimSpace(category.Name)
+	category.Description = strings.TrimSpace(category.Description)
+
+	return s.repo.Update(ctx, category)
+}
+
+func (s *CategoryService) DeleteCategory(ctx context.Context, id uint) error {
+	if _, err := s.GetCategory(ctx, id); err != nil {
+		return err
+	}
+
+	children, err := s.repo.GetChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return ErrCategoryHasChildren
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *CategoryService) GetProductCategories(ctx context.Context, productID uint) ([]domain.Category, error) {
+	if err := s.ensureProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.GetProductCategories(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if categories == nil {
+		return []domain.Category{}, nil
+	}
+	return categories, nil
+}
+
+// SetProductCategories replaces the categories a product belongs to.
+func (s *CategoryService) SetProductCategories(ctx context.Context, productID uint, categoryIDs []uint) ([]domain.Category, error) {
+	if err := s.ensureProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	unique := make([]uint, 0, len(categoryIDs))
+	seen := make(map[uint]struct{}, len(categoryIDs))
+	for _, categoryID := range categoryIDs {
+		if _, ok := seen[categoryID]; ok {
+			continue
+		}
+		if _, err := s.GetCategory(ctx, categoryID); err != nil {
+			return nil, err
+		}
+		seen[categoryID] = struct{}{}
+		unique = append(unique, categoryID)
+	}
+
+	if err := s.repo.SetProductCategories(ctx, productID, unique); err != nil {
+		return nil, err
+	}
+	return s.repo.GetProductCategories(ctx, productID)
+}
+
+func (s *CategoryService) ensureProduct(ctx context.Context, productID uint) error {
+	if productID == 0 {
+		return ErrInvalidProductID
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return ErrProductNotFound
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/currency"
+)
+
+var (
+	ErrInvalidCurrency     = fieldError("currency", "iso4217", "currency must be a three letter ISO 4217 code")
+	ErrUnsupportedCurrency = fieldError("currency", "supported", "no exchange rate is available for this currency")
+)
+
+// DefaultRoundingIncrement rounds converted prices to cents.
+const DefaultRoundingIncrement = 0.01
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// CurrencySettings describe the catalog currency and how prices converted
+// from it are rounded.
+type CurrencySettings struct {
+	// Base is the currency of catalog prices, DefaultCurrency when empty
+	Base     string
+	Rounding domain.RoundingMode
+	// Increments are the rounding steps per currency, such as 1 to round
+	// to whole units. Other currencies round to DefaultRoundingIncrement.
+	Increments map[string]float64
+}
+
+// CurrencyService converts catalog prices from the base currency using the
+// rates of an exchange rate provider.
+type CurrencyService struct {
+	rates    currency.ExchangeRateProvider
+	settings CurrencySettings
+}
+
+func NewCurrencyService(rates currency.ExchangeRateProvider, settings CurrencySettings) *CurrencyService {
+	settings.Base = strings.ToUpper(strings.TrimSpace(settings.Base))
+	if settings.Base == "" {
+		settings.Base = DefaultCurrency
+	}
+	if !settings.Rounding.Valid() {
+		settings.Rounding = domain.RoundNearest
+	}
+	increments := make(map[string]float64, len(settings.Increments))
+	for code, increment := range settings.Increments {
+		if increment > 0 {
+			increments[strings.ToUpper(code)] = increment
+		}
+	}
+	settings.Increments = increments
+
+	return &CurrencyService{
+		rates:    rates,
+		settings: settings,
+	}
+}
+
+// Base returns the currency of catalog prices.
+func (s *CurrencyService) Base() string {
+	return s.settings.Base
+}
+
+// Exchange returns the conversion from the base currency to code at the
+// current rate. An empty code selects the base currency.
+func (s *CurrencyService) Exchange(ctx context.Context, code string) (*Exchange, error) {
+	code, err := normalizeCurrency(code)
+	if err != nil {
+		return nil, err
+	}
+	exchange := &Exchange{
+		Base:      s.settings.Base,
+		Currency:  s.settings.Base,
+		Rate:      1,
+		rounding:  s.settings.Rounding,
+		increment: DefaultRoundingIncrement,
+	}
+	if code == "" || code == s.settings.Base {
+		return exchange, nil
+	}
+
+	rate, err := s.rates.Rate(ctx, s.settings.Base, code)
+	if err != nil {
+		if errors.Is(err, currency.ErrRateNotFound) {
+			return nil, ErrUnsupportedCurrency
+		}
+		return nil, err
+	}
+	exchange.Currency = code
+	exchange.Rate = rate
+	if increment, ok := s.settings.Increments[code]; ok {
+		exchange.increment = increment
+	}
+	return exchange, nil
+}
+
+// LocalizeProducts shows the prices of products in code. Nothing changes
+// for the base currency.
+func (s *CurrencyService) LocalizeProducts(ctx context.Context, code string, products ...*domain.Product) error {
+	exchange, err := s.Exchange(ctx, code)
+	if err != nil {
+		return err
+	}
+	if exchange.IsBase() {
+		return nil
+	}
+	for _, product := range products {
+		product.Price = exchange.ProductPrice(product)
+		for i := range product.Variants {
+			product.Variants[i].Price = exchange.Convert(product.Variants[i].Price)
+		}
+		product.Currency = exchange.Currency
+	}
+	return nil
+}
+
+// Exchange converts amounts from Base to Currency at a fixed Rate.
+type Exchange struct {
+	Base     string
+	Currency string
+	Rate     float64
+
+	rounding  domain.RoundingMode
+	increment float64
+}
+
+// IsBase reports whether amounts are left in the base currency.
+func (e *Exchange) IsBase() bool {
+	return e.Currency == e.Base
+}
+
+// Convert converts a base currency amount and rounds it.
+func (e *Exchange) Convert(amount float64) float64 {
+	if e.IsBase() {
+		return amount
+	}
+	return e.rounding.Round(amount*e.Rate, e.increment)
+}
+
+// ProductPrice returns the price of product in the currency: its set price
+// for the currency when it has one, or its converted price.
+func (e *Exchange) ProductPrice(product *domain.Product) float64 {
+	if price, ok := product.CurrencyPrices[e.Currency]; ok && !e.IsBase() {
+		return price
+	}
+	return e.Convert(product.Price)
+}
+
+// normalizeCurrency trims and uppercases a currency code. An empty code is
+// returned as is.
+func normalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code != "" && !currencyPattern.MatchString(code) {
+		return "", ErrInvalidCurrency
+	}
+	return code, nil
+}
+
+// normalizeCurrencyPrices uppercases the currency codes of prices, returning
+// nil for no prices.
+func normalizeCurrencyPrices(prices map[string]float64) (map[string]float64, error) {
+	if len(prices) == 0 {
+		return nil, nil
+	}
+	normalized := make(map[string]float64, len(prices))
+	for code, price := range prices {
+		code, err := normalizeCurrency(code)
+		if err != nil || code == "" || price <= 0 {
+			return nil, ErrInvalidCurrencyPrice
+		}
+		if _, exists := normalized[code]; exists {
+			return nil, ErrInvalidCurrencyPrice
+		}
+		normalized[code] = price
+	}
+	return normalized, nil
+}
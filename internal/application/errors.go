@@ -0,0 +1,104 @@
+package application
+
+import "errors"
+
+// ErrorKind classifies the errors of the application services by what went
+// wrong, so adapters can react to an error without knowing each one.
+type ErrorKind int
+
+const (
+	// KindInternal is an unexpected failure such as a database error. Its
+	// details are not meant for clients.
+	KindInternal ErrorKind = iota
+	// KindValidation is input that breaks a rule.
+	KindValidation
+	// KindNotFound is a reference to something that does not exist.
+	KindNotFound
+	// KindConflict is a change the current state does not allow, such as a
+	// duplicate or a status change out of order.
+	KindConflict
+	// KindUnauthorized is missing or invalid credentials.
+	KindUnauthorized
+	// KindForbidden is a change the caller's role or ownership does not allow.
+	KindForbidden
+	// KindTooLarge is an upload over its size limit.
+	KindTooLarge
+	// KindUnsupported is an upload in a format that is not supported.
+	KindUnsupported
+)
+
+// Error is an error of a known kind. Its message is meant for clients.
+type Error struct {
+	Kind    ErrorKind
+	Message string
+	// Fields lists the input fields a validation error is about, if known.
+	Fields []FieldError
+}
+
+// FieldError says which rule the value of an input field broke. Field is the
+// field's name in requests, such as "price" or "items[0].quantity".
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// KindOf returns the kind of the first Error in the chain of err, or
+// KindInternal when there is none.
+func KindOf(err error) ErrorKind {
+	var kinded *Error
+	if errors.As(err, &kinded) {
+		return kinded.Kind
+	}
+	return KindInternal
+}
+
+// FieldsOf returns the field errors of the first Error in the chain of err.
+func FieldsOf(err error) []FieldError {
+	var kinded *Error
+	if errors.As(err, &kinded) {
+		return kinded.Fields
+	}
+	return nil
+}
+
+func validationError(message string) error {
+	return &Error{Kind: KindValidation, Message: message}
+}
+
+// fieldError returns a validation error about the value of one input field.
+func fieldError(field, rule, message string) error {
+	return &Error{
+		Kind:    KindValidation,
+		Message: message,
+		Fields:  []FieldError{{Field: field, Rule: rule, Message: message}},
+	}
+}
+
+func notFoundError(message string) error {
+	return &Error{Kind: KindNotFound, Message: message}
+}
+
+func conflictError(message string) error {
+	return &Error{Kind: KindConflict, Message: message}
+}
+
+func unauthorizedError(message string) error {
+	return &Error{Kind: KindUnauthorized, Message: message}
+}
+
+func forbiddenError(message string) error {
+	return &Error{Kind: KindForbidden, Message: message}
+}
+
+func tooLargeError(message string) error {
+	return &Error{Kind: KindTooLarge, Message: message}
+}
+
+func unsupportedError(message string) error {
+	return &Error{Kind: KindUnsupported, Message: message}
+}
@@ -0,0 +1,93 @@
+package application
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif" // Register the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Register the WebP decoder
+)
+
+const (
+	thumbnailSize  = 320
+	thumbnailJPEG  = 85
+	maxImagePixels = 40_000_000
+)
+
+// imageExtensions lists the accepted image types with their file extensions.
+var imageExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// sniffImage detects the content type of data from its leading bytes and
+// returns it with the file extension to use, or ErrUnsupportedImageType.
+func sniffImage(data []byte) (string, string, error) {
+	contentType := http.DetectContentType(data)
+	extension, ok := imageExtensions[contentType]
+	if !ok {
+		return "", "", ErrUnsupportedImageType
+	}
+	return contentType, extension, nil
+}
+
+// decodeImage decodes data after checking that its dimensions are reasonable,
+// so a small file cannot expand into a huge bitmap.
+func decodeImage(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, ErrImageDimensions
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	return img, nil
+}
+
+// thumbnail scales img to fit within thumbnailSize on both sides, keeping its
+// aspect ratio, and encodes it as PNG for PNG sources and JPEG otherwise.
+// It returns the encoded bytes, their content type and file extension.
+func thumbnail(img image.Image, sourceType string) ([]byte, string, string, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > thumbnailSize || height > thumbnailSize {
+		if width >= height {
+			height = max(1, height*thumbnailSize/width)
+			width = thumbnailSize
+		} else {
+			width = max(1, width*thumbnailSize/height)
+			height = thumbnailSize
+		}
+	}
+
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, bounds, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if sourceType == "image/png" {
+		if err := png.Encode(&buf, scaled); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "image/png", "png", nil
+	}
+
+	// JPEG has no alpha channel, so flatten onto white first
+	flat := image.NewRGBA(scaled.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), scaled, image.Point{}, draw.Over)
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: thumbnailJPEG}); err != nil {
+		return nil, "", "", err
+	}
+	return buf.Bytes(), "image/jpeg", "jpg", nil
+}
@@ -0,0 +1,234 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+var (
+	ErrInvalidQuantity      = fieldError("quantity", "gt", "quantity must be greater than 0")
+	ErrInvalidAdjustment    = fieldError("quantity", "ne", "adjustment quantity cannot be 0")
+	ErrInvalidThreshold     = fieldError("threshold", "gte", "low stock threshold cannot be negative")
+	ErrInvalidWarehouse     = fieldError("warehouse", "max", "warehouse code must be at most 50 characters")
+	ErrInsufficientStock    = conflictError("insufficient stock available")
+	ErrInsufficientReserved = conflictError("quantity exceeds reserved stock")
+	ErrStockBelowReserved   = conflictError("on hand stock cannot fall below reserved stock")
+)
+
+const (
+	maxWarehouseLength    = 50
+	defaultMovementsLimit = 50
+	maxMovementsLimit     = 500
+)
+
+type InventoryService struct {
+	repo        repository.InventoryRepository
+	productRepo repository.ProductRepository
+}
+
+func NewInventoryService(repo repository.InventoryRepository, productRepo repository.ProductRepository) *InventoryService {
+	return &InventoryService{
+		repo:        repo,
+		productRepo: productRepo,
+	}
+}
+
+// withRepos returns a copy of the service that uses repos, typically ones
+// bound to a transaction.
+func (s *InventoryService) withRepos(repos repository.Repositories) *InventoryService {
+	clone := *s
+	clone.repo = repos.Inventory
+	clone.productRepo = repos.Products
+	return &clone
+}
+
+// normalizeWarehouse trims a warehouse code and falls back to domain.DefaultWarehouse.
+func normalizeWarehouse(warehouse string) (string, error) {
+	warehouse = strings.ToLower(strings.TrimSpace(warehouse))
+	if warehouse == "" {
+		return domain.DefaultWarehouse, nil
+	}
+	if len(warehouse) > maxWarehouseLength {
+		return "", ErrInvalidWarehouse
+	}
+	return warehouse, nil
+}
+
+func (s *InventoryService) ensureProduct(ctx context.Context, productID uint) error {
+	if productID == 0 {
+		return ErrInvalidProductID
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+// change validates the product and warehouse, then applies change under the repository lock.
+func (s *InventoryService) change(ctx context.Context, productID uint, warehouse string, change repository.StockChange) (*domain.StockLevel, error) {
+	if err := s.ensureProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	warehouse, err := normalizeWarehouse(warehouse)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Change(ctx, productID, warehouse, change)
+}
+
+// GetAvailability returns the stock of a product in every warehouse along with totals.
+func (s *InventoryService) GetAvailability(ctx context.Context, productID uint) (*domain.StockAvailability, error) {
+	if err := s.ensureProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	levels, err := s.repo.GetProductStock(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if levels == nil {
+		levels = []domain.StockLevel{}
+	}
+
+	availability := &domain.StockAvailability{
+		ProductID:  productID,
+		Warehouses: levels,
+	}
+	for _, level := range levels {
+		availability.OnHand += level.OnHand
+		availability.Reserved += level.Reserved
+	}
+	availability.Available = availability.OnHand - availability.Reserved
+	availability.InStock = availability.Available > 0
+	return availability, nil
+}
+
+func (s *InventoryService) GetLowStock(ctx context.Context) ([]domain.StockLevel, error) {
+	levels, err := s.repo.GetLowStock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if levels == nil {
+		return []domain.StockLevel{}, nil
+	}
+	return levels, nil
+}
+
+func (s *InventoryService) GetMovements(ctx context.Context, productID uint, limit int) ([]domain.StockMovement, error) {
+	if err := s.ensureProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultMovementsLimit
+	}
+	if limit > maxMovementsLimit {
+		limit = maxMovementsLimit
+	}
+
+	movements, err := s.repo.GetMovements(ctx, productID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if movements == nil {
+		return []domain.StockMovement{}, nil
+	}
+	return movements, nil
+}
+
+// Adjust adds quantity (which may be negative) to the on hand stock, for
+// example after receiving goods or a stock count.
+func (s *InventoryService) Adjust(ctx context.Context, productID uint, warehouse string, quantity int, reason string) (*domain.StockLevel, error) {
+	if quantity == 0 {
+		return nil, ErrInvalidAdjustment
+	}
+
+	return s.change(ctx, productID, warehouse, func(level *domain.StockLevel) (*domain.StockMovement, error) {
+		if level.OnHand+quantity < level.Reserved {
+			return nil, ErrStockBelowReserved
+		}
+		level.OnHand += quantity
+		return &domain.StockMovement{
+			Type:        domain.StockMovementAdjustment,
+			OnHandDelta: quantity,
+			Reason:      strings.TrimSpace(reason),
+		}, nil
+	})
+}
+
+// Reserve holds quantity units for reference, failing if not enough stock is available.
+func (s *InventoryService) Reserve(ctx context.Context, productID uint, warehouse string, quantity int, reference string) (*domain.StockLevel, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	return s.change(ctx, productID, warehouse, func(level *domain.StockLevel) (*domain.StockMovement, error) {
+		if level.Available() < quantity {
+			return nil, ErrInsufficientStock
+		}
+		level.Reserved += quantity
+		return &domain.StockMovement{
+			Type:          domain.StockMovementReserve,
+			ReservedDelta: quantity,
+			Reference:     strings.TrimSpace(reference),
+		}, nil
+	})
+}
+
+// Release returns previously reserved units to the available stock.
+func (s *InventoryService) Release(ctx context.Context, productID uint, warehouse string, quantity int, reference string) (*domain.StockLevel, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	return s.change(ctx, productID, warehouse, func(level *domain.StockLevel) (*domain.StockMovement, error) {
+		if level.Reserved < quantity {
+			return nil, ErrInsufficientReserved
+		}
+		level.Reserved -= quantity
+		return &domain.StockMovement{
+			Type:          domain.StockMovementRelease,
+			ReservedDelta: -quantity,
+			Reference:     strings.TrimSpace(reference),
+		}, nil
+	})
+}
+
+// Commit removes previously reserved units from stock once they have been sold.
+func (s *InventoryService) Commit(ctx context.Context, productID uint, warehouse string, quantity int, reference string) (*domain.StockLevel, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	return s.change(ctx, productID, warehouse, func(level *domain.StockLevel) (*domain.StockMovement, error) {
+		if level.Reserved < quantity {
+			return nil, ErrInsufficientReserved
+		}
+		level.Reserved -= quantity
+		level.OnHand -= quantity
+		return &domain.StockMovement{
+			Type:          domain.StockMovementCommit,
+			OnHandDelta:   -quantity,
+			ReservedDelta: -quantity,
+			Reference:     strings.TrimSpace(reference),
+		}, nil
+	})
+}
+
+func (s *InventoryService) SetThreshold(ctx context.Context, productID uint, warehouse string, threshold int) (*domain.StockLevel, error) {
+	if threshold < 0 {
+		return nil, ErrInvalidThreshold
+	}
+
+	return s.change(ctx, productID, warehouse, func(level *domain.StockLevel) (*domain.StockMovement, error) {
+		level.LowStockThreshold = threshold
+		return nil, nil
+	})
+}
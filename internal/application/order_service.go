@@ -0,0 +1,383 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+var (
+	ErrOrderNotFound          = notFoundError("order not found")
+	ErrInvalidOrderStatus     = fieldError("status", "oneof", "order status must be pending, paid, fulfilled, shipped, delivered, cancelled or refunded")
+	ErrInvalidOrderTransition = conflictError("order cannot move to this status from its current status")
+	ErrCartOutdated           = conflictError("cart has items whose price changed or that are no longer available; review the cart before checking out")
+	ErrOrderNotCancellable    = conflictError("only pending orders can be cancelled")
+)
+
+// orderTransitions lists the statuses each order status can move to.
+var orderTransitions = map[domain.OrderStatus][]domain.OrderStatus{
+	domain.OrderPending:   {domain.OrderPaid, domain.OrderCancelled},
+	domain.OrderPaid:      {domain.OrderFulfilled, domain.OrderRefunded},
+	domain.OrderFulfilled: {domain.OrderShipped, domain.OrderRefunded},
+	domain.OrderShipped:   {domain.OrderDelivered, domain.OrderRefunded},
+	domain.OrderDelivered: {domain.OrderRefunded},
+}
+
+type OrderService struct {
+	repo      repository.OrderRepository
+	carts     *CartService
+	inventory *InventoryService
+	pricing   *PricingService
+	uow       repository.UnitOfWork
+	now       func() time.Time
+}
+
+func NewOrderService(repo repository.OrderRepository, carts *CartService, inventory *InventoryService, pricing *PricingService, uow repository.UnitOfWork) *OrderService {
+	return &OrderService{
+		repo:      repo,
+		carts:     carts,
+		inventory: inventory,
+		pricing:   pricing,
+		uow:       uow,
+		now:       time.Now,
+	}
+}
+
+// Checkout places an order for the cart of user. The cart must match the
+// catalog: an item whose price changed or that can no longer be bought fails
+// the checkout until the cart is repriced or the item removed. Stock is
+// reserved for every item, the promotions applied are redeemed, the order is
+// stored and the cart emptied in one transaction, so if any step fails none
+// of them is kept. The transaction locks the cart, so a checkout of the same
+// cart running alongside fails with ErrEmptyCart or ErrCartOutdated instead
+// of placing a second order. The order is taxed for address and priced in
+// currency, or in the base currency when it is empty, recording the exchange
+// rate used.
+func (s *OrderService) Checkout(ctx context.Context, user *domain.User, coupon string, address domain.TaxAddress, currency string) (*domain.Order, error) {
+	owner := CartOwner{UserID: user.ID}
+	cart, err := s.carts.GetCart(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, ErrEmptyCart
+	}
+	if len(cart.Issues) > 0 {
+		return nil, ErrCartOutdated
+	}
+
+	items := make([]domain.LineItem, len(cart.Items))
+	for i, item := range cart.Items {
+		items[i] = domain.LineItem{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity}
+	}
+	quote, err := s.pricing.PriceItems(ctx, items, coupon, user.ID, address, currency)
+	if err != nil {
+		return nil, err
+	}
+	// The catalog may have changed since the cart was checked
+	for i, line := range quote.Lines {
+		if line.BaseUnitPrice != cart.Items[i].UnitPrice {
+			return nil, ErrCartOutdated
+		}
+	}
+
+	number, err := newOrderNumber(s.now())
+	if err != nil {
+		return nil, err
+	}
+	order := newOrder(number, user, quote)
+
+	err = s.uow.Transaction(ctx, func(repos repository.Repositories) error {
+		locked, err := repos.Carts.LockByUser(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if locked == nil || len(locked.Items) == 0 {
+			return ErrEmptyCart
+		}
+		if !sameCartItems(locked.Items, cart.Items) {
+			return ErrCartOutdated
+		}
+
+		if err := reserveStock(ctx, s.inventory.withRepos(repos), order); err != nil {
+			return err
+		}
+		if err := s.pricing.withRepos(repos).Redeem(ctx, quote, user.ID, number); err != nil {
+			return err
+		}
+		if err := repos.Orders.Create(ctx, order); err != nil {
+			return err
+		}
+		locked.Items = []domain.CartItem{}
+		return repos.Carts.Save(ctx, locked)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// sameCartItems reports whether a cart still holds the items that were
+// priced. An item keeps its product and variant, so only its quantity and
+// price can change.
+func sameCartItems(items, priced []domain.CartItem) bool {
+	if len(items) != len(priced) {
+		return false
+	}
+	for i := range items {
+		if items[i].ID != priced[i].ID || items[i].Quantity != priced[i].Quantity || items[i].UnitPrice != priced[i].UnitPrice {
+			return false
+		}
+	}
+	return true
+}
+
+// newOrder builds a pending order from a price quote.
+func newOrder(number string, user *domain.User, quote *domain.PriceQuote) *domain.Order {
+	order := &domain.Order{
+		Number:     number,
+		UserID:     user.ID,
+		Status:     domain.OrderPending,
+		Items:      make([]domain.OrderItem, len(quote.Lines)),
+		Coupon:     quote.Coupon,
+		Promotions: quote.Applied,
+		Subtotal:   quote.Subtotal,
+		Discount:   quote.Discount,
+		Tax:        quote.Tax,
+		Total:      quote.Total,
+
+		TaxAddress:       quote.TaxAddress,
+		PricesIncludeTax: quote.PricesIncludeTax,
+		Currency:         quote.Currency,
+		BaseCurrency:     quote.BaseCurrency,
+		ExchangeRate:     quote.ExchangeRate,
+		History: []domain.OrderEvent{{
+			To:    domain.OrderPending,
+			Actor: user.Username,
+		}},
+	}
+	for i, line := range quote.Lines {
+		order.Items[i] = domain.OrderItem{
+			ProductID: line.ProductID,
+			VariantID: line.VariantID,
+			SKU:       line.SKU,
+			Name:      line.Name,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+			Subtotal:  line.Subtotal,
+			Discount:  line.Discount,
+			TaxRate:   line.TaxRate,
+			Tax:       line.Tax,
+			Total:     line.Total,
+		}
+	}
+	return order
+}
+
+// stockQuantity is the number of units of a product an order takes from stock.
+type stockQuantity struct {
+	productID uint
+	quantity  int
+}
+
+// stockQuantities adds up the items of order per product, variants included,
+// since stock is kept per product. They are ordered by product so concurrent
+// orders lock stock levels in the same order.
+func stockQuantities(order *domain.Order) []stockQuantity {
+	totals := make(map[uint]int)
+	for _, item := range order.Items {
+		totals[item.ProductID] += item.Quantity
+	}
+	quantities := make([]stockQuantity, 0, len(totals))
+	for productID, quantity := range totals {
+		quantities = append(quantities, stockQuantity{productID: productID, quantity: quantity})
+	}
+	sort.Slice(quantities, func(i, j int) bool {
+		return quantities[i].productID < quantities[j].productID
+	})
+	return quantities
+}
+
+// reserveStock holds stock for every item of order. Run it in a transaction:
+// when a reservation fails, those already made are not released.
+func reserveStock(ctx context.Context, inventory *InventoryService, order *domain.Order) error {
+	for _, q := range stockQuantities(order) {
+		if _, err := inventory.Reserve(ctx, q.productID, "", q.quantity, order.Number); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseStock returns the stock reserved for every item of order. Run it in
+// a transaction with the status change that gives the stock back.
+func releaseStock(ctx context.Context, inventory *InventoryService, order *domain.Order) error {
+	for _, q := range stockQuantities(order) {
+		if _, err := inventory.Release(ctx, q.productID, "", q.quantity, order.Number); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commitStock removes the reserved stock of an order that left the
+// warehouse. Like releaseStock, run it in a transaction with the status
+// change.
+func commitStock(ctx context.Context, inventory *InventoryService, order *domain.Order) error {
+	for _, q := range stockQuantities(order) {
+		if _, err := inventory.Commit(ctx, q.productID, "", q.quantity, order.Number); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetOrder returns an order to its owner or an admin. Other users get
+// ErrOrderNotFound, so they cannot learn which orders exist.
+func (s *OrderService) GetOrder(ctx context.Context, id uint, user *domain.User) (*domain.Order, error) {
+	order, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || (order.UserID != user.ID && !user.HasRole(domain.RoleAdmin)) {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// GetUserOrders returns the orders of a user, newest first.
+func (s *OrderService) GetUserOrders(ctx context.Context, userID uint) ([]domain.Order, error) {
+	orders, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if orders == nil {
+		return []domain.Order{}, nil
+	}
+	return orders, nil
+}
+
+// GetOrders returns the orders in status, or every order when status is empty.
+func (s *OrderService) GetOrders(ctx context.Context, status domain.OrderStatus) ([]domain.Order, error) {
+	if status != "" && !status.Valid() {
+		return nil, ErrInvalidOrderStatus
+	}
+	orders, err := s.repo.GetAll(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	if orders == nil {
+		return []domain.Order{}, nil
+	}
+	return orders, nil
+}
+
+// Cancel cancels a pending order on behalf of its owner or an admin.
+func (s *OrderService) Cancel(ctx context.Context, id uint, user *domain.User, reason string) (*domain.Order, error) {
+	order, err := s.GetOrder(ctx, id, user)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != domain.OrderPending {
+		return nil, ErrOrderNotCancellable
+	}
+	return s.transition(ctx, order, domain.OrderCancelled, Change{Actor: user.Username, Reason: reason})
+}
+
+// SetStatus moves an order to status, following the order lifecycle.
+// Cancelling or refunding an order that has not been fulfilled releases its
+// stock; fulfilling it removes the stock it reserved. The stock moves in the
+// transaction that changes the status, so if it cannot move the status stays.
+func (s *OrderService) SetStatus(ctx context.Context, id uint, status domain.OrderStatus, change Change) (*domain.Order, error) {
+	if !status.Valid() {
+		return nil, ErrInvalidOrderStatus
+	}
+	order, err := s.order(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, order, status, change)
+}
+
+// order returns an order regardless of who asks, or ErrOrderNotFound.
+func (s *OrderService) order(ctx context.Context, id uint) (*domain.Order, error) {
+	order, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (s *OrderService) transition(ctx context.Context, order *domain.Order, status domain.OrderStatus, change Change) (*domain.Order, error) {
+	if !containsOrderStatus(orderTransitions[order.Status], status) {
+		return nil, ErrInvalidOrderTransition
+	}
+
+	from := order.Status
+	err := s.uow.Transaction(ctx, func(repos repository.Repositories) error {
+		updated, err := repos.Orders.UpdateStatus(ctx, &domain.OrderEvent{
+			OrderID: order.ID,
+			From:    from,
+			To:      status,
+			Actor:   change.actor(),
+			Note:    strings.TrimSpace(change.Reason),
+		})
+		if err != nil {
+			return err
+		}
+		if !updated {
+			// Another request changed the order first
+			return ErrInvalidOrderTransition
+		}
+
+		if status == domain.OrderCancelled || status == domain.OrderRefunded {
+			// The customer did not get the discount after all
+			if err := s.pricing.withRepos(repos).Unredeem(ctx, order.Number); err != nil {
+				return err
+			}
+		}
+
+		inventory := s.inventory.withRepos(repos)
+		switch {
+		case status == domain.OrderFulfilled:
+			return commitStock(ctx, inventory, order)
+		case status == domain.OrderCancelled,
+			status == domain.OrderRefunded && from == domain.OrderPaid:
+			return releaseStock(ctx, inventory, order)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, order.ID)
+}
+
+func containsOrderStatus(statuses []domain.OrderStatus, status domain.OrderStatus) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
+
+// newOrderNumber returns a random, human readable order number such as
+// ORD-20240131-K3J8Q2ZA.
+func newOrderNumber(now time.Time) (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating order number: %w", err)
+	}
+	return "ORD-" + now.UTC().Format("20060102") + "-" + base32.StdEncoding.EncodeToString(b), nil
+}
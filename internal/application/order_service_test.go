@@ -0,0 +1,217 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/euro1061/gohex/internal/adapters/repository/memory"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+// orderFixture is a pending order for two units of a product with ten in
+// stock.
+type orderFixture struct {
+	repos     repository.Repositories
+	inventory *InventoryService
+	carts     *CartService
+	orders    *OrderService
+	product   *domain.Product
+	user      *domain.User
+	order     *domain.Order
+}
+
+func newOrderFixture(t *testing.T) *orderFixture {
+	t.Helper()
+	ctx := context.Background()
+	f := &orderFixture{repos: memoryRepositories()}
+
+	pricing := NewPricingService(f.repos.Promotions, f.repos.Products, f.repos.Categories,
+		NewTaxService(f.repos.TaxRates, TaxSettings{}), NewCurrencyService(noRates{}, CurrencySettings{}))
+	f.carts = NewCartService(f.repos.Carts, f.repos.Products, pricing)
+	f.inventory = NewInventoryService(f.repos.Inventory, f.repos.Products)
+	f.orders = NewOrderService(f.repos.Orders, f.carts, f.inventory, pricing, memory.NewUnitOfWork(f.repos))
+
+	f.product = &domain.Product{
+		SKU:      "TEE-1",
+		Slug:     "tee",
+		Name:     "Tee",
+		Price:    20,
+		TaxClass: domain.DefaultTaxClass,
+		Status:   domain.ProductPublished,
+	}
+	if err := f.repos.Products.Create(ctx, f.product); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.inventory.Adjust(ctx, f.product.ID, "", 10, "initial stock"); err != nil {
+		t.Fatal(err)
+	}
+
+	f.user = &domain.User{Username: "alice", Role: domain.RoleCustomer}
+	f.user.ID = 1
+	f.order = f.checkout(t, "")
+	return f
+}
+
+// checkout orders two units of the product with coupon.
+func (f *orderFixture) checkout(t *testing.T, coupon string) *domain.Order {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := f.carts.AddItem(ctx, CartOwner{UserID: f.user.ID}, domain.LineItem{ProductID: f.product.ID, Quantity: 2}); err != nil {
+		t.Fatal(err)
+	}
+	order, err := f.orders.Checkout(ctx, f.user, coupon, domain.TaxAddress{}, "")
+	if err != nil {
+		t.Fatalf("Checkout(%q) error = %v", coupon, err)
+	}
+	return order
+}
+
+// stock returns the on hand and reserved units of the product.
+func (f *orderFixture) stock(t *testing.T) (int, int) {
+	t.Helper()
+	availability, err := f.inventory.GetAvailability(context.Background(), f.product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return availability.OnHand, availability.Reserved
+}
+
+func TestSetStatusMovesStock(t *testing.T) {
+	tests := []struct {
+		name         string
+		steps        []domain.OrderStatus
+		wantOnHand   int
+		wantReserved int
+	}{
+		{"cancel releases", []domain.OrderStatus{domain.OrderCancelled}, 10, 0},
+		{"fulfil commits", []domain.OrderStatus{domain.OrderPaid, domain.OrderFulfilled}, 8, 0},
+		{"refund of a paid order releases", []domain.OrderStatus{domain.OrderPaid, domain.OrderRefunded}, 10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOrderFixture(t)
+			for _, status := range tt.steps {
+				if _, err := f.orders.SetStatus(context.Background(), f.order.ID, status, Change{}); err != nil {
+					t.Fatalf("SetStatus(%s) error = %v", status, err)
+				}
+			}
+			if onHand, reserved := f.stock(t); onHand != tt.wantOnHand || reserved != tt.wantReserved {
+				t.Errorf("stock = %d on hand, %d reserved, want %d, %d", onHand, reserved, tt.wantOnHand, tt.wantReserved)
+			}
+		})
+	}
+}
+
+func TestSetStatusKeepsStatusWhenStockCannotMove(t *testing.T) {
+	ctx := context.Background()
+	f := newOrderFixture(t)
+	// Give the reservation back behind the order's back
+	if _, err := f.inventory.Release(ctx, f.product.ID, "", 2, "manual release"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.orders.SetStatus(ctx, f.order.ID, domain.OrderCancelled, Change{}); err == nil {
+		t.Fatal("SetStatus() succeeded without stock to release")
+	}
+	order, err := f.orders.order(ctx, f.order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != domain.OrderPending || len(order.History) != 1 {
+		t.Errorf("order status = %s with %d events, want %s with 1", order.Status, len(order.History), domain.OrderPending)
+	}
+}
+
+func TestSetStatusGivesBackPromotionUses(t *testing.T) {
+	tests := []struct {
+		name      string
+		steps     []domain.OrderStatus
+		wantUsage int
+	}{
+		{"cancel", []domain.OrderStatus{domain.OrderCancelled}, 0},
+		{"refund of a paid order", []domain.OrderStatus{domain.OrderPaid, domain.OrderRefunded}, 0},
+		{"refund of a fulfilled order", []domain.OrderStatus{domain.OrderPaid, domain.OrderFulfilled, domain.OrderRefunded}, 0},
+		{"fulfil keeps the use", []domain.OrderStatus{domain.OrderPaid, domain.OrderFulfilled}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newOrderFixture(t)
+			code, limit := "ONCE", 1
+			promotion := &domain.Promotion{
+				Name:         "Once per customer",
+				Type:         domain.PromotionPercentage,
+				Value:        10,
+				Code:         &code,
+				PerUserLimit: &limit,
+				Active:       true,
+			}
+			if err := f.repos.Promotions.Create(ctx, promotion); err != nil {
+				t.Fatal(err)
+			}
+			order := f.checkout(t, code)
+
+			for _, status := range tt.steps {
+				if _, err := f.orders.SetStatus(ctx, order.ID, status, Change{}); err != nil {
+					t.Fatalf("SetStatus(%s) error = %v", status, err)
+				}
+			}
+
+			stored, err := f.repos.Promotions.GetByID(ctx, promotion.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.UsageCount != tt.wantUsage {
+				t.Errorf("usage count = %d, want %d", stored.UsageCount, tt.wantUsage)
+			}
+			count, err := f.repos.Promotions.CountRedemptions(ctx, promotion.ID, f.user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != tt.wantUsage {
+				t.Errorf("redemptions = %d, want %d", count, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestCheckoutRollsBackWhenStockRunsOut(t *testing.T) {
+	ctx := context.Background()
+	f := newOrderFixture(t)
+	// Stock is reserved product by product: the tee first, then the hat,
+	// which has none
+	hat := &domain.Product{SKU: "HAT-1", Slug: "hat", Name: "Hat", Price: 15, TaxClass: domain.DefaultTaxClass, Status: domain.ProductPublished}
+	if err := f.repos.Products.Create(ctx, hat); err != nil {
+		t.Fatal(err)
+	}
+	owner := CartOwner{UserID: f.user.ID}
+	for _, item := range []domain.LineItem{{ProductID: f.product.ID, Quantity: 3}, {ProductID: hat.ID, Quantity: 1}} {
+		if _, err := f.carts.AddItem(ctx, owner, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := f.orders.Checkout(ctx, f.user, "", domain.TaxAddress{}, ""); !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("Checkout() error = %v, want %v", err, ErrInsufficientStock)
+	}
+
+	if onHand, reserved := f.stock(t); onHand != 10 || reserved != 2 {
+		t.Errorf("stock = %d on hand, %d reserved, want 10, 2", onHand, reserved)
+	}
+	orders, err := f.orders.GetUserOrders(ctx, f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 1 {
+		t.Errorf("orders = %d, want only the fixture order", len(orders))
+	}
+	cart, err := f.carts.GetCart(ctx, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cart.Items) != 2 {
+		t.Errorf("cart items = %d, want 2 kept", len(cart.Items))
+	}
+}
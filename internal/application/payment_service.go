@@ -0,0 +1,320 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/payment"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+var (
+	ErrOrderNotPayable         = conflictError("only pending orders can be paid")
+	ErrPaymentNotRefundable    = conflictError("order has no captured payment to refund")
+	ErrInvalidPaymentSignature = validationError("webhook signature is not valid")
+	ErrPaymentIntentState      = conflictError("payment cannot make this change in its current state")
+)
+
+// DefaultCurrency is the catalog currency when none is configured.
+const DefaultCurrency = "USD"
+
+// PaymentService takes payment for orders through a payment gateway and
+// keeps orders in step with the state of their payments.
+type PaymentService struct {
+	repo     repository.PaymentRepository
+	gateway  payment.PaymentGateway
+	orders   *OrderService
+	currency string
+	now      func() time.Time
+
+	// mu serializes changes to payments, so a webhook and the reconciler do
+	// not act on the same intent at once
+	mu sync.Mutex
+}
+
+func NewPaymentService(repo repository.PaymentRepository, gateway payment.PaymentGateway, orders *OrderService, currency string) *PaymentService {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	return &PaymentService{
+		repo:     repo,
+		gateway:  gateway,
+		orders:   orders,
+		currency: currency,
+		now:      time.Now,
+	}
+}
+
+// StartPayment starts paying for a pending order of user and returns the
+// payment with the client secret to complete it with the provider. An
+// order with a payment in progress gets that payment back.
+func (s *PaymentService) StartPayment(ctx context.Context, orderID uint, user *domain.User) (*domain.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, err := s.orders.GetOrder(ctx, orderID, user)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != domain.OrderPending || order.Total <= 0 {
+		return nil, ErrOrderNotPayable
+	}
+
+	payments, err := s.repo.GetByOrder(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payments {
+		if p.Status == domain.PaymentPending || p.Status == domain.PaymentAuthorized {
+			intent, err := s.gateway.GetIntent(ctx, p.IntentID)
+			if err != nil {
+				return nil, err
+			}
+			p.ClientSecret = intent.ClientSecret
+			return &p, nil
+		}
+	}
+
+	currency := order.Currency
+	if currency == "" {
+		// Orders placed before currencies were recorded
+		currency = s.currency
+	}
+	intent, err := s.gateway.CreateIntent(ctx, order.Total, currency, order.Number)
+	if err != nil {
+		return nil, err
+	}
+	p := &domain.Payment{
+		OrderID:  order.ID,
+		IntentID: intent.ID,
+		Amount:   intent.Amount,
+		Currency: intent.Currency,
+		Status:   domain.PaymentPending,
+	}
+	if err := s.repo.Create(ctx, p); err != nil {
+		return nil, err
+	}
+	// The provider may authorize the intent at once
+	if err := s.apply(ctx, p, intent, Change{}); err != nil {
+		return nil, err
+	}
+	p.ClientSecret = intent.ClientSecret
+	return p, nil
+}
+
+// GetPayments returns the payments of an order to its owner or an admin.
+func (s *PaymentService) GetPayments(ctx context.Context, orderID uint, user *domain.User) ([]domain.Payment, error) {
+	if _, err := s.orders.GetOrder(ctx, orderID, user); err != nil {
+		return nil, err
+	}
+	payments, err := s.repo.GetByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if payments == nil {
+		return []domain.Payment{}, nil
+	}
+	return payments, nil
+}
+
+// HandleWebhook verifies a webhook from the provider and brings the payment
+// it is about, and its order, up to date. Events are applied once: a
+// redelivered event is ignored. Since webhooks may arrive out of order, the
+// current state of the intent is fetched from the provider rather than
+// taken from the event.
+func (s *PaymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.gateway.ParseEvent(payload, signature)
+	if err != nil {
+		return gatewayError(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen, err := s.repo.HasEvent(ctx, event.ID)
+	if err != nil || seen {
+		return err
+	}
+
+	p, err := s.repo.GetByIntent(ctx, event.Intent.ID)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		// Not one of ours, or created by a request that failed midway
+		log.Printf("ignoring payment event %s for unknown intent %s", event.ID, event.Intent.ID)
+		return nil
+	}
+	intent, err := s.gateway.GetIntent(ctx, p.IntentID)
+	if err != nil {
+		return err
+	}
+	if err := s.apply(ctx, p, intent, Change{}); err != nil {
+		return err
+	}
+
+	return s.repo.RecordEvent(ctx, &domain.PaymentEvent{
+		ID:       event.ID,
+		Type:     event.Type,
+		IntentID: event.Intent.ID,
+	})
+}
+
+// Refund refunds the captured payment of an order, which moves the order to
+// refunded.
+func (s *PaymentService) Refund(ctx context.Context, orderID uint, change Change) (*domain.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, err := s.orders.order(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.repo.GetByOrder(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payments {
+		if p.Status != domain.PaymentCaptured {
+			continue
+		}
+		intent, err := s.gateway.Refund(ctx, p.IntentID)
+		if err != nil {
+			return nil, gatewayError(err)
+		}
+		if err := s.apply(ctx, &p, intent, change); err != nil {
+			return nil, err
+		}
+		return s.orders.order(ctx, order.ID)
+	}
+	return nil, ErrPaymentNotRefundable
+}
+
+// Reconcile brings up to date the payments left pending or authorized for
+// longer than age, for example because a webhook was lost. It returns the
+// number of payments checked.
+func (s *PaymentService) Reconcile(ctx context.Context, age time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stale, err := s.repo.GetStale(ctx, []domain.PaymentStatus{domain.PaymentPending, domain.PaymentAuthorized}, s.now().Add(-age))
+	if err != nil {
+		return 0, err
+	}
+	for i := range stale {
+		p := &stale[i]
+		intent, err := s.gateway.GetIntent(ctx, p.IntentID)
+		if err == nil {
+			err = s.apply(ctx, p, intent, Change{})
+		}
+		if err != nil {
+			log.Printf("error reconciling payment %s: %v", p.IntentID, err)
+		}
+	}
+	return len(stale), nil
+}
+
+// StartReconciler reconciles the payments stuck for longer than age every
+// interval until the returned stop function is called, which also cancels a
+// pass in progress.
+func (s *PaymentService) StartReconciler(interval, age time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			if _, err := s.Reconcile(ctx, age); err != nil {
+				log.Printf("error reconciling payments: %v", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return cancel
+}
+
+// apply records the state of intent on its payment and acts on it: an
+// authorized intent is captured, a captured one pays its order and a
+// refunded one refunds it. Payments for orders cancelled in the meantime are
+// voided or refunded. Every step checks the current state first, so applying
+// the same intent again changes nothing. s.mu must be held.
+func (s *PaymentService) apply(ctx context.Context, p *domain.Payment, intent *payment.Intent, change Change) error {
+	if intent.Status != p.Status {
+		updated, err := s.repo.UpdateStatus(ctx, p.ID, p.Status, intent.Status)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			// Another instance applied a change first; it will act on it
+			return nil
+		}
+		p.Status = intent.Status
+	}
+
+	order, err := s.orders.order(ctx, p.OrderID)
+	if err != nil {
+		return err
+	}
+	if change.Reason == "" {
+		change.Reason = fmt.Sprintf("payment %s %s", intent.ID, intent.Status)
+	}
+
+	switch intent.Status {
+	case domain.PaymentPending:
+		if order.Status == domain.OrderCancelled {
+			return s.follow(ctx, p, change, s.gateway.Void)
+		}
+	case domain.PaymentAuthorized:
+		if order.Status != domain.OrderPending {
+			return s.follow(ctx, p, change, s.gateway.Void)
+		}
+		return s.follow(ctx, p, change, s.gateway.Capture)
+	case domain.PaymentCaptured:
+		switch order.Status {
+		case domain.OrderPending:
+			_, err := s.orders.SetStatus(ctx, order.ID, domain.OrderPaid, change)
+			return err
+		case domain.OrderCancelled:
+			return s.follow(ctx, p, change, s.gateway.Refund)
+		}
+	case domain.PaymentRefunded:
+		if containsOrderStatus(orderTransitions[order.Status], domain.OrderRefunded) {
+			_, err := s.orders.SetStatus(ctx, order.ID, domain.OrderRefunded, change)
+			return err
+		}
+	}
+	return nil
+}
+
+// follow takes the next gateway step for a payment and applies its result.
+func (s *PaymentService) follow(ctx context.Context, p *domain.Payment, change Change, step func(ctx context.Context, id string) (*payment.Intent, error)) error {
+	intent, err := step(ctx, p.IntentID)
+	if err != nil {
+		return gatewayError(err)
+	}
+	change.Reason = ""
+	return s.apply(ctx, p, intent, change)
+}
+
+// gatewayError gives the gateway errors a caller can act on an application
+// error kind.
+func gatewayError(err error) error {
+	switch {
+	case errors.Is(err, payment.ErrInvalidSignature):
+		return ErrInvalidPaymentSignature
+	case errors.Is(err, payment.ErrInvalidIntentState):
+		return ErrPaymentIntentState
+	}
+	return err
+}
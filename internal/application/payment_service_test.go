@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/euro1061/gohex/internal/adapters/payment/fake"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/currency"
+)
+
+// noRates is an exchange rate provider without rates, for orders in the
+// base currency.
+type noRates struct{}
+
+func (noRates) Rate(ctx context.Context, from, to string) (float64, error) {
+	return 0, currency.ErrRateNotFound
+}
+
+func TestHandleWebhookAppliesEventOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newOrderFixture(t)
+	gateway, err := fake.NewGateway(fake.Config{Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payments := NewPaymentService(f.repos.Payments, gateway, f.orders, "")
+	order := f.order
+
+	p, err := payments.StartPayment(ctx, order.ID, f.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, signature, err := gateway.Authorize(p.IntentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := payments.HandleWebhook(ctx, payload, signature); err != nil {
+			t.Fatalf("HandleWebhook() delivery %d error = %v", i+1, err)
+		}
+	}
+
+	paid, err := f.orders.order(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paid.Status != domain.OrderPaid {
+		t.Errorf("order status = %s, want %s", paid.Status, domain.OrderPaid)
+	}
+	var paidEvents int
+	for _, event := range paid.History {
+		if event.To == domain.OrderPaid {
+			paidEvents++
+		}
+	}
+	if paidEvents != 1 {
+		t.Errorf("order moved to paid %d times, want once", paidEvents)
+	}
+
+	stored, err := f.repos.Payments.GetByOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 || stored[0].Status != domain.PaymentCaptured {
+		t.Errorf("payments = %+v, want one captured payment", stored)
+	}
+}
@@ -0,0 +1,284 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+var (
+	ErrPriceScheduleNotFound       = notFoundError("price schedule not found")
+	ErrInvalidPriceScheduleWindow  = fieldError("ends_at", "gtfield", "price schedule must end after it starts and in the future")
+	ErrPriceScheduleOverlap        = conflictError("price schedule overlaps another scheduled price for this product")
+	ErrPriceScheduleNotCancellable = conflictError("only scheduled or active price schedules can be cancelled")
+)
+
+// PriceScheduleService manages scheduled price changes. Its scheduler applies
+// them when they start and, for sale windows, restores the previous price when
+// they end. Each schedule is claimed in the transaction that applies it, so
+// every replica can run a scheduler.
+type PriceScheduleService struct {
+	repo     repository.PriceRepository
+	products *ProductService
+	now      func() time.Time
+
+	// mu serializes the overlap check with creating schedules
+	mu sync.Mutex
+}
+
+func NewPriceScheduleService(repo repository.PriceRepository, products *ProductService) *PriceScheduleService {
+	return &PriceScheduleService{
+		repo:     repo,
+		products: products,
+		now:      time.Now,
+	}
+}
+
+func (s *PriceScheduleService) GetSchedules(ctx context.Context, productID uint) ([]domain.PriceSchedule, error) {
+	if _, err := s.products.GetProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.repo.GetSchedules(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if schedules == nil {
+		return []domain.PriceSchedule{}, nil
+	}
+	return schedules, nil
+}
+
+// Schedule adds a scheduled price for a product. A schedule whose start time
+// has already passed is applied immediately.
+func (s *PriceScheduleService) Schedule(ctx context.Context, productID uint, price float64, startsAt time.Time, endsAt *time.Time, change Change) (*domain.PriceSchedule, error) {
+	if price <= 0 {
+		return nil, ErrInvalidProductPrice
+	}
+	now := s.now()
+	if startsAt.IsZero() {
+		startsAt = now
+	}
+	if endsAt != nil && (!endsAt.After(startsAt) || !endsAt.After(now)) {
+		return nil, ErrInvalidPriceScheduleWindow
+	}
+	if _, err := s.products.GetProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	schedule := &domain.PriceSchedule{
+		ProductID: productID,
+		Price:     price,
+		StartsAt:  startsAt.UTC(),
+		Reason:    strings.TrimSpace(change.Reason),
+		Actor:     change.actor(),
+		Status:    domain.PriceScheduleScheduled,
+	}
+	if endsAt != nil {
+		end := endsAt.UTC()
+		schedule.EndsAt = &end
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.repo.GetSchedules(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range existing {
+		open := existing[i].Status == domain.PriceScheduleScheduled || existing[i].Status == domain.PriceScheduleActive
+		if open && schedulesOverlap(schedule, &existing[i]) {
+			return nil, ErrPriceScheduleOverlap
+		}
+	}
+
+	err = s.transaction(ctx, func(products *ProductService, repo repository.PriceRepository) error {
+		if err := repo.CreateSchedule(ctx, schedule); err != nil {
+			return err
+		}
+		if !schedule.StartsAt.After(now) {
+			return s.apply(ctx, products, repo, schedule, now)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Cancel cancels a schedule. An active sale window ends immediately and the
+// previous price is restored.
+func (s *PriceScheduleService) Cancel(ctx context.Context, productID, scheduleID uint, change Change) (*domain.PriceSchedule, error) {
+	var schedule *domain.PriceSchedule
+	err := s.transaction(ctx, func(products *ProductService, repo repository.PriceRepository) error {
+		// Lock the schedule so a scheduler cannot apply it meanwhile
+		var err error
+		schedule, err = repo.LockSchedule(ctx, scheduleID)
+		if err != nil {
+			return err
+		}
+		if schedule == nil || schedule.ProductID != productID {
+			return ErrPriceScheduleNotFound
+		}
+		if schedule.Status != domain.PriceScheduleScheduled && schedule.Status != domain.PriceScheduleActive {
+			return ErrPriceScheduleNotCancellable
+		}
+
+		if schedule.Status == domain.PriceScheduleActive {
+			reason := fmt.Sprintf("price schedule #%d cancelled", schedule.ID)
+			if change.Reason != "" {
+				reason += ": " + strings.TrimSpace(change.Reason)
+			}
+			if err := s.restore(ctx, products, schedule, Change{Actor: change.Actor, Reason: reason}); err != nil {
+				return err
+			}
+		}
+		schedule.Status = domain.PriceScheduleCancelled
+		return repo.UpdateSchedule(ctx, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// ApplyDue starts the schedules whose start time has passed and ends the
+// sale windows whose end time has passed. A schedule another scheduler is
+// applying, or has applied since it was listed, is skipped. A failing
+// schedule is logged and retried on the next run.
+func (s *PriceScheduleService) ApplyDue(ctx context.Context) error {
+	now := s.now()
+	schedules, err := s.repo.GetDueSchedules(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for i := range schedules {
+		err := s.transaction(ctx, func(products *ProductService, repo repository.PriceRepository) error {
+			schedule, err := repo.ClaimDueSchedule(ctx, schedules[i].ID, now)
+			if err != nil || schedule == nil {
+				return err
+			}
+			return s.apply(ctx, products, repo, schedule, now)
+		})
+		if err != nil {
+			log.Printf("error applying price schedule %d: %v", schedules[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// StartScheduler runs ApplyDue every interval until the returned stop
+// function is called, which also cancels a pass in progress.
+func (s *PriceScheduleService) StartScheduler(interval time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			if err := s.ApplyDue(ctx); err != nil {
+				log.Printf("error applying price schedules: %v", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return cancel
+}
+
+// transaction runs fn with the product service and price repository bound to
+// one unit of work, so a price and the schedule that set it change together.
+func (s *PriceScheduleService) transaction(ctx context.Context, fn func(products *ProductService, repo repository.PriceRepository) error) error {
+	return s.products.transaction(ctx, func(products *ProductService) error {
+		return fn(products, products.priceRepo)
+	})
+}
+
+// apply moves a due schedule forward: a scheduled entry sets its price and
+// becomes active (or completed when it has no end), and an active entry
+// past its end restores the previous price. The caller must have claimed
+// schedule in the transaction apply runs in.
+func (s *PriceScheduleService) apply(ctx context.Context, products *ProductService, repo repository.PriceRepository, schedule *domain.PriceSchedule, now time.Time) error {
+	if schedule.Status == domain.PriceScheduleScheduled {
+		product, err := products.repo.GetByID(ctx, schedule.ProductID)
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			schedule.Status = domain.PriceScheduleCancelled
+			return repo.UpdateSchedule(ctx, schedule)
+		}
+
+		original := product.Price
+		reason := fmt.Sprintf("price schedule #%d started", schedule.ID)
+		if schedule.Reason != "" {
+			reason += ": " + schedule.Reason
+		}
+		if _, err := products.ChangePrice(ctx, product.ID, schedule.Price, Change{Actor: schedule.Actor, Reason: reason}); err != nil {
+			return err
+		}
+
+		schedule.OriginalPrice = &original
+		schedule.Status = domain.PriceScheduleActive
+		if schedule.EndsAt == nil {
+			schedule.Status = domain.PriceScheduleCompleted
+		}
+		if err := repo.UpdateSchedule(ctx, schedule); err != nil {
+			return err
+		}
+	}
+
+	if schedule.Status == domain.PriceScheduleActive && schedule.EndsAt != nil && !schedule.EndsAt.After(now) {
+		reason := fmt.Sprintf("price schedule #%d ended", schedule.ID)
+		if err := s.restore(ctx, products, schedule, Change{Actor: schedule.Actor, Reason: reason}); err != nil {
+			return err
+		}
+		schedule.Status = domain.PriceScheduleCompleted
+		return repo.UpdateSchedule(ctx, schedule)
+	}
+	return nil
+}
+
+// restore sets the price back to the one in effect before schedule started.
+// A price changed by someone else during the window is left alone.
+func (s *PriceScheduleService) restore(ctx context.Context, products *ProductService, schedule *domain.PriceSchedule, change Change) error {
+	if schedule.OriginalPrice == nil {
+		return nil
+	}
+
+	product, err := products.repo.GetByID(ctx, schedule.ProductID)
+	if err != nil {
+		return err
+	}
+	if product == nil || product.Price != schedule.Price {
+		return nil
+	}
+
+	_, err = products.ChangePrice(ctx, product.ID, *schedule.OriginalPrice, change)
+	return err
+}
+
+// schedulesOverlap reports whether two schedules affect the price at the same
+// time. A schedule without an end covers only its start instant.
+func schedulesOverlap(a, b *domain.PriceSchedule) bool {
+	end := func(schedule *domain.PriceSchedule) time.Time {
+		if schedule.EndsAt == nil {
+			return schedule.StartsAt.Add(time.Nanosecond)
+		}
+		return *schedule.EndsAt
+	}
+	return a.StartsAt.Before(end(b)) && b.StartsAt.Before(end(a))
+}
@@ -0,0 +1,408 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+	"github.com/euro1061/gohex/internal/ports/tax"
+)
+
+var (
+	ErrInvalidLineItems       = fieldError("items", "length", "between 1 and 100 line items with a quantity from 1 to 1000 are required")
+	ErrCouponNotFound         = fieldError("coupon", "exists", "coupon code is not valid")
+	ErrCouponNotActive        = validationError("coupon is not active")
+	ErrCouponExhausted        = conflictError("coupon has reached its usage limit")
+	ErrCouponUserLimitReached = conflictError("you have already used this coupon the maximum number of times")
+	ErrCouponNotApplicable    = validationError("coupon does not apply to any item")
+	ErrPromotionLimitReached  = conflictError("a promotion in the quote has reached its usage limit")
+)
+
+const (
+	maxLineItems    = 100
+	maxLineQuantity = 1000
+)
+
+// PricingService computes the effective price of products after promotions
+// and tax, in the base currency or converted to another one.
+type PricingService struct {
+	promotionRepo repository.PromotionRepository
+	productRepo   repository.ProductRepository
+	categoryRepo  repository.CategoryRepository
+	taxes         tax.TaxCalculator
+	currencies    *CurrencyService
+	now           func() time.Time
+}
+
+func NewPricingService(promotionRepo repository.PromotionRepository, productRepo repository.ProductRepository, categoryRepo repository.CategoryRepository, taxes tax.TaxCalculator, currencies *CurrencyService) *PricingService {
+	return &PricingService{
+		promotionRepo: promotionRepo,
+		productRepo:   productRepo,
+		categoryRepo:  categoryRepo,
+		taxes:         taxes,
+		currencies:    currencies,
+		now:           time.Now,
+	}
+}
+
+// withRepos returns a copy of the service that uses repos, typically ones
+// bound to a transaction.
+func (s *PricingService) withRepos(repos repository.Repositories) *PricingService {
+	clone := *s
+	clone.promotionRepo = repos.Promotions
+	clone.productRepo = repos.Products
+	clone.categoryRepo = repos.Categories
+	return &clone
+}
+
+// PriceProduct prices quantity units of a product, or of one of its variants.
+func (s *PricingService) PriceProduct(ctx context.Context, productID uint, variantID *uint, quantity int, coupon string, userID uint, address domain.TaxAddress, currency string) (*domain.PriceQuote, error) {
+	return s.PriceItems(ctx, []domain.LineItem{{ProductID: productID, VariantID: variantID, Quantity: quantity}}, coupon, userID, address, currency)
+}
+
+// PriceItems prices line items of live products, applying the automatic
+// promotions in effect and the promotion of coupon when one is given, then
+// taxes the discounted lines for address. Prices are in currency, or in the
+// base currency when it is empty; fixed promotion amounts are converted too.
+// userID, when not zero, is checked against the per user limit of the coupon.
+func (s *PricingService) PriceItems(ctx context.Context, items []domain.LineItem, coupon string, userID uint, address domain.TaxAddress, currency string) (*domain.PriceQuote, error) {
+	if len(items) == 0 || len(items) > maxLineItems {
+		return nil, ErrInvalidLineItems
+	}
+	for _, item := range items {
+		if item.Quantity < 1 || item.Quantity > maxLineQuantity {
+			return nil, ErrInvalidLineItems
+		}
+	}
+	now := s.now()
+	exchange, err := s.currencies.Exchange(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &domain.PriceQuote{
+		Lines:        make([]domain.PricedLine, len(items)),
+		Currency:     exchange.Currency,
+		BaseCurrency: exchange.Base,
+		ExchangeRate: exchange.Rate,
+		Applied:      []domain.AppliedPromotion{},
+	}
+	productIDs := make([]uint, len(items))
+	for i, item := range items {
+		productIDs[i] = item.ProductID
+	}
+	products, err := s.productRepo.GetByIDs(ctx, uniqueIDs(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*domain.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+	for i, item := range items {
+		line, err := newPricedLine(byID[item.ProductID], item, now, exchange)
+		if err != nil {
+			return nil, err
+		}
+		quote.Lines[i] = line
+	}
+
+	promotions, err := s.promotions(ctx, coupon, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if coupon != "" {
+		quote.Coupon = normalizeCouponCode(coupon)
+	}
+
+	targets, err := s.newTargetMatcher(ctx, productIDs, promotions)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uint]*domain.AppliedPromotion)
+	for i := range quote.Lines {
+		line := &quote.Lines[i]
+		remaining := line.Subtotal
+		for j := range promotions {
+			promotion := &promotions[j]
+			if !targets.matches(promotion, line.ProductID) {
+				continue
+			}
+			amount := domain.RoundMoney(min(discount(promotion, line, remaining, exchange), remaining))
+			if amount <= 0 {
+				continue
+			}
+			remaining = domain.RoundMoney(remaining - amount)
+
+			applied := domain.AppliedPromotion{
+				PromotionID: promotion.ID,
+				Name:        promotion.Name,
+				Type:        promotion.Type,
+				Amount:      amount,
+			}
+			if promotion.Code != nil {
+				applied.Code = *promotion.Code
+			}
+			line.Applied = append(line.Applied, applied)
+
+			if total, ok := totals[promotion.ID]; ok {
+				total.Amount = domain.RoundMoney(total.Amount + amount)
+			} else {
+				totals[promotion.ID] = &applied
+				quote.Applied = append(quote.Applied, applied)
+			}
+		}
+		line.Discount = domain.RoundMoney(line.Subtotal - remaining)
+		line.Total = remaining
+
+		quote.Subtotal = domain.RoundMoney(quote.Subtotal + line.Subtotal)
+		quote.Discount = domain.RoundMoney(quote.Discount + line.Discount)
+	}
+	for i := range quote.Applied {
+		quote.Applied[i].Amount = totals[quote.Applied[i].PromotionID].Amount
+	}
+	quote.Total = domain.RoundMoney(quote.Subtotal - quote.Discount)
+
+	if quote.Coupon != "" && !couponApplied(quote) {
+		return nil, ErrCouponNotApplicable
+	}
+	if err := s.applyTax(ctx, quote, address); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// applyTax taxes the discounted lines of quote. Tax is added to the quote
+// total unless prices already include it.
+func (s *PricingService) applyTax(ctx context.Context, quote *domain.PriceQuote, address domain.TaxAddress) error {
+	lines := make([]domain.TaxableLine, len(quote.Lines))
+	for i, line := range quote.Lines {
+		lines[i] = domain.TaxableLine{TaxClass: line.TaxClass, Amount: line.Total}
+	}
+	taxQuote, err := s.taxes.Calculate(ctx, address, lines)
+	if err != nil {
+		return err
+	}
+
+	for i := range quote.Lines {
+		quote.Lines[i].TaxRate = taxQuote.Lines[i].Rate
+		quote.Lines[i].Tax = taxQuote.Lines[i].Tax
+	}
+	quote.Tax = taxQuote.Tax
+	quote.TaxAddress = taxQuote.Address
+	quote.PricesIncludeTax = taxQuote.Inclusive
+	if !quote.PricesIncludeTax {
+		quote.Total = domain.RoundMoney(quote.Total + quote.Tax)
+	}
+	return nil
+}
+
+// Redeem records the use of every promotion that applied to quote, for
+// promotions with usage limits to count. reference identifies what the quote
+// was used for, such as an order number. Promotions redeemed before one
+// that reached its limit stay redeemed.
+func (s *PricingService) Redeem(ctx context.Context, quote *domain.PriceQuote, userID uint, reference string) error {
+	for _, applied := range quote.Applied {
+		redeemed, err := s.promotionRepo.Redeem(ctx, &domain.PromotionRedemption{
+			PromotionID: applied.PromotionID,
+			UserID:      userID,
+			Reference:   reference,
+			CreatedAt:   s.now(),
+		})
+		if err != nil {
+			return err
+		}
+		if !redeemed {
+			return ErrPromotionLimitReached
+		}
+	}
+	return nil
+}
+
+// Unredeem gives back the promotion uses redeemed with reference, such as
+// those of an order that was cancelled.
+func (s *PricingService) Unredeem(ctx context.Context, reference string) error {
+	return s.promotionRepo.Unredeem(ctx, reference)
+}
+
+// promotions returns the promotions to apply, highest priority first: the
+// automatic ones in effect and, when coupon is given, its promotion.
+func (s *PricingService) promotions(ctx context.Context, coupon string, userID uint, now time.Time) ([]domain.Promotion, error) {
+	automatic, err := s.promotionRepo.GetAutomatic(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	promotions := make([]domain.Promotion, 0, len(automatic)+1)
+	for _, promotion := range automatic {
+		if !promotion.Exhausted() {
+			promotions = append(promotions, promotion)
+		}
+	}
+
+	if coupon != "" {
+		promotion, err := s.promotionRepo.GetByCode(ctx, normalizeCouponCode(coupon))
+		if err != nil {
+			return nil, err
+		}
+		if promotion == nil {
+			return nil, ErrCouponNotFound
+		}
+		if !promotion.InEffect(now) {
+			return nil, ErrCouponNotActive
+		}
+		if promotion.Exhausted() {
+			return nil, ErrCouponExhausted
+		}
+		if promotion.PerUserLimit != nil && userID != 0 {
+			count, err := s.promotionRepo.CountRedemptions(ctx, promotion.ID, userID)
+			if err != nil {
+				return nil, err
+			}
+			if count >= *promotion.PerUserLimit {
+				return nil, ErrCouponUserLimitReached
+			}
+		}
+		promotions = append(promotions, *promotion)
+	}
+
+	sort.SliceStable(promotions, func(i, j int) bool {
+		if promotions[i].Priority != promotions[j].Priority {
+			return promotions[i].Priority > promotions[j].Priority
+		}
+		return promotions[i].ID < promotions[j].ID
+	})
+	return promotions, nil
+}
+
+// newPricedLine prices item before discounts, in the currency of exchange.
+// Products that are not live cannot be priced.
+func newPricedLine(product *domain.Product, item domain.LineItem, now time.Time, exchange *Exchange) (domain.PricedLine, error) {
+	if product == nil || !product.IsLive(now) {
+		return domain.PricedLine{}, ErrProductNotFound
+	}
+
+	line := domain.PricedLine{
+		LineItem:  item,
+		SKU:       product.SKU,
+		Name:      product.Name,
+		TaxClass:  product.TaxClass,
+		UnitPrice: exchange.ProductPrice(product),
+		Applied:   []domain.AppliedPromotion{},
+
+		BaseUnitPrice: product.Price,
+	}
+	if item.VariantID != nil {
+		variant := findVariant(product.Variants, *item.VariantID)
+		if variant == nil {
+			return domain.PricedLine{}, ErrVariantNotFound
+		}
+		line.SKU = variant.SKU
+		line.UnitPrice = exchange.Convert(variant.Price)
+		line.BaseUnitPrice = variant.Price
+	}
+	if line.TaxClass == "" {
+		line.TaxClass = domain.DefaultTaxClass
+	}
+	line.Subtotal = domain.RoundMoney(line.UnitPrice * float64(item.Quantity))
+	return line, nil
+}
+
+func findVariant(variants []domain.ProductVariant, id uint) *domain.ProductVariant {
+	for i := range variants {
+		if variants[i].ID == id {
+			return &variants[i]
+		}
+	}
+	return nil
+}
+
+// discount computes what promotion takes off line, whose undiscounted part
+// is remaining. Fixed amounts are in the base currency and converted with
+// exchange.
+func discount(promotion *domain.Promotion, line *domain.PricedLine, remaining float64, exchange *Exchange) float64 {
+	switch promotion.Type {
+	case domain.PromotionPercentage:
+		return remaining * promotion.Value / 100
+	case domain.PromotionFixed:
+		return exchange.Convert(promotion.Value) * float64(line.Quantity)
+	case domain.PromotionBuyXGetY:
+		discounted := line.Quantity / (promotion.BuyQuantity + promotion.GetQuantity) * promotion.GetQuantity
+		return float64(discounted) * line.UnitPrice * promotion.Value / 100
+	}
+	return 0
+}
+
+func couponApplied(quote *domain.PriceQuote) bool {
+	for _, applied := range quote.Applied {
+		if applied.Code == quote.Coupon {
+			return true
+		}
+	}
+	return false
+}
+
+// targetMatcher decides which products promotions target, resolving their
+// category targets once per quote.
+type targetMatcher struct {
+	productCategories map[uint][]uint
+	// categories holds the targeted categories and those below them, per promotion
+	categories map[uint]map[uint]struct{}
+}
+
+func (s *PricingService) newTargetMatcher(ctx context.Context, productIDs []uint, promotions []domain.Promotion) (*targetMatcher, error) {
+	matcher := &targetMatcher{categories: make(map[uint]map[uint]struct{})}
+
+	descendants := make(map[uint][]uint)
+	for _, promotion := range promotions {
+		if len(promotion.CategoryIDs) == 0 {
+			continue
+		}
+		categories := make(map[uint]struct{})
+		for _, id := range promotion.CategoryIDs {
+			ids, ok := descendants[id]
+			if !ok {
+				var err error
+				if ids, err = s.categoryRepo.GetDescendantIDs(ctx, id); err != nil {
+					return nil, err
+				}
+				descendants[id] = ids
+			}
+			for _, descendant := range ids {
+				categories[descendant] = struct{}{}
+			}
+		}
+		matcher.categories[promotion.ID] = categories
+	}
+
+	if len(matcher.categories) > 0 {
+		productCategories, err := s.categoryRepo.GetCategoryIDsByProducts(ctx, uniqueIDs(productIDs))
+		if err != nil {
+			return nil, err
+		}
+		matcher.productCategories = productCategories
+	}
+	return matcher, nil
+}
+
+// matches reports whether promotion targets productID. A promotion without
+// targets applies to every product.
+func (m *targetMatcher) matches(promotion *domain.Promotion, productID uint) bool {
+	if len(promotion.ProductIDs) == 0 && len(promotion.CategoryIDs) == 0 {
+		return true
+	}
+	for _, id := range promotion.ProductIDs {
+		if id == productID {
+			return true
+		}
+	}
+	categories := m.categories[promotion.ID]
+	for _, id := range m.productCategories[productID] {
+		if _, ok := categories[id]; ok {
+			return true
+		}
+	}
+	return false
+}
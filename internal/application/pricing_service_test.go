@@ -0,0 +1,326 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+// pricingFixture prices a 10.00 tee and a 3.33 sticker without tax.
+type pricingFixture struct {
+	repos   repository.Repositories
+	pricing *PricingService
+	tee     *domain.Product
+	sticker *domain.Product
+}
+
+func newPricingFixture(t *testing.T, promotions ...*domain.Promotion) *pricingFixture {
+	t.Helper()
+	ctx := context.Background()
+	f := &pricingFixture{repos: memoryRepositories()}
+	f.pricing = NewPricingService(f.repos.Promotions, f.repos.Products, f.repos.Categories,
+		NewTaxService(f.repos.TaxRates, TaxSettings{}), NewCurrencyService(noRates{}, CurrencySettings{}))
+
+	f.tee = &domain.Product{SKU: "TEE-1", Slug: "tee", Name: "Tee", Price: 10, Status: domain.ProductPublished}
+	f.sticker = &domain.Product{SKU: "STICKER-1", Slug: "sticker", Name: "Sticker", Price: 3.33, Status: domain.ProductPublished}
+	for _, product := range []*domain.Product{f.tee, f.sticker} {
+		if err := f.repos.Products.Create(ctx, product); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, promotion := range promotions {
+		promotion.Active = true
+		if err := f.repos.Promotions.Create(ctx, promotion); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return f
+}
+
+func coupon(code string) *string { return &code }
+
+func limit(n int) *int { return &n }
+
+func TestPriceItemsDiscounts(t *testing.T) {
+	tests := []struct {
+		name         string
+		promotions   []*domain.Promotion
+		tees         int
+		stickers     int
+		coupon       string
+		wantDiscount float64
+		wantTotal    float64
+	}{
+		{
+			name:         "no promotion",
+			tees:         2,
+			wantDiscount: 0,
+			wantTotal:    20,
+		},
+		{
+			name:         "percentage",
+			promotions:   []*domain.Promotion{{Name: "10% off", Type: domain.PromotionPercentage, Value: 10}},
+			tees:         2,
+			wantDiscount: 2,
+			wantTotal:    18,
+		},
+		{
+			name:         "fixed per unit",
+			promotions:   []*domain.Promotion{{Name: "3 off", Type: domain.PromotionFixed, Value: 3}},
+			tees:         2,
+			wantDiscount: 6,
+			wantTotal:    14,
+		},
+		{
+			name:         "fixed larger than the line",
+			promotions:   []*domain.Promotion{{Name: "15 off", Type: domain.PromotionFixed, Value: 15}},
+			tees:         1,
+			wantDiscount: 10,
+			wantTotal:    0,
+		},
+		{
+			name:         "percentage above 100",
+			promotions:   []*domain.Promotion{{Name: "150% off", Type: domain.PromotionPercentage, Value: 150}},
+			tees:         1,
+			wantDiscount: 10,
+			wantTotal:    0,
+		},
+		{
+			name:         "buy 2 get 1 free",
+			promotions:   []*domain.Promotion{{Name: "3 for 2", Type: domain.PromotionBuyXGetY, Value: 100, BuyQuantity: 2, GetQuantity: 1}},
+			tees:         3,
+			wantDiscount: 10,
+			wantTotal:    20,
+		},
+		{
+			name:         "buy 2 get 1 free with a remainder",
+			promotions:   []*domain.Promotion{{Name: "3 for 2", Type: domain.PromotionBuyXGetY, Value: 100, BuyQuantity: 2, GetQuantity: 1}},
+			tees:         5,
+			wantDiscount: 10,
+			wantTotal:    40,
+		},
+		{
+			name:         "buy 2 get 1 free below the threshold",
+			promotions:   []*domain.Promotion{{Name: "3 for 2", Type: domain.PromotionBuyXGetY, Value: 100, BuyQuantity: 2, GetQuantity: 1}},
+			tees:         2,
+			wantDiscount: 0,
+			wantTotal:    20,
+		},
+		{
+			name:         "buy 1 get 1 half price",
+			promotions:   []*domain.Promotion{{Name: "Second half price", Type: domain.PromotionBuyXGetY, Value: 50, BuyQuantity: 1, GetQuantity: 1}},
+			tees:         4,
+			wantDiscount: 10,
+			wantTotal:    30,
+		},
+		{
+			name: "stacking in priority order, percentage first",
+			promotions: []*domain.Promotion{
+				{Name: "1 off", Type: domain.PromotionFixed, Value: 1},
+				{Name: "10% off", Type: domain.PromotionPercentage, Value: 10, Priority: 10},
+			},
+			tees:         2,
+			wantDiscount: 4,
+			wantTotal:    16,
+		},
+		{
+			name: "stacking in priority order, fixed first",
+			promotions: []*domain.Promotion{
+				{Name: "1 off", Type: domain.PromotionFixed, Value: 1, Priority: 10},
+				{Name: "10% off", Type: domain.PromotionPercentage, Value: 10},
+			},
+			tees:         2,
+			wantDiscount: 3.8,
+			wantTotal:    16.2,
+		},
+		{
+			name: "stacking never goes below zero",
+			promotions: []*domain.Promotion{
+				{Name: "8 off", Type: domain.PromotionFixed, Value: 8, Priority: 10},
+				{Name: "5 off", Type: domain.PromotionFixed, Value: 5},
+			},
+			tees:         1,
+			wantDiscount: 10,
+			wantTotal:    0,
+		},
+		{
+			name:         "rounding a line",
+			promotions:   []*domain.Promotion{{Name: "15% off", Type: domain.PromotionPercentage, Value: 15}},
+			stickers:     1,
+			wantDiscount: 0.5,
+			wantTotal:    2.83,
+		},
+		{
+			name:         "rounding every line",
+			promotions:   []*domain.Promotion{{Name: "33% off", Type: domain.PromotionPercentage, Value: 33}},
+			tees:         1,
+			stickers:     3,
+			wantDiscount: 6.6,
+			wantTotal:    13.39,
+		},
+		{
+			name:         "coupon",
+			promotions:   []*domain.Promotion{{Name: "Welcome", Type: domain.PromotionPercentage, Value: 20, Code: coupon("WELCOME")}},
+			tees:         1,
+			coupon:       "welcome",
+			wantDiscount: 2,
+			wantTotal:    8,
+		},
+		{
+			name:         "coupon not given",
+			promotions:   []*domain.Promotion{{Name: "Welcome", Type: domain.PromotionPercentage, Value: 20, Code: coupon("WELCOME")}},
+			tees:         1,
+			wantDiscount: 0,
+			wantTotal:    10,
+		},
+		{
+			name:         "exhausted automatic promotion",
+			promotions:   []*domain.Promotion{{Name: "First ten", Type: domain.PromotionPercentage, Value: 10, UsageLimit: limit(10), UsageCount: 10}},
+			tees:         1,
+			wantDiscount: 0,
+			wantTotal:    10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPricingFixture(t, tt.promotions...)
+			var items []domain.LineItem
+			if tt.tees > 0 {
+				items = append(items, domain.LineItem{ProductID: f.tee.ID, Quantity: tt.tees})
+			}
+			if tt.stickers > 0 {
+				items = append(items, domain.LineItem{ProductID: f.sticker.ID, Quantity: tt.stickers})
+			}
+
+			quote, err := f.pricing.PriceItems(context.Background(), items, tt.coupon, 0, domain.TaxAddress{}, "")
+			if err != nil {
+				t.Fatalf("PriceItems() error = %v", err)
+			}
+			if quote.Discount != tt.wantDiscount || quote.Total != tt.wantTotal {
+				t.Errorf("PriceItems() discount = %v, total = %v, want %v, %v", quote.Discount, quote.Total, tt.wantDiscount, tt.wantTotal)
+			}
+			var applied float64
+			for _, promotion := range quote.Applied {
+				applied = domain.RoundMoney(applied + promotion.Amount)
+			}
+			if applied != quote.Discount {
+				t.Errorf("applied promotions add up to %v, want the discount %v", applied, quote.Discount)
+			}
+		})
+	}
+}
+
+func TestPriceItemsTargets(t *testing.T) {
+	f := newPricingFixture(t)
+	ctx := context.Background()
+	promotion := &domain.Promotion{Name: "Tees", Type: domain.PromotionPercentage, Value: 50, Active: true, ProductIDs: []uint{f.tee.ID}}
+	if err := f.repos.Promotions.Create(ctx, promotion); err != nil {
+		t.Fatal(err)
+	}
+
+	quote, err := f.pricing.PriceItems(ctx, []domain.LineItem{
+		{ProductID: f.tee.ID, Quantity: 1},
+		{ProductID: f.sticker.ID, Quantity: 1},
+	}, "", 0, domain.TaxAddress{}, "")
+	if err != nil {
+		t.Fatalf("PriceItems() error = %v", err)
+	}
+	if got := quote.Lines[0].Discount; got != 5 {
+		t.Errorf("tee discount = %v, want 5", got)
+	}
+	if got := quote.Lines[1].Discount; got != 0 {
+		t.Errorf("sticker discount = %v, want 0", got)
+	}
+}
+
+func TestPriceItemsCouponErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		promotion *domain.Promotion
+		coupon    string
+		wantErr   error
+	}{
+		{"unknown", nil, "NOPE", ErrCouponNotFound},
+		{
+			"global limit reached",
+			&domain.Promotion{Name: "Launch", Type: domain.PromotionPercentage, Value: 10, Code: coupon("LAUNCH"), UsageLimit: limit(5), UsageCount: 5},
+			"LAUNCH",
+			ErrCouponExhausted,
+		},
+		{
+			"not applicable",
+			&domain.Promotion{Name: "Stickers", Type: domain.PromotionPercentage, Value: 10, Code: coupon("STICKERS"), ProductIDs: []uint{99}},
+			"STICKERS",
+			ErrCouponNotApplicable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var promotions []*domain.Promotion
+			if tt.promotion != nil {
+				promotions = append(promotions, tt.promotion)
+			}
+			f := newPricingFixture(t, promotions...)
+
+			_, err := f.pricing.PriceProduct(context.Background(), f.tee.ID, nil, 1, tt.coupon, 0, domain.TaxAddress{}, "")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PriceProduct() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedeemLimits(t *testing.T) {
+	ctx := context.Background()
+	promotion := &domain.Promotion{
+		Name:         "Twice, once each",
+		Type:         domain.PromotionFixed,
+		Value:        1,
+		Code:         coupon("TWICE"),
+		UsageLimit:   limit(2),
+		PerUserLimit: limit(1),
+	}
+	f := newPricingFixture(t, promotion)
+	redeem := func(userID uint, reference string) error {
+		quote, err := f.pricing.PriceProduct(ctx, f.tee.ID, nil, 1, "TWICE", userID, domain.TaxAddress{}, "")
+		if err != nil {
+			return err
+		}
+		return f.pricing.Redeem(ctx, quote, userID, reference)
+	}
+
+	if err := redeem(1, "ORDER-1"); err != nil {
+		t.Fatalf("first redemption error = %v", err)
+	}
+	if err := redeem(1, "ORDER-2"); !errors.Is(err, ErrCouponUserLimitReached) {
+		t.Errorf("second redemption by the same user error = %v, want %v", err, ErrCouponUserLimitReached)
+	}
+	if _, err := f.pricing.PriceProduct(ctx, f.tee.ID, nil, 1, "TWICE", 0, domain.TaxAddress{}, ""); err != nil {
+		t.Errorf("quote without a user error = %v, want none", err)
+	}
+
+	// A quote taken before the last use cannot be redeemed after it
+	late, err := f.pricing.PriceProduct(ctx, f.tee.ID, nil, 1, "TWICE", 3, domain.TaxAddress{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := redeem(2, "ORDER-3"); err != nil {
+		t.Fatalf("redemption by another user error = %v", err)
+	}
+	if err := f.pricing.Redeem(ctx, late, 3, "ORDER-4"); !errors.Is(err, ErrPromotionLimitReached) {
+		t.Errorf("redemption past the global limit error = %v, want %v", err, ErrPromotionLimitReached)
+	}
+	if err := redeem(3, "ORDER-5"); !errors.Is(err, ErrCouponExhausted) {
+		t.Errorf("quote past the global limit error = %v, want %v", err, ErrCouponExhausted)
+	}
+
+	if err := f.pricing.Unredeem(ctx, "ORDER-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := redeem(1, "ORDER-6"); err != nil {
+		t.Errorf("redemption after giving the use back error = %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+package application
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+var ErrInvalidExportFormat = fieldError("format", "oneof", "export format must be csv, ndjson or xlsx")
+
+// exportBatchSize is the number of products read from the repository at a time.
+const exportBatchSize = 500
+
+// exportColumns are the CSV and XLSX columns. Apart from id they match the
+// columns accepted by the CSV import, so an export can be imported again.
+var exportColumns = []string{"id", "sku", "name", "description", "price", "barcode", "slug", "tags", "tax_class"}
+
+// ProductExport writes the products matching a query in one format. It is
+// created by ProductService.Export, which validates the request up front so
+// errors can be reported before any output is written.
+type ProductExport struct {
+	service *ProductService
+	format  domain.ExportFormat
+	filter  domain.ProductFilter
+}
+
+// Export prepares an export of the products matching query.
+func (s *ProductService) Export(ctx context.Context, format domain.ExportFormat, query ProductQuery) (*ProductExport, error) {
+	switch format {
+	case domain.ExportFormatCSV, domain.ExportFormatNDJSON, domain.ExportFormatXLSX:
+	default:
+		return nil, ErrInvalidExportFormat
+	}
+
+	filter, err := s.buildFilter(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &ProductExport{service: s, format: format, filter: filter}, nil
+}
+
+func (e *ProductExport) ContentType() string {
+	switch e.format {
+	case domain.ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case domain.ExportFormatNDJSON:
+		return "application/x-ndjson"
+	}
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (e *ProductExport) Extension() string {
+	return string(e.format)
+}
+
+// productRowWriter encodes products in one export format.
+type productRowWriter interface {
+	write(product *domain.Product) error
+	// flush passes buffered rows on to the underlying writer
+	flush() error
+	close() error
+}
+
+// Write streams the export to w, reading products in batches with an ID
+// cursor so memory use stays flat however large the catalog is. When w can
+// be flushed it is flushed after every batch.
+func (e *ProductExport) Write(ctx context.Context, w io.Writer) error {
+	rows, err := newProductRowWriter(e.format, w)
+	if err != nil {
+		return err
+	}
+	flusher, _ := w.(interface{ Flush() error })
+
+	var afterID uint
+	for {
+		products, err := e.service.repo.FindAfter(ctx, e.filter, afterID, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(products) == 0 {
+			break
+		}
+		if err := e.service.attachDetails(ctx, products); err != nil {
+			return err
+		}
+
+		for i := range products {
+			if err := rows.write(&products[i]); err != nil {
+				return err
+			}
+		}
+		afterID = products[len(products)-1].ID
+
+		if err := rows.flush(); err != nil {
+			return err
+		}
+		if flusher != nil {
+			if err := flusher.Flush(); err != nil {
+				return err
+			}
+		}
+		if len(products) < exportBatchSize {
+			break
+		}
+	}
+	return rows.close()
+}
+
+func newProductRowWriter(format domain.ExportFormat, w io.Writer) (productRowWriter, error) {
+	switch format {
+	case domain.ExportFormatCSV:
+		rows := &csvProductWriter{writer: csv.NewWriter(w)}
+		return rows, rows.writer.Write(exportColumns)
+	case domain.ExportFormatNDJSON:
+		return &ndjsonProductWriter{encoder: json.NewEncoder(w)}, nil
+	case domain.ExportFormatXLSX:
+		sheet, err := newXLSXWriter(w)
+		if err != nil {
+			return nil, err
+		}
+		header := make([]interface{}, len(exportColumns))
+		for i, column := range exportColumns {
+			header[i] = column
+		}
+		return &xlsxProductWriter{sheet: sheet}, sheet.writeRow(header...)
+	}
+	return nil, ErrInvalidExportFormat
+}
+
+// exportBarcode returns the barcode of product or an empty string.
+func exportBarcode(product *domain.Product) string {
+	if product.Barcode == nil {
+		return ""
+	}
+	return *product.Barcode
+}
+
+type csvProductWriter struct {
+	writer *csv.Writer
+}
+
+func (c *csvProductWriter) write(product *domain.Product) error {
+	return c.writer.Write([]string{
+		strconv.FormatUint(uint64(product.ID), 10),
+		product.SKU,
+		product.Name,
+		product.Description,
+		strconv.FormatFloat(product.Price, 'f', -1, 64),
+		exportBarcode(product),
+		product.Slug,
+		strings.Join(product.Tags, csvTagSeparator),
+		product.TaxClass,
+	})
+}
+
+func (c *csvProductWriter) flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvProductWriter) close() error {
+	return c.flush()
+}
+
+// ndjsonProductWriter writes each product as one line of JSON, including its
+// options, variants and images.
+type ndjsonProductWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonProductWriter) write(product *domain.Product) error {
+	return n.encoder.Encode(product)
+}
+
+func (n *ndjsonProductWriter) flush() error {
+	return nil
+}
+
+func (n *ndjsonProductWriter) close() error {
+	return nil
+}
+
+type xlsxProductWriter struct {
+	sheet *xlsxWriter
+}
+
+func (x *xlsxProductWriter) write(product *domain.Product) error {
+	return x.sheet.writeRow(
+		float64(product.ID),
+		product.SKU,
+		product.Name,
+		product.Description,
+		product.Price,
+		exportBarcode(product),
+		product.Slug,
+		strings.Join(product.Tags, csvTagSeparator),
+		product.TaxClass,
+	)
+}
+
+// flush is a no-op because the compressor decides when sheet data is written.
+func (x *xlsxProductWriter) flush() error {
+	return nil
+}
+
+func (x *xlsxProductWriter) close() error {
+	return x.sheet.close()
+}
@@ -0,0 +1,97 @@
+package application
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var skuPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9._-]{1,63}$`)
+
+const maxSlugLength = 100
+
+// normalizeSKU trims and uppercases a SKU and checks its format.
+func normalizeSKU(sku string) (string, error) {
+	sku = strings.ToUpper(strings.TrimSpace(sku))
+	if !skuPattern.MatchString(sku) {
+		return "", ErrInvalidProductSKU
+	}
+	return sku, nil
+}
+
+// normalizeBarcode strips spaces from a barcode and validates it as a
+// GTIN-8, GTIN-12 (UPC-A), GTIN-13 (EAN-13) or GTIN-14 with a valid check digit.
+// An empty barcode is returned as nil.
+func normalizeBarcode(barcode *string) (*string, error) {
+	if barcode == nil {
+		return nil, nil
+	}
+
+	code := strings.ReplaceAll(strings.TrimSpace(*barcode), " ", "")
+	if code == "" {
+		return nil, nil
+	}
+	if !validGTIN(code) {
+		return nil, ErrInvalidProductBarcode
+	}
+	return &code, nil
+}
+
+func validGTIN(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	// Weights alternate 3 and 1 starting from the digit next to the check digit
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		digit := int(c - '0')
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	check := code[len(code)-1]
+	if check < '0' || check > '9' {
+		return false
+	}
+	return (10-sum%10)%10 == int(check-'0')
+}
+
+// slugify builds a URL slug from text. Letters, digits and combining marks of
+// any script are kept, so Thai names such as "เสื้อยืด สีขาว" become
+// "เสื้อยืด-สีขาว"; everything else collapses into single hyphens.
+func slugify(text string) string {
+	text = strings.ToLower(norm.NFKC.String(text))
+
+	var b strings.Builder
+	hyphen := false
+	length := 0
+	for _, r := range text {
+		if length >= maxSlugLength {
+			break
+		}
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+				length++
+			}
+			hyphen = false
+			b.WriteRune(r)
+			length++
+		default:
+			hyphen = true
+		}
+	}
+	return b.String()
+}
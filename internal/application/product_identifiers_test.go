@@ -0,0 +1,134 @@
+package application
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestValidGTIN(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want bool
+	}{
+		{"GTIN-8", "96385074", true},
+		{"GTIN-12", "036000291452", true},
+		{"GTIN-13", "4006381333931", true},
+		{"GTIN-13 Thai prefix", "8850999220017", true},
+		{"GTIN-14", "10614141000415", true},
+		{"GTIN-8 bad check digit", "96385075", false},
+		{"GTIN-12 bad check digit", "036000291453", false},
+		{"GTIN-13 bad check digit", "4006381333932", false},
+		{"GTIN-14 bad check digit", "10614141000416", false},
+		{"too short", "1234567", false},
+		{"unsupported length", "12345678901", false},
+		{"too long", "106141410004150", false},
+		{"letters", "4006381A33931", false},
+		{"letter check digit", "400638133393X", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validGTIN(tt.code); got != tt.want {
+				t.Errorf("validGTIN(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func barcode(code string) *string { return &code }
+
+func TestNormalizeBarcode(t *testing.T) {
+	tests := []struct {
+		name    string
+		barcode *string
+		want    string
+		wantNil bool
+		wantErr error
+	}{
+		{"nil", nil, "", true, nil},
+		{"blank", barcode("   "), "", true, nil},
+		{"spaces stripped", barcode(" 4006381 333931 "), "4006381333931", false, nil},
+		{"invalid", barcode("4006381333932"), "", true, ErrInvalidProductBarcode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeBarcode(tt.barcode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalizeBarcode() error = %v, want %v", err, tt.wantErr)
+			}
+			if (got == nil) != tt.wantNil || (got != nil && *got != tt.want) {
+				t.Errorf("normalizeBarcode() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeSKU(t *testing.T) {
+	tests := []struct {
+		name    string
+		sku     string
+		want    string
+		wantErr bool
+	}{
+		{"uppercased and trimmed", "  tee-01 ", "TEE-01", false},
+		{"dots and underscores", "A.B_C-1", "A.B_C-1", false},
+		{"shortest", "A1", "A1", false},
+		{"longest", strings.Repeat("A", 64), strings.Repeat("A", 64), false},
+		{"empty is required", "", "", true},
+		{"blank is required", "   ", "", true},
+		{"single character", "A", "", true},
+		{"too long", strings.Repeat("A", 65), "", true},
+		{"leading separator", "-TEE", "", true},
+		{"inner space", "TEE 01", "", true},
+		{"slash", "TEE/01", "", true},
+		{"non ASCII", "เสื้อ-1", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeSKU(tt.sku)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidProductSKU) {
+					t.Errorf("normalizeSKU(%q) error = %v, want %v", tt.sku, err, ErrInvalidProductSKU)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("normalizeSKU(%q) = %q, %v, want %q", tt.sku, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"ASCII", "Classic Tee", "classic-tee"},
+		{"punctuation collapses", "  Tee -- (White) & Black!  ", "tee-white-black"},
+		{"digits", "Size 42", "size-42"},
+		{"Thai keeps marks", "เสื้อยืด สีขาว", "เสื้อยืด-สีขาว"},
+		{"Thai and ASCII", "เสื้อยืด Cotton 100%", "เสื้อยืด-cotton-100"},
+		{"full width normalized", "ＴＥＥ", "tee"},
+		{"empty", "", ""},
+		{"only punctuation", "!!! ---", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slugify(tt.text); got != tt.want {
+				t.Errorf("slugify(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugifyLimitsLength(t *testing.T) {
+	got := slugify(strings.Repeat("ก", maxSlugLength+20))
+	if n := utf8.RuneCountInString(got); n != maxSlugLength {
+		t.Errorf("slugify() length = %d runes, want %d", n, maxSlugLength)
+	}
+}
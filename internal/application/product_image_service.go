@@ -0,0 +1,278 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+	"github.com/euro1061/gohex/internal/ports/storage"
+)
+
+var (
+	ErrImageTooLarge        = tooLargeError("image exceeds the maximum upload size")
+	ErrUnsupportedImageType = unsupportedError("image must be a JPEG, PNG, GIF or WebP file")
+	ErrInvalidImage         = validationError("image could not be decoded")
+	ErrImageDimensions      = validationError("image dimensions are too large")
+	ErrImageNotFound        = notFoundError("product image not found")
+	ErrInvalidImageOrder    = fieldError("image_ids", "permutation", "image order must list every image of the product exactly once")
+	ErrTooManyImages        = conflictError("a product can have at most 20 images")
+)
+
+const (
+	// DefaultMaxImageSize is the upload limit used when none is configured.
+	DefaultMaxImageSize = 5 << 20
+	maxImagesPerProduct = 20
+)
+
+type ProductImageService struct {
+	repo        repository.ProductImageRepository
+	productRepo repository.ProductRepository
+	blobs       storage.BlobStore
+	maxSize     int64
+}
+
+func NewProductImageService(repo repository.ProductImageRepository, productRepo repository.ProductRepository, blobs storage.BlobStore, maxSize int64) *ProductImageService {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxImageSize
+	}
+
+	return &ProductImageService{
+		repo:        repo,
+		productRepo: productRepo,
+		blobs:       blobs,
+		maxSize:     maxSize,
+	}
+}
+
+func (s *ProductImageService) MaxSize() int64 {
+	return s.maxSize
+}
+
+func (s *ProductImageService) ensureProduct(ctx context.Context, productID uint) error {
+	if productID == 0 {
+		return ErrInvalidProductID
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+func (s *ProductImageService) GetImages(ctx context.Context, productID uint) ([]domain.ProductImage, error) {
+	if err := s.ensureProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	images, err := s.repo.GetByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if images == nil {
+		return []domain.ProductImage{}, nil
+	}
+	return images, nil
+}
+
+// Upload validates and stores an image together with a thumbnail and appends
+// it to the product's images. The first image of a product becomes primary,
+// as does any image uploaded with primary set.
+func (s *ProductImageService) Upload(ctx context.Context, productID uint, body io.Reader, primary bool) (*domain.ProductImage, error) {
+	if err := s.ensureProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	images, err := s.repo.GetByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) >= maxImagesPerProduct {
+		return nil, ErrTooManyImages
+	}
+
+	// Read one byte past the limit to detect oversized uploads
+	data, err := io.ReadAll(io.LimitReader(body, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrImageTooLarge
+	}
+
+	contentType, extension, err := sniffImage(data)
+	if err != nil {
+		return nil, err
+	}
+	img, err := decodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+	thumb, thumbType, thumbExtension, err := thumbnail(img, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("error creating thumbnail: %w", err)
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("products/%d/%s.%s", productID, name, extension)
+	thumbKey := fmt.Sprintf("products/%d/%s_thumb.%s", productID, name, thumbExtension)
+
+	if err := s.blobs.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return nil, err
+	}
+	if err := s.blobs.Put(ctx, thumbKey, bytes.NewReader(thumb), int64(len(thumb)), thumbType); err != nil {
+		deleteBlobs(ctx, s.blobs, key)
+		return nil, err
+	}
+
+	image := &domain.ProductImage{
+		ProductID:    productID,
+		Key:          key,
+		ThumbnailKey: thumbKey,
+		URL:          s.blobs.URL(key),
+		ThumbnailURL: s.blobs.URL(thumbKey),
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Width:        img.Bounds().Dx(),
+		Height:       img.Bounds().Dy(),
+		Position:     len(images),
+		IsPrimary:    primary || len(images) == 0,
+	}
+	if err := s.repo.Create(ctx, image); err != nil {
+		deleteBlobs(ctx, s.blobs, key, thumbKey)
+		return nil, err
+	}
+
+	if image.IsPrimary && len(images) > 0 {
+		for i := range images {
+			images[i].IsPrimary = false
+		}
+		if err := s.repo.UpdateAll(ctx, images); err != nil {
+			return nil, err
+		}
+	}
+	return image, nil
+}
+
+// Reorder sets the image positions to the order of imageIDs, which must list
+// every image of the product exactly once.
+func (s *ProductImageService) Reorder(ctx context.Context, productID uint, imageIDs []uint) ([]domain.ProductImage, error) {
+	images, err := s.GetImages(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(imageIDs) != len(images) {
+		return nil, ErrInvalidImageOrder
+	}
+
+	byID := make(map[uint]domain.ProductImage, len(images))
+	for _, image := range images {
+		byID[image.ID] = image
+	}
+
+	ordered := make([]domain.ProductImage, 0, len(images))
+	for position, id := range imageIDs {
+		image, ok := byID[id]
+		if !ok {
+			return nil, ErrInvalidImageOrder
+		}
+		delete(byID, id)
+		image.Position = position
+		ordered = append(ordered, image)
+	}
+
+	if err := s.repo.UpdateAll(ctx, ordered); err != nil {
+		return nil, err
+	}
+	return ordered, nil
+}
+
+// SetPrimary makes one image the primary image of its product.
+func (s *ProductImageService) SetPrimary(ctx context.Context, productID, imageID uint) ([]domain.ProductImage, error) {
+	images, err := s.GetImages(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for i := range images {
+		images[i].IsPrimary = images[i].ID == imageID
+		found = found || images[i].IsPrimary
+	}
+	if !found {
+		return nil, ErrImageNotFound
+	}
+
+	if err := s.repo.UpdateAll(ctx, images); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// Delete removes an image and its files. Remaining images are renumbered and,
+// if the primary image was deleted, the first remaining image becomes primary.
+func (s *ProductImageService) Delete(ctx context.Context, productID, imageID uint) error {
+	images, err := s.GetImages(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	var deleted *domain.ProductImage
+	remaining := make([]domain.ProductImage, 0, len(images))
+	for i := range images {
+		if images[i].ID == imageID {
+			deleted = &images[i]
+			continue
+		}
+		remaining = append(remaining, images[i])
+	}
+	if deleted == nil {
+		return ErrImageNotFound
+	}
+
+	if err := s.repo.Delete(ctx, imageID); err != nil {
+		return err
+	}
+	deleteBlobs(ctx, s.blobs, deleted.Key, deleted.ThumbnailKey)
+
+	for i := range remaining {
+		remaining[i].Position = i
+		if deleted.IsPrimary {
+			remaining[i].IsPrimary = i == 0
+		}
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+	return s.repo.UpdateAll(ctx, remaining)
+}
+
+// deleteBlobs removes stored files on a best-effort basis; a leftover file is
+// harmless, so failures are only logged.
+func deleteBlobs(ctx context.Context, blobs storage.BlobStore, keys ...string) {
+	for _, key := range keys {
+		if err := blobs.Delete(ctx, key); err != nil {
+			log.Printf("error deleting blob %s: %v", key, err)
+		}
+	}
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating image name: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,286 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/euro1061/gohex/internal/adapters/storage/local"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/storage"
+)
+
+// imageFixture is a product without images and an image service storing
+// files in a temporary directory.
+type imageFixture struct {
+	images  *ProductImageService
+	blobs   storage.BlobStore
+	product *domain.Product
+}
+
+func newImageFixture(t *testing.T, maxSize int64) *imageFixture {
+	t.Helper()
+	repos := memoryRepositories()
+	blobs, err := local.NewBlobStore(t.TempDir(), "/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &imageFixture{
+		images:  NewProductImageService(repos.Images, repos.Products, blobs, maxSize),
+		blobs:   blobs,
+		product: &domain.Product{SKU: "TEE-1", Slug: "tee", Name: "Tee", Price: 20},
+	}
+	if err := repos.Products.Create(context.Background(), f.product); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// pngData encodes a blank width by height PNG.
+func pngData(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var data bytes.Buffer
+	if err := png.Encode(&data, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return data.Bytes()
+}
+
+// upload adds n images to the product and returns their IDs.
+func (f *imageFixture) upload(t *testing.T, n int) []uint {
+	t.Helper()
+	ids := make([]uint, n)
+	for i := range ids {
+		uploaded, err := f.images.Upload(context.Background(), f.product.ID, bytes.NewReader(pngData(t, 4, 4)), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = uploaded.ID
+	}
+	return ids
+}
+
+// order returns the image IDs of the product by position, and the primary one.
+func (f *imageFixture) order(t *testing.T) ([]uint, uint) {
+	t.Helper()
+	images, err := f.images.GetImages(context.Background(), f.product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]uint, len(images))
+	var primary uint
+	for i, image := range images {
+		if image.Position != i {
+			t.Errorf("image %d has position %d, want %d", image.ID, image.Position, i)
+		}
+		if image.IsPrimary {
+			if primary != 0 {
+				t.Errorf("images %d and %d are both primary", primary, image.ID)
+			}
+			primary = image.ID
+		}
+		ids[i] = image.ID
+	}
+	return ids, primary
+}
+
+func TestUploadImage(t *testing.T) {
+	ctx := context.Background()
+	f := newImageFixture(t, 0)
+
+	first, err := f.images.Upload(ctx, f.product.ID, bytes.NewReader(pngData(t, 640, 480)), false)
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if first.Width != 640 || first.Height != 480 || first.ContentType != "image/png" {
+		t.Errorf("Upload() = %dx%d %s, want 640x480 image/png", first.Width, first.Height, first.ContentType)
+	}
+	if !first.IsPrimary || first.Position != 0 {
+		t.Errorf("first image primary = %v at %d, want primary at 0", first.IsPrimary, first.Position)
+	}
+	if !strings.HasPrefix(first.URL, "/uploads/") || !strings.HasPrefix(first.ThumbnailURL, "/uploads/") {
+		t.Errorf("Upload() URLs = %s, %s, want them under /uploads/", first.URL, first.ThumbnailURL)
+	}
+
+	thumb, err := f.blobs.Get(ctx, first.ThumbnailKey)
+	if err != nil {
+		t.Fatalf("thumbnail not stored: %v", err)
+	}
+	data, err := io.ReadAll(thumb)
+	thumb.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("thumbnail is not a PNG: %v", err)
+	}
+	if config.Width != 320 || config.Height != 240 {
+		t.Errorf("thumbnail = %dx%d, want 320x240", config.Width, config.Height)
+	}
+
+	second, err := f.images.Upload(ctx, f.product.ID, bytes.NewReader(pngData(t, 4, 4)), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.IsPrimary || second.Position != 1 {
+		t.Errorf("second image primary = %v at %d, want not primary at 1", second.IsPrimary, second.Position)
+	}
+
+	third, err := f.images.Upload(ctx, f.product.ID, bytes.NewReader(pngData(t, 4, 4)), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, primary := f.order(t)
+	if want := []uint{first.ID, second.ID, third.ID}; !slices.Equal(ids, want) || primary != third.ID {
+		t.Errorf("images = %v with primary %d, want %v with primary %d", ids, primary, want, third.ID)
+	}
+}
+
+func TestUploadImageRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    func(t *testing.T) []byte
+		maxSize int64
+		wantErr error
+	}{
+		{"too large", func(t *testing.T) []byte { return pngData(t, 64, 64) }, 100, ErrImageTooLarge},
+		{"not an image", func(t *testing.T) []byte { return []byte("just some text") }, 0, ErrUnsupportedImageType},
+		{"corrupt", func(t *testing.T) []byte { return pngData(t, 4, 4)[:30] }, 0, ErrInvalidImage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newImageFixture(t, tt.maxSize)
+			_, err := f.images.Upload(context.Background(), f.product.ID, bytes.NewReader(tt.data(t)), false)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Upload() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("unknown product", func(t *testing.T) {
+		f := newImageFixture(t, 0)
+		_, err := f.images.Upload(context.Background(), 99, bytes.NewReader(pngData(t, 4, 4)), false)
+		if !errors.Is(err, ErrProductNotFound) {
+			t.Errorf("Upload() error = %v, want %v", err, ErrProductNotFound)
+		}
+	})
+
+	t.Run("too many images", func(t *testing.T) {
+		f := newImageFixture(t, 0)
+		f.upload(t, maxImagesPerProduct)
+		_, err := f.images.Upload(context.Background(), f.product.ID, bytes.NewReader(pngData(t, 4, 4)), false)
+		if !errors.Is(err, ErrTooManyImages) {
+			t.Errorf("Upload() error = %v, want %v", err, ErrTooManyImages)
+		}
+	})
+}
+
+func TestReorderImages(t *testing.T) {
+	f := newImageFixture(t, 0)
+	ids := f.upload(t, 3)
+
+	tests := []struct {
+		name    string
+		order   []uint
+		wantErr error
+	}{
+		{"missing image", []uint{ids[2], ids[0]}, ErrInvalidImageOrder},
+		{"repeated image", []uint{ids[2], ids[0], ids[0]}, ErrInvalidImageOrder},
+		{"unknown image", []uint{ids[2], ids[0], 99}, ErrInvalidImageOrder},
+		{"every image once", []uint{ids[2], ids[0], ids[1]}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.images.Reorder(context.Background(), f.product.ID, tt.order)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Reorder() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	order, primary := f.order(t)
+	if want := []uint{ids[2], ids[0], ids[1]}; !slices.Equal(order, want) {
+		t.Errorf("images after Reorder() = %v, want %v", order, want)
+	}
+	if primary != ids[0] {
+		t.Errorf("primary after Reorder() = %d, want %d", primary, ids[0])
+	}
+}
+
+func TestSetPrimaryImage(t *testing.T) {
+	ctx := context.Background()
+	f := newImageFixture(t, 0)
+	ids := f.upload(t, 3)
+
+	if _, err := f.images.SetPrimary(ctx, f.product.ID, ids[1]); err != nil {
+		t.Fatalf("SetPrimary() error = %v", err)
+	}
+	if _, primary := f.order(t); primary != ids[1] {
+		t.Errorf("primary = %d, want %d", primary, ids[1])
+	}
+
+	if _, err := f.images.SetPrimary(ctx, f.product.ID, 99); !errors.Is(err, ErrImageNotFound) {
+		t.Errorf("SetPrimary() unknown image error = %v, want %v", err, ErrImageNotFound)
+	}
+	if _, primary := f.order(t); primary != ids[1] {
+		t.Errorf("primary after a failed SetPrimary() = %d, want %d", primary, ids[1])
+	}
+}
+
+func TestDeleteImage(t *testing.T) {
+	tests := []struct {
+		name        string
+		delete      int
+		wantOrder   []int
+		wantPrimary int
+	}{
+		{"primary passes to the next image", 0, []int{1, 2}, 1},
+		{"middle image closes the gap", 1, []int{0, 2}, 0},
+		{"last image", 2, []int{0, 1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newImageFixture(t, 0)
+			ids := f.upload(t, 3)
+			images, err := f.images.GetImages(ctx, f.product.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			deleted := images[tt.delete]
+
+			if err := f.images.Delete(ctx, f.product.ID, deleted.ID); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+
+			order, primary := f.order(t)
+			want := make([]uint, len(tt.wantOrder))
+			for i, index := range tt.wantOrder {
+				want[i] = ids[index]
+			}
+			if !slices.Equal(order, want) || primary != ids[tt.wantPrimary] {
+				t.Errorf("images = %v with primary %d, want %v with primary %d", order, primary, want, ids[tt.wantPrimary])
+			}
+			for _, key := range []string{deleted.Key, deleted.ThumbnailKey} {
+				if _, err := f.blobs.Get(ctx, key); err == nil {
+					t.Errorf("blob %s still stored", key)
+				}
+			}
+		})
+	}
+
+	t.Run("unknown image", func(t *testing.T) {
+		f := newImageFixture(t, 0)
+		f.upload(t, 1)
+		if err := f.images.Delete(context.Background(), f.product.ID, 99); !errors.Is(err, ErrImageNotFound) {
+			t.Errorf("Delete() error = %v, want %v", err, ErrImageNotFound)
+		}
+	})
+}
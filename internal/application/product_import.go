@@ -0,0 +1,181 @@
+package application
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+const (
+	// csvTagSeparator separates the tags within the tags column of a CSV file.
+	csvTagSeparator = "|"
+	maxNDJSONLine   = 1 << 20
+)
+
+// csvColumns lists the columns a CSV import understands; required ones must
+// be present. The id column written by exports is accepted and ignored.
+var csvColumns = map[string]bool{
+	"id":          false,
+	"sku":         true,
+	"name":        true,
+	"description": true,
+	"price":       true,
+	"barcode":     false,
+	"slug":        false,
+	"tags":        false,
+	"tax_class":   false,
+}
+
+// importRow is one parsed row of an import file. err is set when the row
+// could not be parsed; the import reports it and moves on.
+type importRow struct {
+	line    int
+	product *domain.Product
+	err     error
+}
+
+// rowReader yields the rows of an import file one at a time and returns
+// io.EOF after the last row. Any other error means the file cannot be read
+// any further.
+type rowReader interface {
+	next() (importRow, error)
+}
+
+func newRowReader(format domain.ImportFormat, r io.Reader) (rowReader, error) {
+	switch format {
+	case domain.ImportFormatCSV:
+		return newCSVRowReader(r)
+	case domain.ImportFormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxNDJSONLine)
+		return &ndjsonRowReader{scanner: scanner}, nil
+	}
+	return nil, ErrInvalidImportFormat
+}
+
+type csvRowReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+// newCSVRowReader reads the header row, which names the columns in any order.
+func newCSVRowReader(r io.Reader) (*csvRowReader, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidImportFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImportFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := csvColumns[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidImportFile, name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidImportFile, name)
+		}
+		columns[name] = i
+	}
+	for name, required := range csvColumns {
+		if _, ok := columns[name]; required && !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidImportFile, name)
+		}
+	}
+
+	return &csvRowReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvRowReader) next() (importRow, error) {
+	record, err := r.reader.Read()
+	if err == io.EOF {
+		return importRow{}, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return importRow{line: parseErr.StartLine, err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return importRow{}, fmt.Errorf("error reading import file: %w", err)
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	row := importRow{line: line}
+
+	value := func(name string) (string, bool) {
+		i, ok := r.columns[name]
+		if !ok {
+			return "", false
+		}
+		return strings.TrimSpace(record[i]), true
+	}
+
+	product := &domain.Product{}
+	product.SKU, _ = value("sku")
+	product.Name, _ = value("name")
+	product.Description, _ = value("description")
+	product.Slug, _ = value("slug")
+	product.TaxClass, _ = value("tax_class")
+	if barcode, ok := value("barcode"); ok {
+		product.Barcode = &barcode
+	}
+	if tags, ok := value("tags"); ok {
+		product.Tags = []string{}
+		if tags != "" {
+			product.Tags = strings.Split(tags, csvTagSeparator)
+		}
+	}
+
+	price, _ := value("price")
+	product.Price, err = strconv.ParseFloat(price, 64)
+	if err != nil {
+		row.err = errors.New("price must be a number")
+	}
+
+	row.product = product
+	return row, nil
+}
+
+type ndjsonRowReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// next decodes the next non-blank line as a product in the same JSON shape
+// accepted by POST /products.
+func (r *ndjsonRowReader) next() (importRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+		data := strings.TrimSpace(r.scanner.Text())
+		if data == "" {
+			continue
+		}
+
+		row := importRow{line: r.line, product: &domain.Product{}}
+		if err := json.Unmarshal([]byte(data), row.product); err != nil {
+			row.err = fmt.Errorf("invalid JSON: %w", err)
+		}
+		return row, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return importRow{}, fmt.Errorf("%w: line %d is longer than %d bytes", ErrInvalidImportFile, r.line+1, maxNDJSONLine)
+		}
+		return importRow{}, fmt.Errorf("error reading import file: %w", err)
+	}
+	return importRow{}, io.EOF
+}
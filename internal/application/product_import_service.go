@@ -0,0 +1,307 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+var (
+	ErrInvalidImportFormat    = unsupportedError("import format must be either csv or ndjson")
+	ErrInvalidImportMode      = fieldError("mode", "oneof", "import mode must be either create or upsert")
+	ErrInvalidImportBatchSize = fieldError("batch_size", "range", "import batch size must be between 0 and 10000")
+	ErrInvalidImportFile      = validationError("invalid import file")
+	ErrImportTooLarge         = tooLargeError("import file exceeds the maximum size")
+	ErrImportJobNotFound      = notFoundError("import job not found")
+)
+
+// errRollback makes a transaction roll back without being reported as a failure.
+var errRollback = errors.New("rollback")
+
+const (
+	// DefaultMaxImportSize is the import file limit used when none is configured.
+	DefaultMaxImportSize = 50 << 20
+	maxImportBatchSize   = 10000
+	// maxReportedErrors bounds the number of row errors kept in a report.
+	maxReportedErrors = 1000
+	// importJobRetention is how long finished jobs can still be queried.
+	importJobRetention = 24 * time.Hour
+)
+
+// ImportOptions controls a product import. With a BatchSize of zero the whole
+// file is imported in one transaction that is rolled back if any row fails.
+// Otherwise rows are committed in batches of that size and failing rows are
+// skipped. A dry run validates every row in one transaction and always rolls
+// it back. Actor is recorded in the revisions and price history of the
+// imported products.
+type ImportOptions struct {
+	Format    domain.ImportFormat
+	Mode      domain.ImportMode
+	DryRun    bool
+	BatchSize int
+	Actor     string
+}
+
+type ProductImportService struct {
+	products *ProductService
+	maxSize  int64
+
+	mu   sync.RWMutex
+	jobs map[string]*domain.ImportJob
+}
+
+func NewProductImportService(products *ProductService, maxSize int64) *ProductImportService {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxImportSize
+	}
+
+	return &ProductImportService{
+		products: products,
+		maxSize:  maxSize,
+		jobs:     make(map[string]*domain.ImportJob),
+	}
+}
+
+func (s *ProductImportService) MaxSize() int64 {
+	return s.maxSize
+}
+
+func (s *ProductImportService) validateOptions(options *ImportOptions) error {
+	switch options.Format {
+	case domain.ImportFormatCSV, domain.ImportFormatNDJSON:
+	default:
+		return ErrInvalidImportFormat
+	}
+	switch options.Mode {
+	case "":
+		options.Mode = domain.ImportModeCreate
+	case domain.ImportModeCreate, domain.ImportModeUpsert:
+	default:
+		return ErrInvalidImportMode
+	}
+	if options.BatchSize < 0 || options.BatchSize > maxImportBatchSize {
+		return ErrInvalidImportBatchSize
+	}
+	if options.DryRun {
+		options.BatchSize = 0
+	}
+	return nil
+}
+
+// Import reads products from r and creates or updates them through the same
+// validation as CreateProduct and UpdateProduct. Row problems are collected
+// in the report; an error is only returned when the file itself is unusable.
+func (s *ProductImportService) Import(ctx context.Context, r io.Reader, options ImportOptions) (*domain.ImportReport, error) {
+	if err := s.validateOptions(&options); err != nil {
+		return nil, err
+	}
+	return s.run(ctx, r, options, func(int) {})
+}
+
+// StartImport runs Import in the background and returns the job tracking it.
+// r must stay readable until the job finishes.
+func (s *ProductImportService) StartImport(ctx context.Context, r io.Reader, options ImportOptions) (*domain.ImportJob, error) {
+	if err := s.validateOptions(&options); err != nil {
+		return nil, err
+	}
+
+	id, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	job := &domain.ImportJob{
+		ID:        id,
+		Status:    domain.ImportJobPending,
+		CreatedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.pruneJobs()
+	s.jobs[id] = job
+	snapshot := *job
+	s.mu.Unlock()
+
+	// The job outlives the request that started it
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		s.updateJob(id, func(job *domain.ImportJob) { job.Status = domain.ImportJobRunning })
+
+		report, err := s.run(ctx, r, options, func(processed int) {
+			s.updateJob(id, func(job *domain.ImportJob) { job.Processed = processed })
+		})
+
+		s.updateJob(id, func(job *domain.ImportJob) {
+			now := time.Now()
+			job.FinishedAt = &now
+			if err != nil {
+				log.Printf("import job %s failed: %v", id, err)
+				job.Status = domain.ImportJobFailed
+				job.Error = err.Error()
+				return
+			}
+			job.Status = domain.ImportJobCompleted
+			job.Processed = report.Total
+			job.Report = report
+		})
+	}()
+
+	return &snapshot, nil
+}
+
+// GetJob returns a snapshot of an import job.
+func (s *ProductImportService) GetJob(id string) (*domain.ImportJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, exists := s.jobs[id]
+	if !exists {
+		return nil, ErrImportJobNotFound
+	}
+	snapshot := *job
+	return &snapshot, nil
+}
+
+func (s *ProductImportService) updateJob(id string, update func(job *domain.ImportJob)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, exists := s.jobs[id]; exists {
+		update(job)
+	}
+}
+
+// pruneJobs forgets jobs that finished longer than importJobRetention ago.
+// The caller must hold s.mu.
+func (s *ProductImportService) pruneJobs() {
+	cutoff := time.Now().Add(-importJobRetention)
+	for id, job := range s.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// run imports the rows of r, calling progress with the number of rows read
+// after each row.
+func (s *ProductImportService) run(ctx context.Context, r io.Reader, options ImportOptions, progress func(processed int)) (*domain.ImportReport, error) {
+	rows, err := newRowReader(options.Format, r)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.ImportReport{
+		DryRun: options.DryRun,
+		Errors: []domain.ImportRowError{},
+	}
+	fail := func(row importRow, err error) {
+		report.Failed++
+		if len(report.Errors) == maxReportedErrors {
+			report.ErrorsTruncated = true
+			return
+		}
+		rowError := domain.ImportRowError{Line: row.line, Error: err.Error()}
+		if row.product != nil {
+			rowError.SKU = strings.TrimSpace(row.product.SKU)
+		}
+		report.Errors = append(report.Errors, rowError)
+	}
+
+	for done := false; !done; {
+		err := s.products.transaction(ctx, func(products *ProductService) error {
+			failedBefore := report.Failed
+
+			for n := 0; options.BatchSize == 0 || n < options.BatchSize; n++ {
+				row, err := rows.next()
+				if err == io.EOF {
+					done = true
+					break
+				}
+				if err != nil {
+					return err
+				}
+
+				report.Total++
+				if row.err != nil {
+					fail(row, row.err)
+				} else if created, err := importProduct(ctx, products, row.product, options); err != nil {
+					fail(row, err)
+				} else if created {
+					report.Created++
+				} else {
+					report.Updated++
+				}
+				progress(report.Total)
+			}
+
+			if options.DryRun || (options.BatchSize == 0 && report.Failed > failedBefore) {
+				return errRollback
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errRollback) {
+			return nil, err
+		}
+		// Only single transaction imports and dry runs are rolled back, and
+		// those run in one pass of this loop
+		report.Committed = err == nil
+	}
+
+	return report, nil
+}
+
+// importProduct creates product or, in upsert mode, updates the product that
+// already has its SKU. It reports whether a product was created. On update,
+// nil tags, options, variants and barcode keep their stored values, and
+// variants are matched to the stored ones by SKU.
+func importProduct(ctx context.Context, products *ProductService, product *domain.Product, options ImportOptions) (bool, error) {
+	product.ID = 0
+	product.Images = nil
+
+	if options.Mode == domain.ImportModeUpsert {
+		sku, err := normalizeSKU(product.SKU)
+		if err != nil {
+			return false, err
+		}
+		existing, err := products.repo.GetBySKU(ctx, sku)
+		if err != nil {
+			return false, err
+		}
+		if existing != nil {
+			product.ID = existing.ID
+			if product.Barcode == nil {
+				product.Barcode = existing.Barcode
+			}
+			if product.Tags == nil {
+				product.Tags = existing.Tags
+			}
+			if product.Options == nil {
+				product.Options = existing.Options
+			}
+			if product.Variants == nil {
+				product.Variants = existing.Variants
+			}
+
+			variantIDs := make(map[string]uint, len(existing.Variants))
+			for _, variant := range existing.Variants {
+				variantIDs[variant.SKU] = variant.ID
+			}
+			for i := range product.Variants {
+				variant := &product.Variants[i]
+				variant.ID = variantIDs[strings.ToUpper(strings.TrimSpace(variant.SKU))]
+			}
+
+			return false, products.UpdateProduct(ctx, product, Change{Actor: options.Actor, Reason: "import"})
+		}
+	}
+
+	if _, err := products.CreateProduct(ctx, product, Change{Actor: options.Actor, Reason: "import"}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+var (
+	ErrInvalidProductStatus    = fieldError("status", "oneof", "product status must be draft, pending_review, published or archived")
+	ErrInvalidProductAction    = fieldError("action", "oneof", "unknown product lifecycle action")
+	ErrInvalidTransition       = conflictError("product cannot make this transition from its current status")
+	ErrTransitionForbidden     = forbiddenError("your role does not allow this product transition")
+	ErrInvalidPublishWindow    = fieldError("unpublish_at", "gtfield", "product unpublish time must be after its publish time")
+	ErrRejectionReasonRequired = fieldError("reason", "required", "a reason is required to reject a product")
+)
+
+// productTransition describes a lifecycle action: the states it applies to,
+// the state it leads to and the least role allowed to take it.
+type productTransition struct {
+	from []domain.ProductStatus
+	to   domain.ProductStatus
+	role domain.Role
+}
+
+var productTransitions = map[domain.ProductAction]productTransition{
+	domain.ProductSubmit: {
+		from: []domain.ProductStatus{domain.ProductDraft},
+		to:   domain.ProductPendingReview,
+		role: domain.RoleEditor,
+	},
+	domain.ProductApprove: {
+		from: []domain.ProductStatus{domain.ProductPendingReview},
+		to:   domain.ProductPublished,
+		role: domain.RoleReviewer,
+	},
+	domain.ProductReject: {
+		from: []domain.ProductStatus{domain.ProductPendingReview},
+		to:   domain.ProductDraft,
+		role: domain.RoleReviewer,
+	},
+	domain.ProductUnpublish: {
+		from: []domain.ProductStatus{domain.ProductPublished},
+		to:   domain.ProductDraft,
+		role: domain.RoleEditor,
+	},
+	domain.ProductArchive: {
+		from: []domain.ProductStatus{domain.ProductDraft, domain.ProductPendingReview, domain.ProductPublished},
+		to:   domain.ProductArchived,
+		role: domain.RoleEditor,
+	},
+	domain.ProductRestore: {
+		from: []domain.ProductStatus{domain.ProductArchived},
+		to:   domain.ProductDraft,
+		role: domain.RoleEditor,
+	},
+}
+
+// validatePublishWindow checks the scheduled publish and unpublish times.
+func validatePublishWindow(product *domain.Product) error {
+	if product.PublishAt != nil && product.UnpublishAt != nil && !product.UnpublishAt.After(*product.PublishAt) {
+		return ErrInvalidPublishWindow
+	}
+	return nil
+}
+
+// Transition applies a lifecycle action to a product on behalf of change.Role.
+// A rejection needs a reason, which is kept as the review note until the
+// product is approved.
+func (s *ProductService) Transition(ctx context.Context, id uint, action domain.ProductAction, change Change) (*domain.Product, error) {
+	transition, ok := productTransitions[action]
+	if !ok {
+		return nil, ErrInvalidProductAction
+	}
+	if !change.Role.AtLeast(transition.role) {
+		return nil, ErrTransitionForbidden
+	}
+	reason := strings.TrimSpace(change.Reason)
+	if action == domain.ProductReject && reason == "" {
+		return nil, ErrRejectionReasonRequired
+	}
+
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+	if !containsProductStatus(transition.from, product.Status) {
+		return nil, ErrInvalidTransition
+	}
+
+	product.Status = transition.to
+	switch action {
+	case domain.ProductReject:
+		product.ReviewNote = reason
+	case domain.ProductApprove:
+		product.ReviewNote = ""
+	}
+
+	revisionChange := change
+	revisionChange.Reason = string(action)
+	if reason != "" {
+		revisionChange.Reason += ": " + reason
+	}
+	err = s.transaction(ctx, func(tx *ProductService) error {
+		if err := tx.repo.Update(ctx, product); err != nil {
+			return saveError(err)
+		}
+		return tx.recordRevision(ctx, product, revisionChange)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachProductDetails(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func containsProductStatus(statuses []domain.ProductStatus, status domain.ProductStatus) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/euro1061/gohex/internal/adapters/repository/memory"
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+// newLifecycleProduct returns a product service and the ID of a product
+// created as a draft and moved to status.
+func newLifecycleProduct(t *testing.T, status domain.ProductStatus) (*ProductService, uint) {
+	t.Helper()
+	ctx := context.Background()
+	repos := memoryRepositories()
+	service := NewProductService(repos.Products, repos.Categories, repos.Images, repos.Prices, repos.Reviews, nil, memory.NewUnitOfWork(repos))
+
+	product, err := service.CreateProduct(ctx, &domain.Product{SKU: "TEE-1", Name: "Tee", Description: "A tee", Price: 20}, Change{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != domain.ProductDraft {
+		product.Status = status
+		if err := repos.Products.Update(ctx, product); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return service, product.ID
+}
+
+func TestTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    domain.ProductStatus
+		action  domain.ProductAction
+		role    domain.Role
+		reason  string
+		want    domain.ProductStatus
+		wantErr error
+	}{
+		{"editor submits a draft", domain.ProductDraft, domain.ProductSubmit, domain.RoleEditor, "", domain.ProductPendingReview, nil},
+		{"reviewer approves", domain.ProductPendingReview, domain.ProductApprove, domain.RoleReviewer, "", domain.ProductPublished, nil},
+		{"admin approves", domain.ProductPendingReview, domain.ProductApprove, domain.RoleAdmin, "", domain.ProductPublished, nil},
+		{"reviewer rejects with a reason", domain.ProductPendingReview, domain.ProductReject, domain.RoleReviewer, "blurry photos", domain.ProductDraft, nil},
+		{"editor unpublishes", domain.ProductPublished, domain.ProductUnpublish, domain.RoleEditor, "", domain.ProductDraft, nil},
+		{"editor archives a draft", domain.ProductDraft, domain.ProductArchive, domain.RoleEditor, "", domain.ProductArchived, nil},
+		{"editor archives a pending product", domain.ProductPendingReview, domain.ProductArchive, domain.RoleEditor, "", domain.ProductArchived, nil},
+		{"editor archives a published product", domain.ProductPublished, domain.ProductArchive, domain.RoleEditor, "", domain.ProductArchived, nil},
+		{"editor restores", domain.ProductArchived, domain.ProductRestore, domain.RoleEditor, "", domain.ProductDraft, nil},
+
+		{"customer cannot submit", domain.ProductDraft, domain.ProductSubmit, domain.RoleCustomer, "", domain.ProductDraft, ErrTransitionForbidden},
+		{"editor cannot approve", domain.ProductPendingReview, domain.ProductApprove, domain.RoleEditor, "", domain.ProductPendingReview, ErrTransitionForbidden},
+		{"editor cannot reject", domain.ProductPendingReview, domain.ProductReject, domain.RoleEditor, "no", domain.ProductPendingReview, ErrTransitionForbidden},
+		{"unknown role", domain.ProductDraft, domain.ProductSubmit, domain.Role("owner"), "", domain.ProductDraft, ErrTransitionForbidden},
+		{"rejection needs a reason", domain.ProductPendingReview, domain.ProductReject, domain.RoleReviewer, "  ", domain.ProductPendingReview, ErrRejectionReasonRequired},
+		{"draft cannot be approved", domain.ProductDraft, domain.ProductApprove, domain.RoleReviewer, "", domain.ProductDraft, ErrInvalidTransition},
+		{"published cannot be submitted", domain.ProductPublished, domain.ProductSubmit, domain.RoleEditor, "", domain.ProductPublished, ErrInvalidTransition},
+		{"archived cannot be unpublished", domain.ProductArchived, domain.ProductUnpublish, domain.RoleEditor, "", domain.ProductArchived, ErrInvalidTransition},
+		{"archived cannot be archived", domain.ProductArchived, domain.ProductArchive, domain.RoleEditor, "", domain.ProductArchived, ErrInvalidTransition},
+		{"draft cannot be restored", domain.ProductDraft, domain.ProductRestore, domain.RoleEditor, "", domain.ProductDraft, ErrInvalidTransition},
+		{"unknown action", domain.ProductDraft, domain.ProductAction("publish"), domain.RoleAdmin, "", domain.ProductDraft, ErrInvalidProductAction},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service, id := newLifecycleProduct(t, tt.from)
+
+			_, err := service.Transition(ctx, id, tt.action, Change{Actor: "alice", Role: tt.role, Reason: tt.reason})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Transition() error = %v, want %v", err, tt.wantErr)
+			}
+			product, err := service.GetProduct(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if product.Status != tt.want {
+				t.Errorf("status = %s, want %s", product.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransitionUnknownProduct(t *testing.T) {
+	service, _ := newLifecycleProduct(t, domain.ProductDraft)
+	_, err := service.Transition(context.Background(), 99, domain.ProductSubmit, Change{Role: domain.RoleEditor})
+	if !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("Transition() error = %v, want %v", err, ErrProductNotFound)
+	}
+}
+
+func TestReviewCycle(t *testing.T) {
+	ctx := context.Background()
+	service, id := newLifecycleProduct(t, domain.ProductDraft)
+	editor := Change{Actor: "erin", Role: domain.RoleEditor}
+	reviewer := Change{Actor: "rita", Role: domain.RoleReviewer}
+	rejection := reviewer
+	rejection.Reason = " blurry photos "
+
+	steps := []struct {
+		action   domain.ProductAction
+		change   Change
+		wantNote string
+		wantLive bool
+	}{
+		{domain.ProductSubmit, editor, "", false},
+		{domain.ProductReject, rejection, "blurry photos", false},
+		{domain.ProductSubmit, editor, "blurry photos", false},
+		{domain.ProductApprove, reviewer, "", true},
+	}
+	for _, step := range steps {
+		product, err := service.Transition(ctx, id, step.action, step.change)
+		if err != nil {
+			t.Fatalf("Transition(%s) error = %v", step.action, err)
+		}
+		if product.ReviewNote != step.wantNote {
+			t.Errorf("after %s review note = %q, want %q", step.action, product.ReviewNote, step.wantNote)
+		}
+		if live := product.IsLive(time.Now()); live != step.wantLive {
+			t.Errorf("after %s live = %v, want %v", step.action, live, step.wantLive)
+		}
+	}
+
+	revisions, err := service.GetRevisions(ctx, id, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		actor  string
+		reason string
+		status domain.ProductStatus
+	}{
+		{"rita", "approve", domain.ProductPublished},
+		{"erin", "submit", domain.ProductPendingReview},
+		{"rita", "reject: blurry photos", domain.ProductDraft},
+		{"erin", "submit", domain.ProductPendingReview},
+		{SystemActor, "", domain.ProductDraft},
+	}
+	if len(revisions) != len(want) {
+		t.Fatalf("GetRevisions() = %d revisions, want %d", len(revisions), len(want))
+	}
+	for i, w := range want {
+		revision := revisions[i]
+		if revision.Actor != w.actor || revision.Reason != w.reason || revision.Snapshot.Status != w.status {
+			t.Errorf("revision %d = %s %q %s, want %s %q %s",
+				revision.Number, revision.Actor, revision.Reason, revision.Snapshot.Status, w.actor, w.reason, w.status)
+		}
+	}
+}
+
+func TestCreateProductValidatesPublishWindow(t *testing.T) {
+	service, _ := newLifecycleProduct(t, domain.ProductDraft)
+	publishAt := time.Now().Add(time.Hour)
+	unpublishAt := publishAt.Add(-time.Minute)
+
+	_, err := service.CreateProduct(context.Background(), &domain.Product{
+		SKU: "TEE-2", Name: "Tee 2", Description: "A tee", Price: 20,
+		PublishAt: &publishAt, UnpublishAt: &unpublishAt,
+	}, Change{})
+	if !errors.Is(err, ErrInvalidPublishWindow) {
+		t.Errorf("CreateProduct() error = %v, want %v", err, ErrInvalidPublishWindow)
+	}
+}
@@ -0,0 +1,188 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+var ErrRevisionNotFound = notFoundError("product revision not found")
+
+const maxRevisions = 200
+
+// recordRevision stores the current content of product as its next revision.
+func (s *ProductService) recordRevision(ctx context.Context, product *domain.Product, change Change) error {
+	return s.repo.CreateRevision(ctx, &domain.ProductRevision{
+		ProductID: product.ID,
+		Snapshot:  domain.NewProductSnapshot(product),
+		Actor:     change.actor(),
+		Reason:    strings.TrimSpace(change.Reason),
+		CreatedAt: time.Now(),
+	})
+}
+
+// GetRevisions returns the most recent revisions of a product, newest first.
+func (s *ProductService) GetRevisions(ctx context.Context, id uint, limit int) ([]domain.ProductRevision, error) {
+	if id == 0 {
+		return nil, ErrInvalidProductID
+	}
+	if limit <= 0 || limit > maxRevisions {
+		limit = maxRevisions
+	}
+
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+
+	revisions, err := s.repo.GetRevisions(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	if revisions == nil {
+		return []domain.ProductRevision{}, nil
+	}
+	return revisions, nil
+}
+
+func (s *ProductService) GetRevision(ctx context.Context, id uint, number int) (*domain.ProductRevision, error) {
+	if id == 0 {
+		return nil, ErrInvalidProductID
+	}
+
+	revision, err := s.repo.GetRevision(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, ErrRevisionNotFound
+	}
+	return revision, nil
+}
+
+// DiffRevisions lists the snapshot fields that differ between revisions from
+// and to of a product, in snapshot field order.
+func (s *ProductService) DiffRevisions(ctx context.Context, id uint, from, to int) (*domain.RevisionDiff, error) {
+	fromRevision, err := s.GetRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.GetRevision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diffSnapshots(fromRevision.Snapshot, toRevision.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.RevisionDiff{
+		ProductID: id,
+		From:      from,
+		To:        to,
+		Changes:   changes,
+	}, nil
+}
+
+// RestoreRevision puts the content of a revision back through UpdateProduct,
+// which records the result as a new revision. The lifecycle status is not
+// restored since it only changes through Transition, and variants deleted
+// since the revision are created again. Revisions that did not record the
+// currency prices keep the current ones.
+func (s *ProductService) RestoreRevision(ctx context.Context, id uint, number int, change Change) (*domain.Product, error) {
+	revision, err := s.GetRevision(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrProductNotFound
+	}
+
+	snapshot := revision.Snapshot
+	product := &domain.Product{
+		ID:          id,
+		SKU:         snapshot.SKU,
+		Barcode:     snapshot.Barcode,
+		Slug:        snapshot.Slug,
+		Name:        snapshot.Name,
+		Description: snapshot.Description,
+		Price:       snapshot.Price,
+		Tags:        snapshot.Tags,
+		TaxClass:    snapshot.TaxClass,
+		PublishAt:   snapshot.PublishAt,
+		UnpublishAt: snapshot.UnpublishAt,
+		Options:     snapshot.Options,
+		Variants:    snapshot.Variants,
+	}
+	// Older snapshots may not know the currency prices, keep the current ones
+	product.CurrencyPrices = existing.CurrencyPrices
+	if snapshot.HasCurrencyPrices() {
+		product.CurrencyPrices = snapshot.CurrencyPrices
+	}
+	known := make(map[uint]struct{}, len(existing.Variants))
+	for _, variant := range existing.Variants {
+		known[variant.ID] = struct{}{}
+	}
+	for i := range product.Variants {
+		if _, ok := known[product.Variants[i].ID]; !ok {
+			product.Variants[i].ID = 0
+		}
+	}
+
+	reason := fmt.Sprintf("restored revision %d", number)
+	if change.Reason != "" {
+		reason += ": " + strings.TrimSpace(change.Reason)
+	}
+	change.Reason = reason
+	if err := s.UpdateProduct(ctx, product, change); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// diffSnapshots compares two snapshots field by field through their JSON
+// encoding, so nil and empty values that encode alike are not reported.
+func diffSnapshots(from, to domain.ProductSnapshot) ([]domain.FieldChange, error) {
+	changes := []domain.FieldChange{}
+	fromValue, toValue := reflect.ValueOf(from), reflect.ValueOf(to)
+	snapshotType := fromValue.Type()
+
+	for i := 0; i < snapshotType.NumField(); i++ {
+		field := snapshotType.Field(i)
+		// The schema version is not content
+		if field.Name == "Version" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		before, err := json.Marshal(fromValue.Field(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		after, err := json.Marshal(toValue.Field(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		if string(before) != string(after) {
+			changes = append(changes, domain.FieldChange{
+				Field: name,
+				From:  fromValue.Field(i).Interface(),
+				To:    toValue.Field(i).Interface(),
+			})
+		}
+	}
+	return changes, nil
+}
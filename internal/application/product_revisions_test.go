@@ -0,0 +1,259 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/euro1061/gohex/internal/adapters/repository/memory"
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+func TestRestoreRevisionCurrencyPrices(t *testing.T) {
+	// A snapshot recorded before currency prices existed
+	var old domain.ProductSnapshot
+	if err := json.Unmarshal([]byte(`{
+		"sku": "TEE-1", "slug": "tee", "name": "Old tee", "description": "A tee",
+		"price": 15, "tags": [], "status": "draft", "options": [], "variants": []
+	}`), &old); err != nil {
+		t.Fatal(err)
+	}
+	current := domain.NewProductSnapshot(&domain.Product{
+		SKU: "TEE-1", Slug: "tee", Name: "Current tee", Description: "A tee", Price: 15,
+	})
+
+	tests := []struct {
+		name     string
+		snapshot domain.ProductSnapshot
+		want     map[string]float64
+	}{
+		{"unversioned snapshot keeps the current prices", old, map[string]float64{"EUR": 18}},
+		{"versioned snapshot restores its prices", current, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repos := memoryRepositories()
+			service := NewProductService(repos.Products, repos.Categories, repos.Images, repos.Prices, repos.Reviews, nil, memory.NewUnitOfWork(repos))
+
+			product, err := service.CreateProduct(ctx, &domain.Product{
+				SKU:            "TEE-1",
+				Name:           "Tee",
+				Description:    "A tee",
+				Price:          20,
+				CurrencyPrices: map[string]float64{"EUR": 18},
+			}, Change{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			revision := &domain.ProductRevision{ProductID: product.ID, Snapshot: tt.snapshot, Actor: SystemActor}
+			if err := repos.Products.CreateRevision(ctx, revision); err != nil {
+				t.Fatal(err)
+			}
+
+			restored, err := service.RestoreRevision(ctx, product.ID, revision.Number, Change{})
+			if err != nil {
+				t.Fatalf("RestoreRevision() error = %v", err)
+			}
+			if restored.Name != tt.snapshot.Name || restored.Price != tt.snapshot.Price {
+				t.Errorf("restored %q at %v, want %q at %v", restored.Name, restored.Price, tt.snapshot.Name, tt.snapshot.Price)
+			}
+			if !reflect.DeepEqual(restored.CurrencyPrices, tt.want) {
+				t.Errorf("CurrencyPrices = %v, want %v", restored.CurrencyPrices, tt.want)
+			}
+		})
+	}
+}
+
+// newRevisedProduct creates a tee with a small and a large variant, then
+// renames it, drops the large variant and raises its price, leaving revisions
+// 1 and 2.
+func newRevisedProduct(t *testing.T) (*ProductService, *domain.Product) {
+	t.Helper()
+	ctx := context.Background()
+	repos := memoryRepositories()
+	service := NewProductService(repos.Products, repos.Categories, repos.Images, repos.Prices, repos.Reviews, nil, memory.NewUnitOfWork(repos))
+
+	product, err := service.CreateProduct(ctx, &domain.Product{
+		SKU:         "TEE-1",
+		Name:        "Tee",
+		Description: "A tee",
+		Price:       20,
+		Tags:        []string{"cotton"},
+		Options:     []domain.ProductOption{{Name: "Size", Values: []string{"S", "L"}}},
+		Variants: []domain.ProductVariant{
+			{SKU: "TEE-1-S", Price: 20, Attributes: map[string]string{"Size": "S"}},
+			{SKU: "TEE-1-L", Price: 22, Attributes: map[string]string{"Size": "L"}},
+		},
+	}, Change{Actor: "erin", Reason: "new product"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := service.GetProduct(ctx, product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated.Name = "Classic tee"
+	updated.Price = 25
+	updated.Options = []domain.ProductOption{{Name: "Size", Values: []string{"S"}}}
+	updated.Variants = updated.Variants[:1]
+	if err := service.UpdateProduct(ctx, updated, Change{Actor: "rita", Reason: " price rise "}); err != nil {
+		t.Fatal(err)
+	}
+	return service, updated
+}
+
+func TestUpdateRecordsRevisions(t *testing.T) {
+	service, product := newRevisedProduct(t)
+
+	revisions, err := service.GetRevisions(context.Background(), product.ID, 0)
+	if err != nil {
+		t.Fatalf("GetRevisions() error = %v", err)
+	}
+	want := []struct {
+		number int
+		actor  string
+		reason string
+		name   string
+		price  float64
+	}{
+		{2, "rita", "price rise", "Classic tee", 25},
+		{1, "erin", "new product", "Tee", 20},
+	}
+	if len(revisions) != len(want) {
+		t.Fatalf("GetRevisions() = %d revisions, want %d", len(revisions), len(want))
+	}
+	for i, w := range want {
+		revision := revisions[i]
+		if revision.Number != w.number || revision.Actor != w.actor || revision.Reason != w.reason {
+			t.Errorf("revision %d = %d by %s %q, want %d by %s %q",
+				i, revision.Number, revision.Actor, revision.Reason, w.number, w.actor, w.reason)
+		}
+		if revision.Snapshot.Name != w.name || revision.Snapshot.Price != w.price {
+			t.Errorf("revision %d snapshot = %q at %v, want %q at %v",
+				revision.Number, revision.Snapshot.Name, revision.Snapshot.Price, w.name, w.price)
+		}
+		if revision.Snapshot.Version != domain.ProductSnapshotVersion {
+			t.Errorf("revision %d snapshot version = %d, want %d", revision.Number, revision.Snapshot.Version, domain.ProductSnapshotVersion)
+		}
+	}
+
+	latest, err := service.GetRevisions(context.Background(), product.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(latest) != 1 || latest[0].Number != 2 {
+		t.Errorf("GetRevisions() with a limit of 1 = %v, want revision 2", latest)
+	}
+
+	if _, err := service.GetRevision(context.Background(), product.ID, 3); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("GetRevision() unknown number error = %v, want %v", err, ErrRevisionNotFound)
+	}
+	if _, err := service.GetRevisions(context.Background(), 99, 0); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("GetRevisions() unknown product error = %v, want %v", err, ErrProductNotFound)
+	}
+}
+
+func TestDiffRevisions(t *testing.T) {
+	service, product := newRevisedProduct(t)
+	ctx := context.Background()
+
+	diff, err := service.DiffRevisions(ctx, product.ID, 1, 2)
+	if err != nil {
+		t.Fatalf("DiffRevisions() error = %v", err)
+	}
+	var fields []string
+	for _, change := range diff.Changes {
+		fields = append(fields, change.Field)
+	}
+	if want := []string{"name", "price", "options", "variants"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("DiffRevisions() fields = %v, want %v", fields, want)
+	}
+	if change := diff.Changes[0]; change.From != "Tee" || change.To != "Classic tee" {
+		t.Errorf("name change = %v to %v, want Tee to Classic tee", change.From, change.To)
+	}
+
+	same, err := service.DiffRevisions(ctx, product.ID, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(same.Changes) != 0 {
+		t.Errorf("DiffRevisions() of a revision with itself = %v, want no changes", same.Changes)
+	}
+
+	if _, err := service.DiffRevisions(ctx, product.ID, 1, 9); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("DiffRevisions() unknown revision error = %v, want %v", err, ErrRevisionNotFound)
+	}
+}
+
+func TestDiffSnapshotsIgnoresEncodingAlikes(t *testing.T) {
+	from := domain.ProductSnapshot{Name: "Tee", Tags: nil}
+	to := domain.ProductSnapshot{Version: domain.ProductSnapshotVersion, Name: "Tee", Tags: nil}
+
+	changes, err := diffSnapshots(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("diffSnapshots() = %v, want no changes", changes)
+	}
+}
+
+func TestRestoreRevision(t *testing.T) {
+	service, product := newRevisedProduct(t)
+	ctx := context.Background()
+	if _, err := service.Transition(ctx, product.ID, domain.ProductSubmit, Change{Role: domain.RoleEditor}); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := service.RestoreRevision(ctx, product.ID, 1, Change{Actor: "erin", Reason: "too expensive"})
+	if err != nil {
+		t.Fatalf("RestoreRevision() error = %v", err)
+	}
+	if restored.Name != "Tee" || restored.Price != 20 {
+		t.Errorf("restored %q at %v, want Tee at 20", restored.Name, restored.Price)
+	}
+
+	stored, err := service.GetProduct(ctx, product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != domain.ProductPendingReview {
+		t.Errorf("status after restore = %s, want %s", stored.Status, domain.ProductPendingReview)
+	}
+	var skus []string
+	for _, variant := range stored.Variants {
+		skus = append(skus, variant.SKU)
+		if variant.ID == 0 {
+			t.Errorf("variant %s has no ID", variant.SKU)
+		}
+	}
+	if want := []string{"TEE-1-S", "TEE-1-L"}; !reflect.DeepEqual(skus, want) {
+		t.Errorf("variants after restore = %v, want %v", skus, want)
+	}
+
+	revisions, err := service.GetRevisions(ctx, product.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest := revisions[0]; latest.Number != 4 || latest.Actor != "erin" || latest.Reason != "restored revision 1: too expensive" {
+		t.Errorf("latest revision = %d by %s %q, want 4 by erin %q", latest.Number, latest.Actor, latest.Reason, "restored revision 1: too expensive")
+	}
+
+	diff, err := service.DiffRevisions(ctx, product.ID, 1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, change := range diff.Changes {
+		if change.Field != "status" && change.Field != "variants" {
+			t.Errorf("restored revision differs from revision 1 in %s", change.Field)
+		}
+	}
+
+	if _, err := service.RestoreRevision(ctx, product.ID, 9, Change{}); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("RestoreRevision() unknown revision error = %v, want %v", err, ErrRevisionNotFound)
+	}
+}
@@ -1,31 +1,123 @@
 package application
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/euro1061/gohex/internal/domain"
 	"github.com/euro1061/gohex/internal/ports/repository"
+	"github.com/euro1061/gohex/internal/ports/storage"
 )
 
 var (
-	ErrInvalidProductName        = errors.New("product name cannot be empty")
-	ErrInvalidProductPrice       = errors.New("product price must be greater than 0")
-	ErrInvalidProductDescription = errors.New("product description cannot be empty")
-	ErrProductNotFound           = errors.New("product not found")
-	ErrInvalidProductID          = errors.New("invalid product ID")
+	ErrInvalidProductName        = fieldError("name", "required", "product name cannot be empty")
+	ErrInvalidProductPrice       = fieldError("price", "gt", "product price must be greater than 0")
+	ErrInvalidProductDescription = fieldError("description", "required", "product description cannot be empty")
+	ErrProductNotFound           = notFoundError("product not found")
+	ErrInvalidProductID          = validationError("invalid product ID")
+	ErrInvalidProductTag         = fieldError("tags", "length", "product tags must be between 1 and 50 characters")
+	ErrInvalidTagMatch           = fieldError("tag_match", "oneof", "tag match must be either any or all")
+	ErrInvalidPriceRange         = fieldError("min_price", "ltefield", "minimum price cannot be greater than maximum price")
+	ErrInvalidPriceBuckets       = fieldError("price_buckets", "ascending", "price bucket boundaries must be positive and ascending")
+	ErrInvalidProductSKU         = fieldError("sku", "format", "product SKU must be 2-64 letters, digits, dots, dashes or underscores")
+	ErrInvalidProductBarcode     = fieldError("barcode", "gtin", "product barcode must be a valid GTIN-8, GTIN-12, GTIN-13 or GTIN-14")
+	ErrInvalidProductSlug        = fieldError("slug", "format", "product slug must contain at least one letter or digit")
+	ErrProductSKUExists          = conflictError("product SKU already exists")
+	ErrProductSlugExists         = conflictError("product slug already exists")
+	ErrProductConflict           = conflictError("product SKU, slug or barcode already exists")
+	ErrInvalidCurrencyPrice      = fieldError("currency_prices", "format", "currency prices need a three letter currency code each and a price greater than 0")
 )
 
+const (
+	maxTagLength    = 50
+	maxPriceHistory = 200
+)
+
+// DefaultPriceBuckets are the upper bounds used for price facets when none are given.
+var DefaultPriceBuckets = []float64{100, 500, 1000, 5000}
+
+// ProductQuery describes a product listing request. CategoryID restricts the
+// listing to one category and, when IncludeDescendants is set, its subtree.
+// Only live products are listed unless IncludeUnpublished is set, which also
+// allows filtering by Statuses.
+type ProductQuery struct {
+	Tags               []string
+	TagMatch           domain.TagMatch
+	CategoryID         *uint
+	IncludeDescendants bool
+	MinPrice           *float64
+	MaxPrice           *float64
+	IncludeUnpublished bool
+	Statuses           []domain.ProductStatus
+}
+
+// Change describes who makes a change and why, for the audit records kept
+// about it. An empty Actor is recorded as SystemActor. Role is the role of
+// the actor, used where an action requires one.
+type Change struct {
+	Actor  string
+	Role   domain.Role
+	Reason string
+}
+
+// SystemActor is recorded for changes not made on behalf of a user.
+const SystemActor = "system"
+
+func (c Change) actor() string {
+	if c.Actor == "" {
+		return SystemActor
+	}
+	return c.Actor
+}
+
 type ProductService struct {
-	repo repository.ProductRepository
+	repo         repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	imageRepo    repository.ProductImageRepository
+	priceRepo    repository.PriceRepository
+	reviewRepo   repository.ReviewRepository
+	blobs        storage.BlobStore
+	uow          repository.UnitOfWork
 }
 
-func NewProductService(repo repository.ProductRepository) *ProductService {
+func NewProductService(repo repository.ProductRepository, categoryRepo repository.CategoryRepository, imageRepo repository.ProductImageRepository, priceRepo repository.PriceRepository, reviewRepo repository.ReviewRepository, blobs storage.BlobStore, uow repository.UnitOfWork) *ProductService {
 	return &ProductService{
-		repo: repo,
+		repo:         repo,
+		categoryRepo: categoryRepo,
+		imageRepo:    imageRepo,
+		priceRepo:    priceRepo,
+		reviewRepo:   reviewRepo,
+		blobs:        blobs,
+		uow:          uow,
 	}
 }
 
+// withRepos returns a copy of the service that uses repos, typically ones
+// bound to a transaction.
+func (s *ProductService) withRepos(repos repository.Repositories) *ProductService {
+	clone := *s
+	clone.repo = repos.Products
+	clone.categoryRepo = repos.Categories
+	clone.imageRepo = repos.Images
+	clone.priceRepo = repos.Prices
+	clone.reviewRepo = repos.Reviews
+	clone.uow = repos.UnitOfWork
+	return &clone
+}
+
+// transaction runs fn with a copy of the service bound to a unit of work, so
+// the writes fn makes through it are kept or discarded together.
+func (s *ProductService) transaction(ctx context.Context, fn func(tx *ProductService) error) error {
+	return s.uow.Transaction(ctx, func(repos repository.Repositories) error {
+		return fn(s.withRepos(repos))
+	})
+}
+
 func (s *ProductService) validateProduct(name, description string, price float64) error {
 	if strings.TrimSpace(name) == "" {
 		return ErrInvalidProductName
@@ -39,46 +131,300 @@ func (s *ProductService) validateProduct(name, description string, price float64
 	return nil
 }
 
-func (s *ProductService) CreateProduct(name, description string, price float64) (*domain.Product, error) {
-	// Validate input
-	if err := s.validateProduct(name, description, price); err != nil {
-		return nil, err
+// normalizeTags lowercases, trims and de-duplicates tags, keeping their order.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, ErrInvalidProductTag
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+	return normalized, nil
+}
+
+// prepareProduct validates product and normalizes its fields in place.
+func (s *ProductService) prepareProduct(product *domain.Product) error {
+	if err := s.validateProduct(product.Name, product.Description, product.Price); err != nil {
+		return err
+	}
+
+	sku, err := normalizeSKU(product.SKU)
+	if err != nil {
+		return err
+	}
+	barcode, err := normalizeBarcode(product.Barcode)
+	if err != nil {
+		return err
+	}
+	tags, err := normalizeTags(product.Tags)
+	if err != nil {
+		return err
+	}
+	options, err := normalizeOptions(product.Options)
+	if err != nil {
+		return err
+	}
+	taxClass, err := normalizeTaxClass(product.TaxClass)
+	if err != nil {
+		return err
+	}
+	currencyPrices, err := normalizeCurrencyPrices(product.CurrencyPrices)
+	if err != nil {
+		return err
 	}
 
 	// Clean input data
-	name = strings.TrimSpace(name)
-	description = strings.TrimSpace(description)
+	product.SKU = sku
+	product.Barcode = barcode
+	product.Name = strings.TrimSpace(product.Name)
+	product.Description = strings.TrimSpace(product.Description)
+	product.Tags = tags
+	product.TaxClass = taxClass
+	product.CurrencyPrices = currencyPrices
+	product.Options = options
+	return nil
+}
+
+// checkSKU fails when another product or a variant of another product already uses product.SKU.
+func (s *ProductService) checkSKU(ctx context.Context, product *domain.Product) error {
+	existing, err := s.repo.GetBySKU(ctx, product.SKU)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != product.ID {
+		return ErrProductSKUExists
+	}
+
+	variant, err := s.repo.GetVariantBySKU(ctx, product.SKU)
+	if err != nil {
+		return err
+	}
+	if variant != nil {
+		return ErrProductSKUExists
+	}
+	return nil
+}
+
+// assignSlug sets product.Slug. An explicit slug is normalized and must be
+// free; otherwise a slug is generated from the name, falling back to the SKU,
+// with a numeric suffix added until it is unique.
+func (s *ProductService) assignSlug(ctx context.Context, product *domain.Product) error {
+	if strings.TrimSpace(product.Slug) != "" {
+		slug := slugify(product.Slug)
+		if slug == "" {
+			return ErrInvalidProductSlug
+		}
+		existing, err := s.repo.GetBySlug(ctx, slug)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ID != product.ID {
+			return ErrProductSlugExists
+		}
+		product.Slug = slug
+		return nil
+	}
+
+	base := slugify(product.Name)
+	if base == "" {
+		base = slugify(product.SKU)
+	}
+	slug := base
+	for i := 2; ; i++ {
+		existing, err := s.repo.GetBySlug(ctx, slug)
+		if err != nil {
+			return err
+		}
+		if existing == nil || existing.ID == product.ID {
+			product.Slug = slug
+			return nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// attachProductDetails fills the Images and Rating fields of product.
+func (s *ProductService) attachProductDetails(ctx context.Context, product *domain.Product) error {
+	images, err := s.imageRepo.GetByProduct(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	if images == nil {
+		images = []domain.ProductImage{}
+	}
+	product.Images = images
+
+	counts, err := s.reviewRepo.GetRatingCounts(ctx, []uint{product.ID})
+	if err != nil {
+		return err
+	}
+	product.Rating = domain.NewProductRating(counts[product.ID])
+	return nil
+}
+
+// attachDetails fills the Images and Rating fields of every product in place.
+func (s *ProductService) attachDetails(ctx context.Context, products []domain.Product) error {
+	ids := make([]uint, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	images, err := s.imageRepo.GetByProducts(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		products[i].Images = images[products[i].ID]
+		if products[i].Images == nil {
+			products[i].Images = []domain.ProductImage{}
+		}
+	}
+
+	counts, err := s.reviewRepo.GetRatingCounts(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		products[i].Rating = domain.NewProductRating(counts[products[i].ID])
+	}
+	return nil
+}
+
+// saveError converts a duplicate key error from the repository into ErrProductConflict.
+func saveError(err error) error {
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return ErrProductConflict
+	}
+	return err
+}
+
+// CreateProduct validates and stores a new draft product and records its
+// first revision in the same transaction.
+func (s *ProductService) CreateProduct(ctx context.Context, input *domain.Product, change Change) (*domain.Product, error) {
+	if input == nil {
+		return nil, errors.New("product cannot be nil")
+	}
 
 	product := &domain.Product{
-		Name:        name,
-		Description: description,
-		Price:       price,
+		SKU:            input.SKU,
+		Barcode:        input.Barcode,
+		Slug:           input.Slug,
+		Name:           input.Name,
+		Description:    input.Description,
+		Price:          input.Price,
+		Tags:           input.Tags,
+		TaxClass:       input.TaxClass,
+		CurrencyPrices: input.CurrencyPrices,
+		Options:        input.Options,
+		Variants:       input.Variants,
+		Status:         domain.ProductDraft,
+		PublishAt:      input.PublishAt,
+		UnpublishAt:    input.UnpublishAt,
+	}
+	for i := range product.Variants {
+		product.Variants[i].ID = 0
+	}
+	if strings.TrimSpace(product.TaxClass) == "" {
+		product.TaxClass = domain.DefaultTaxClass
+	}
+
+	// Validate input
+	if err := s.prepareProduct(product); err != nil {
+		return nil, err
+	}
+	if err := validatePublishWindow(product); err != nil {
+		return nil, err
+	}
+	if err := s.prepareVariants(product, nil); err != nil {
+		return nil, err
+	}
+	if err := s.checkSKU(ctx, product); err != nil {
+		return nil, err
+	}
+	if err := s.checkVariantSKUs(ctx, product); err != nil {
+		return nil, err
+	}
+	if err := s.assignSlug(ctx, product); err != nil {
+		return nil, err
 	}
 
-	err := s.repo.Create(product)
+	err := s.transaction(ctx, func(tx *ProductService) error {
+		if err := tx.repo.Create(ctx, product); err != nil {
+			return saveError(err)
+		}
+		return tx.recordRevision(ctx, product, change)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return product, nil
 }
 
-func (s *ProductService) GetProduct(id uint) (*domain.Product, error) {
+func (s *ProductService) GetProduct(ctx context.Context, id uint) (*domain.Product, error) {
 	if id == 0 {
-		return nil, errors.New("invalid product ID")
+		return nil, ErrInvalidProductID
+	}
+
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+	if err := s.attachProductDetails(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (s *ProductService) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	sku, err := normalizeSKU(sku)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.repo.GetBySKU(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+	if err := s.attachProductDetails(ctx, product); err != nil {
+		return nil, err
 	}
+	return product, nil
+}
 
-	product, err := s.repo.GetByID(id)
+func (s *ProductService) GetProductBySlug(ctx context.Context, slug string) (*domain.Product, error) {
+	slug = slugify(slug)
+	if slug == "" {
+		return nil, ErrInvalidProductSlug
+	}
+
+	product, err := s.repo.GetBySlug(ctx, slug)
 	if err != nil {
 		return nil, err
 	}
 	if product == nil {
 		return nil, ErrProductNotFound
 	}
+	if err := s.attachProductDetails(ctx, product); err != nil {
+		return nil, err
+	}
 	return product, nil
 }
 
-func (s *ProductService) GetAllProducts() ([]domain.Product, error) {
-	products, err := s.repo.GetAll()
+func (s *ProductService) GetAllProducts(ctx context.Context) ([]domain.Product, error) {
+	products, err := s.repo.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -87,20 +433,219 @@ func (s *ProductService) GetAllProducts() ([]domain.Product, error) {
 	if products == nil {
 		return []domain.Product{}, nil
 	}
+	if err := s.attachDetails(ctx, products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// GetProductsByIDs returns the products with the given IDs that exist, in
+// no particular order.
+func (s *ProductService) GetProductsByIDs(ctx context.Context, ids []uint) ([]domain.Product, error) {
+	if len(ids) == 0 {
+		return []domain.Product{}, nil
+	}
+
+	products, err := s.repo.GetByIDs(ctx, uniqueIDs(ids))
+	if err != nil {
+		return nil, err
+	}
+	if products == nil {
+		return []domain.Product{}, nil
+	}
+	if err := s.attachDetails(ctx, products); err != nil {
+		return nil, err
+	}
 	return products, nil
 }
 
-func (s *ProductService) UpdateProduct(product *domain.Product) error {
+// FindProducts returns the products matching query.
+func (s *ProductService) FindProducts(ctx context.Context, query ProductQuery) ([]domain.Product, error) {
+	filter, err := s.buildFilter(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.repo.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if products == nil {
+		return []domain.Product{}, nil
+	}
+	if err := s.attachDetails(ctx, products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// GetFacets counts the products matching query per tag, per category and per
+// price bucket. priceBuckets are the ascending upper bounds of the buckets.
+func (s *ProductService) GetFacets(ctx context.Context, query ProductQuery, priceBuckets []float64) (*domain.ProductFacets, error) {
+	if len(priceBuckets) == 0 {
+		priceBuckets = DefaultPriceBuckets
+	}
+	for i, bound := range priceBuckets {
+		if bound <= 0 || (i > 0 && bound <= priceBuckets[i-1]) {
+			return nil, ErrInvalidPriceBuckets
+		}
+	}
+
+	products, err := s.FindProducts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	facets := &domain.ProductFacets{
+		Total:        len(products),
+		Tags:         []domain.TagFacet{},
+		Categories:   []domain.CategoryFacet{},
+		PriceBuckets: make([]domain.PriceBucket, 0, len(priceBuckets)+1),
+	}
+
+	// Price buckets
+	lower := 0.0
+	for i := range priceBuckets {
+		upper := priceBuckets[i]
+		facets.PriceBuckets = append(facets.PriceBuckets, domain.PriceBucket{Min: lower, Max: &upper})
+		lower = upper
+	}
+	facets.PriceBuckets = append(facets.PriceBuckets, domain.PriceBucket{Min: lower})
+
+	// Tags and price buckets
+	tagCounts := make(map[string]int)
+	productIDs := make([]uint, 0, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+		for _, tag := range product.Tags {
+			tagCounts[tag]++
+		}
+		bucket := sort.SearchFloat64s(priceBuckets, product.Price)
+		if bucket < len(priceBuckets) && priceBuckets[bucket] == product.Price {
+			bucket++
+		}
+		facets.PriceBuckets[bucket].Count++
+	}
+	for tag, count := range tagCounts {
+		facets.Tags = append(facets.Tags, domain.TagFacet{Tag: tag, Count: count})
+	}
+	sort.Slice(facets.Tags, func(i, j int) bool {
+		if facets.Tags[i].Count != facets.Tags[j].Count {
+			return facets.Tags[i].Count > facets.Tags[j].Count
+		}
+		return facets.Tags[i].Tag < facets.Tags[j].Tag
+	})
+
+	// Categories
+	productCategories, err := s.categoryRepo.GetCategoryIDsByProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	categoryCounts := make(map[uint]int)
+	for _, categoryIDs := range productCategories {
+		for _, categoryID := range categoryIDs {
+			categoryCounts[categoryID]++
+		}
+	}
+	if len(categoryCounts) > 0 {
+		categories, err := s.categoryRepo.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, category := range categories {
+			if count, ok := categoryCounts[category.ID]; ok {
+				facets.Categories = append(facets.Categories, domain.CategoryFacet{
+					CategoryID: category.ID,
+					Name:       category.Name,
+					Count:      count,
+				})
+			}
+		}
+		sort.SliceStable(facets.Categories, func(i, j int) bool {
+			return facets.Categories[i].Count > facets.Categories[j].Count
+		})
+	}
+
+	return facets, nil
+}
+
+// buildFilter validates query and resolves its category restriction into product IDs.
+func (s *ProductService) buildFilter(ctx context.Context, query ProductQuery) (domain.ProductFilter, error) {
+	tags, err := normalizeTags(query.Tags)
+	if err != nil {
+		return domain.ProductFilter{}, err
+	}
+
+	filter := domain.ProductFilter{
+		Tags:     tags,
+		TagMatch: query.TagMatch,
+		MinPrice: query.MinPrice,
+		MaxPrice: query.MaxPrice,
+	}
+	switch filter.TagMatch {
+	case "":
+		filter.TagMatch = domain.TagMatchAny
+	case domain.TagMatchAny, domain.TagMatchAll:
+	default:
+		return domain.ProductFilter{}, ErrInvalidTagMatch
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return domain.ProductFilter{}, ErrInvalidPriceRange
+	}
+	if query.IncludeUnpublished {
+		for _, status := range query.Statuses {
+			if !status.Valid() {
+				return domain.ProductFilter{}, ErrInvalidProductStatus
+			}
+		}
+		filter.Statuses = query.Statuses
+	} else {
+		now := time.Now()
+		filter.LiveAt = &now
+	}
+
+	if query.CategoryID != nil {
+		category, err := s.categoryRepo.GetByID(ctx, *query.CategoryID)
+		if err != nil {
+			return domain.ProductFilter{}, err
+		}
+		if category == nil {
+			return domain.ProductFilter{}, ErrCategoryNotFound
+		}
+
+		categoryIDs := []uint{category.ID}
+		if query.IncludeDescendants {
+			categoryIDs, err = s.categoryRepo.GetDescendantIDs(ctx, category.ID)
+			if err != nil {
+				return domain.ProductFilter{}, err
+			}
+		}
+		filter.ProductIDs, err = s.categoryRepo.GetProductIDs(ctx, categoryIDs)
+		if err != nil {
+			return domain.ProductFilter{}, err
+		}
+		if filter.ProductIDs == nil {
+			filter.ProductIDs = []uint{}
+		}
+	}
+
+	return filter, nil
+}
+
+// UpdateProduct saves product, records it as a new revision and, when its
+// price changed, records the change in the price history, all in one
+// transaction.
+func (s *ProductService) UpdateProduct(ctx context.Context, product *domain.Product, change Change) error {
 	if product == nil {
 		return errors.New("product cannot be nil")
 	}
 
-	if err := s.validateProduct(product.Name, product.Description, product.Price); err != nil {
+	if err := s.prepareProduct(product); err != nil {
 		return err
 	}
 
 	// Check if product exists
-	existing, err := s.repo.GetByID(product.ID)
+	existing, err := s.repo.GetByID(ctx, product.ID)
 	if err != nil {
 		return err
 	}
@@ -108,26 +653,153 @@ func (s *ProductService) UpdateProduct(product *domain.Product) error {
 		return ErrProductNotFound
 	}
 
-	// Clean input data
-	product.Name = strings.TrimSpace(product.Name)
-	product.Description = strings.TrimSpace(product.Description)
+	// Keep the current slug unless a new one is given
+	if strings.TrimSpace(product.Slug) == "" {
+		product.Slug = existing.Slug
+	}
+	if product.TaxClass == "" {
+		product.TaxClass = existing.TaxClass
+	}
+	// The status only changes through Transition
+	product.Status = existing.Status
+	product.ReviewNote = existing.ReviewNote
+	if err := validatePublishWindow(product); err != nil {
+		return err
+	}
+	if err := s.prepareVariants(product, existing.Variants); err != nil {
+		return err
+	}
+	if err := s.checkSKU(ctx, product); err != nil {
+		return err
+	}
+	if err := s.checkVariantSKUs(ctx, product); err != nil {
+		return err
+	}
+	if err := s.assignSlug(ctx, product); err != nil {
+		return err
+	}
+
+	err = s.transaction(ctx, func(tx *ProductService) error {
+		if err := tx.repo.Update(ctx, product); err != nil {
+			return saveError(err)
+		}
+		if err := tx.recordRevision(ctx, product, change); err != nil {
+			return err
+		}
+		return tx.recordPriceChange(ctx, product.ID, existing.Price, product.Price, change)
+	})
+	if err != nil {
+		return err
+	}
+	return s.attachProductDetails(ctx, product)
+}
+
+// ChangePrice sets the price of a product and records the change in one
+// transaction.
+func (s *ProductService) ChangePrice(ctx context.Context, id uint, price float64, change Change) (*domain.Product, error) {
+	if price <= 0 {
+		return nil, ErrInvalidProductPrice
+	}
+
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
 
-	return s.repo.Update(product)
+	oldPrice := product.Price
+	product.Price = price
+	err = s.transaction(ctx, func(tx *ProductService) error {
+		if err := tx.repo.Update(ctx, product); err != nil {
+			return saveError(err)
+		}
+		if err := tx.recordRevision(ctx, product, change); err != nil {
+			return err
+		}
+		return tx.recordPriceChange(ctx, product.ID, oldPrice, price, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
 }
 
-func (s *ProductService) DeleteProduct(id uint) error {
+// GetPriceHistory returns the most recent price changes of a product, newest first.
+func (s *ProductService) GetPriceHistory(ctx context.Context, id uint, limit int) ([]domain.PriceChange, error) {
+	if id == 0 {
+		return nil, ErrInvalidProductID
+	}
+	if limit <= 0 || limit > maxPriceHistory {
+		limit = maxPriceHistory
+	}
+
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+
+	changes, err := s.priceRepo.GetHistory(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		return []domain.PriceChange{}, nil
+	}
+	return changes, nil
+}
+
+// recordPriceChange adds a price history entry unless the price is unchanged.
+func (s *ProductService) recordPriceChange(ctx context.Context, productID uint, oldPrice, newPrice float64, change Change) error {
+	if oldPrice == newPrice {
+		return nil
+	}
+	return s.priceRepo.RecordChange(ctx, &domain.PriceChange{
+		ProductID: productID,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+		Actor:     change.actor(),
+		Reason:    strings.TrimSpace(change.Reason),
+		CreatedAt: time.Now(),
+	})
+}
+
+func (s *ProductService) DeleteProduct(ctx context.Context, id uint) error {
 	if id == 0 {
 		return ErrInvalidProductID
 	}
 
-	// Check if product exists before deleting
-	existing, err := s.repo.GetByID(id)
+	var images []domain.ProductImage
+	err := s.transaction(ctx, func(tx *ProductService) error {
+		// Check if product exists before deleting
+		existing, err := tx.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrProductNotFound
+		}
+
+		images, err = tx.imageRepo.GetByProduct(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.imageRepo.DeleteByProduct(ctx, id); err != nil {
+			return err
+		}
+		return tx.repo.Delete(ctx, id)
+	})
 	if err != nil {
 		return err
 	}
-	if existing == nil {
-		return ErrProductNotFound
-	}
 
-	return s.repo.Delete(id)
+	// The files go only once no row refers to them any more
+	for _, image := range images {
+		deleteBlobs(ctx, s.blobs, image.Key, image.ThumbnailKey)
+	}
+	return nil
 }
@@ -0,0 +1,122 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/euro1061/gohex/internal/adapters/repository/memory"
+	"github.com/euro1061/gohex/internal/adapters/storage/local"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+// memoryRepositories returns empty in-memory repositories.
+func memoryRepositories() repository.Repositories {
+	return repository.Repositories{
+		Products:   memory.NewProductRepository(),
+		Categories: memory.NewCategoryRepository(),
+		Inventory:  memory.NewInventoryRepository(),
+		Images:     memory.NewProductImageRepository(),
+		Prices:     memory.NewPriceRepository(),
+		Reviews:    memory.NewReviewRepository(),
+		Promotions: memory.NewPromotionRepository(),
+		Carts:      memory.NewCartRepository(),
+		Orders:     memory.NewOrderRepository(),
+		Payments:   memory.NewPaymentRepository(),
+		TaxRates:   memory.NewTaxRateRepository(),
+		Wishlists:  memory.NewWishlistRepository(),
+	}
+}
+
+func TestDeleteProductRemovesImages(t *testing.T) {
+	ctx := context.Background()
+	repos := memoryRepositories()
+	root := t.TempDir()
+	blobs, err := local.NewBlobStore(root, "/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	products := NewProductService(repos.Products, repos.Categories, repos.Images, repos.Prices, repos.Reviews, blobs, memory.NewUnitOfWork(repos))
+	images := NewProductImageService(repos.Images, repos.Products, blobs, 0)
+
+	product := &domain.Product{SKU: "TEE-1", Slug: "tee", Name: "Tee", Price: 20, TaxClass: domain.DefaultTaxClass}
+	if err := repos.Products.Create(ctx, product); err != nil {
+		t.Fatal(err)
+	}
+	uploaded, err := images.Upload(ctx, product.ID, bytes.NewReader(pngData(t, 4, 4)), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := products.DeleteProduct(ctx, product.ID); err != nil {
+		t.Fatalf("DeleteProduct() error = %v", err)
+	}
+
+	if stored, err := repos.Images.GetByID(ctx, uploaded.ID); err != nil || stored != nil {
+		t.Errorf("image row after DeleteProduct() = %+v, %v, want none", stored, err)
+	}
+	for _, key := range []string{uploaded.Key, uploaded.ThumbnailKey} {
+		if _, err := blobs.Get(ctx, key); err == nil {
+			t.Errorf("blob %s still stored", key)
+		}
+	}
+}
+
+func TestFindProductsByCategory(t *testing.T) {
+	ctx := context.Background()
+	repos := memoryRepositories()
+	products := NewProductService(repos.Products, repos.Categories, repos.Images, repos.Prices, repos.Reviews, nil, memory.NewUnitOfWork(repos))
+
+	parent := &domain.Category{Name: "Apparel"}
+	if err := repos.Categories.Create(ctx, parent); err != nil {
+		t.Fatal(err)
+	}
+	child := &domain.Category{Name: "Shirts", ParentID: &parent.ID}
+	if err := repos.Categories.Create(ctx, child); err != nil {
+		t.Fatal(err)
+	}
+	live := &domain.Product{SKU: "TEE-1", Slug: "tee", Name: "Tee", Price: 20, Status: domain.ProductPublished}
+	draft := &domain.Product{SKU: "TEE-2", Slug: "tee-2", Name: "Tee 2", Price: 25, Status: domain.ProductDraft}
+	for _, product := range []*domain.Product{live, draft} {
+		if err := repos.Products.Create(ctx, product); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Categories.SetProductCategories(ctx, product.ID, []uint{child.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query ProductQuery
+		want  []uint
+	}{
+		{"live only", ProductQuery{CategoryID: &child.ID}, []uint{live.ID}},
+		{"unpublished for editors", ProductQuery{CategoryID: &child.ID, IncludeUnpublished: true}, []uint{live.ID, draft.ID}},
+		{"parent without descendants", ProductQuery{CategoryID: &parent.ID}, nil},
+		{"parent with descendants", ProductQuery{CategoryID: &parent.ID, IncludeDescendants: true}, []uint{live.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := products.FindProducts(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("FindProducts() error = %v", err)
+			}
+			var ids []uint
+			for _, product := range found {
+				ids = append(ids, product.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.want) {
+				t.Errorf("FindProducts() ids = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	missing := uint(99)
+	if _, err := products.FindProducts(ctx, ProductQuery{CategoryID: &missing}); !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("FindProducts() unknown category error = %v, want %v", err, ErrCategoryNotFound)
+	}
+}
@@ -0,0 +1,278 @@
+package application
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+var (
+	ErrInvalidOptionName        = fieldError("options", "length", "product option name must be between 1 and 50 characters")
+	ErrDuplicateOptionName      = fieldError("options", "unique", "product option names must be unique")
+	ErrInvalidOptionValues      = fieldError("options", "unique", "product option values must be unique, non-empty and at most 50 characters")
+	ErrTooManyOptions           = fieldError("options", "max", "a product can have at most 3 options")
+	ErrTooManyVariants          = fieldError("variants", "max", "a product can have at most 100 variants")
+	ErrInvalidVariantAttributes = fieldError("variants", "attributes", "variant attributes must set one allowed value for each product option")
+	ErrDuplicateVariant         = fieldError("variants", "unique", "variants cannot repeat the same option combination")
+	ErrInvalidVariantPrice      = fieldError("variants", "gt", "variant price must be greater than 0")
+	ErrVariantSKUExists         = conflictError("variant SKU already exists")
+	ErrVariantNotFound          = notFoundError("variant does not belong to this product")
+)
+
+const (
+	maxOptions        = 3
+	maxVariants       = 100
+	maxOptionValueLen = 50
+)
+
+// normalizeOptions trims option names and values and checks that they are unique.
+func normalizeOptions(options []domain.ProductOption) ([]domain.ProductOption, error) {
+	if len(options) > maxOptions {
+		return nil, ErrTooManyOptions
+	}
+
+	normalized := make([]domain.ProductOption, 0, len(options))
+	names := make(map[string]struct{}, len(options))
+	for i, option := range options {
+		name := strings.TrimSpace(option.Name)
+		if name == "" || utf8.RuneCountInString(name) > maxOptionValueLen {
+			return nil, ErrInvalidOptionName
+		}
+		key := strings.ToLower(name)
+		if _, ok := names[key]; ok {
+			return nil, ErrDuplicateOptionName
+		}
+		names[key] = struct{}{}
+
+		if len(option.Values) == 0 {
+			return nil, ErrInvalidOptionValues
+		}
+		values := make([]string, 0, len(option.Values))
+		seen := make(map[string]struct{}, len(option.Values))
+		for _, value := range option.Values {
+			value = strings.TrimSpace(value)
+			if value == "" || utf8.RuneCountInString(value) > maxOptionValueLen {
+				return nil, ErrInvalidOptionValues
+			}
+			if _, ok := seen[strings.ToLower(value)]; ok {
+				return nil, ErrInvalidOptionValues
+			}
+			seen[strings.ToLower(value)] = struct{}{}
+			values = append(values, value)
+		}
+
+		normalized = append(normalized, domain.ProductOption{
+			Name:     name,
+			Values:   values,
+			Position: i,
+		})
+	}
+	return normalized, nil
+}
+
+// combinationKey identifies the option values of a variant, in option order.
+// It returns false when the attributes do not match the options exactly.
+func combinationKey(options []domain.ProductOption, attributes map[string]string) (string, bool) {
+	if len(attributes) != len(options) {
+		return "", false
+	}
+
+	parts := make([]string, 0, len(options))
+	for _, option := range options {
+		value, ok := attributes[option.Name]
+		if !ok {
+			return "", false
+		}
+		allowed := false
+		for _, candidate := range option.Values {
+			if candidate == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return "", false
+		}
+		parts = append(parts, value)
+	}
+	return strings.Join(parts, "\x00"), true
+}
+
+// prepareVariants validates product.Variants against product.Options, which
+// must already be normalized. When the product has options but no variants,
+// every combination is generated. existing holds the variants currently
+// stored for the product; variants with an ID must be among them.
+func (s *ProductService) prepareVariants(product *domain.Product, existing []domain.ProductVariant) error {
+	if len(product.Options) > 0 && len(product.Variants) == 0 {
+		return s.generateVariants(product)
+	}
+	if len(product.Variants) > maxVariants {
+		return ErrTooManyVariants
+	}
+
+	known := make(map[uint]struct{}, len(existing))
+	for _, variant := range existing {
+		known[variant.ID] = struct{}{}
+	}
+
+	combinations := make(map[string]struct{}, len(product.Variants))
+	skus := make(map[string]struct{}, len(product.Variants))
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		if variant.ID != 0 {
+			if _, ok := known[variant.ID]; !ok {
+				return ErrVariantNotFound
+			}
+		}
+
+		attributes := make(map[string]string, len(variant.Attributes))
+		for name, value := range variant.Attributes {
+			attributes[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+		key, ok := combinationKey(product.Options, attributes)
+		if !ok {
+			return ErrInvalidVariantAttributes
+		}
+		if _, ok := combinations[key]; ok {
+			return ErrDuplicateVariant
+		}
+		combinations[key] = struct{}{}
+		variant.Attributes = attributes
+
+		sku, err := normalizeSKU(variant.SKU)
+		if err != nil {
+			return err
+		}
+		if _, ok := skus[sku]; ok || sku == product.SKU {
+			return ErrVariantSKUExists
+		}
+		skus[sku] = struct{}{}
+		variant.SKU = sku
+
+		if variant.Price <= 0 {
+			return ErrInvalidVariantPrice
+		}
+	}
+	return nil
+}
+
+// generateVariants adds a variant for every option combination the product
+// does not have yet. New variants take the product price and a SKU derived
+// from the product SKU and the option values.
+func (s *ProductService) generateVariants(product *domain.Product) error {
+	total := 1
+	for _, option := range product.Options {
+		total *= len(option.Values)
+		if total > maxVariants {
+			return ErrTooManyVariants
+		}
+	}
+	if len(product.Options) == 0 {
+		return nil
+	}
+
+	present := make(map[string]struct{}, len(product.Variants))
+	for _, variant := range product.Variants {
+		if key, ok := combinationKey(product.Options, variant.Attributes); ok {
+			present[key] = struct{}{}
+		}
+	}
+
+	// Walk the options matrix like an odometer, last option changing fastest
+	indexes := make([]int, len(product.Options))
+	for n := 0; n < total; n++ {
+		attributes := make(map[string]string, len(product.Options))
+		suffix := make([]string, 0, len(product.Options))
+		for i, option := range product.Options {
+			value := option.Values[indexes[i]]
+			attributes[option.Name] = value
+			suffix = append(suffix, skuPart(value, indexes[i]+1))
+		}
+
+		if key, _ := combinationKey(product.Options, attributes); !hasKey(present, key) {
+			product.Variants = append(product.Variants, domain.ProductVariant{
+				SKU:        variantSKU(product.SKU, suffix),
+				Price:      product.Price,
+				Attributes: attributes,
+			})
+		}
+
+		for i := len(indexes) - 1; i >= 0; i-- {
+			indexes[i]++
+			if indexes[i] < len(product.Options[i].Values) {
+				break
+			}
+			indexes[i] = 0
+		}
+	}
+	return nil
+}
+
+// GenerateVariants adds the missing option combinations to an existing product.
+func (s *ProductService) GenerateVariants(ctx context.Context, id uint) (*domain.Product, error) {
+	product, err := s.GetProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.generateVariants(product); err != nil {
+		return nil, err
+	}
+	if err := s.UpdateProduct(ctx, product, Change{}); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// checkVariantSKUs fails when a variant SKU is used by another product or its variants.
+func (s *ProductService) checkVariantSKUs(ctx context.Context, product *domain.Product) error {
+	for _, variant := range product.Variants {
+		existing, err := s.repo.GetVariantBySKU(ctx, variant.SKU)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ProductID != product.ID {
+			return ErrVariantSKUExists
+		}
+
+		owner, err := s.repo.GetBySKU(ctx, variant.SKU)
+		if err != nil {
+			return err
+		}
+		if owner != nil && owner.ID != product.ID {
+			return ErrVariantSKUExists
+		}
+	}
+	return nil
+}
+
+// skuPart turns an option value into an uppercase ASCII SKU segment, falling
+// back to the value's position for values without ASCII letters or digits.
+func skuPart(value string, position int) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(value) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return strconv.Itoa(position)
+	}
+	return b.String()
+}
+
+func variantSKU(productSKU string, parts []string) string {
+	sku := productSKU + "-" + strings.Join(parts, "-")
+	if len(sku) > 64 {
+		sku = sku[:64]
+	}
+	return strings.TrimRight(sku, "-._")
+}
+
+func hasKey(set map[string]struct{}, key string) bool {
+	_, ok := set[key]
+	return ok
+}
@@ -0,0 +1,203 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+var (
+	ErrPromotionNotFound       = notFoundError("promotion not found")
+	ErrInvalidPromotionName    = fieldError("name", "required", "promotion name cannot be empty")
+	ErrInvalidPromotionType    = fieldError("type", "oneof", "promotion type must be percentage, fixed or buy_x_get_y")
+	ErrInvalidPromotionValue   = fieldError("value", "range", "percentage discounts must be between 0 and 100 and fixed discounts greater than 0")
+	ErrInvalidPromotionWindow  = fieldError("ends_at", "gtfield", "promotion must end after it starts")
+	ErrInvalidPromotionLimit   = fieldError("usage_limit", "min", "promotion usage limits must be at least 1")
+	ErrInvalidBuyXGetY         = fieldError("buy_quantity", "min", "buy_x_get_y promotions need buy and get quantities of at least 1")
+	ErrInvalidCouponCode       = fieldError("code", "format", "coupon code must be 3 to 32 letters, digits, dashes or underscores")
+	ErrCouponCodeExists        = conflictError("coupon code already exists")
+	ErrInvalidPromotionTargets = fieldError("product_ids", "exists", "promotion targets unknown products or categories")
+)
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+type PromotionService struct {
+	repo         repository.PromotionRepository
+	productRepo  repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+}
+
+func NewPromotionService(repo repository.PromotionRepository, productRepo repository.ProductRepository, categoryRepo repository.CategoryRepository) *PromotionService {
+	return &PromotionService{
+		repo:         repo,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// normalizeCouponCode trims and uppercases a coupon code.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// preparePromotion validates promotion and normalizes its fields in place.
+func (s *PromotionService) preparePromotion(ctx context.Context, promotion *domain.Promotion) error {
+	promotion.Name = strings.TrimSpace(promotion.Name)
+	if promotion.Name == "" {
+		return ErrInvalidPromotionName
+	}
+
+	switch promotion.Type {
+	case domain.PromotionPercentage:
+		if promotion.Value <= 0 || promotion.Value > 100 {
+			return ErrInvalidPromotionValue
+		}
+	case domain.PromotionFixed:
+		if promotion.Value <= 0 {
+			return ErrInvalidPromotionValue
+		}
+	case domain.PromotionBuyXGetY:
+		if promotion.BuyQuantity < 1 || promotion.GetQuantity < 1 {
+			return ErrInvalidBuyXGetY
+		}
+		// The free units are free unless a smaller discount is given
+		if promotion.Value == 0 {
+			promotion.Value = 100
+		}
+		if promotion.Value < 0 || promotion.Value > 100 {
+			return ErrInvalidPromotionValue
+		}
+	default:
+		return ErrInvalidPromotionType
+	}
+	if promotion.Type != domain.PromotionBuyXGetY {
+		promotion.BuyQuantity, promotion.GetQuantity = 0, 0
+	}
+
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt) {
+		return ErrInvalidPromotionWindow
+	}
+	if (promotion.UsageLimit != nil && *promotion.UsageLimit < 1) ||
+		(promotion.PerUserLimit != nil && *promotion.PerUserLimit < 1) {
+		return ErrInvalidPromotionLimit
+	}
+
+	if promotion.Code != nil {
+		code := normalizeCouponCode(*promotion.Code)
+		if code == "" {
+			promotion.Code = nil
+		} else if !couponCodePattern.MatchString(code) {
+			return ErrInvalidCouponCode
+		} else {
+			promotion.Code = &code
+		}
+	}
+
+	return s.checkTargets(ctx, promotion)
+}
+
+// checkTargets makes sure the targeted products and categories exist.
+func (s *PromotionService) checkTargets(ctx context.Context, promotion *domain.Promotion) error {
+	if len(promotion.ProductIDs) > 0 {
+		products, err := s.productRepo.GetByIDs(ctx, promotion.ProductIDs)
+		if err != nil {
+			return err
+		}
+		if len(products) != len(uniqueIDs(promotion.ProductIDs)) {
+			return ErrInvalidPromotionTargets
+		}
+	}
+	for _, id := range promotion.CategoryIDs {
+		category, err := s.categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if category == nil {
+			return ErrInvalidPromotionTargets
+		}
+	}
+	return nil
+}
+
+func (s *PromotionService) GetPromotions(ctx context.Context) ([]domain.Promotion, error) {
+	promotions, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if promotions == nil {
+		return []domain.Promotion{}, nil
+	}
+	return promotions, nil
+}
+
+func (s *PromotionService) GetPromotion(ctx context.Context, id uint) (*domain.Promotion, error) {
+	promotion, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if promotion == nil {
+		return nil, ErrPromotionNotFound
+	}
+	return promotion, nil
+}
+
+func (s *PromotionService) CreatePromotion(ctx context.Context, promotion *domain.Promotion) (*domain.Promotion, error) {
+	promotion.ID = 0
+	promotion.UsageCount = 0
+	if err := s.preparePromotion(ctx, promotion); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, promotion); err != nil {
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			return nil, ErrCouponCodeExists
+		}
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// UpdatePromotion replaces a promotion. Its usage count is kept.
+func (s *PromotionService) UpdatePromotion(ctx context.Context, promotion *domain.Promotion) (*domain.Promotion, error) {
+	existing, err := s.GetPromotion(ctx, promotion.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.preparePromotion(ctx, promotion); err != nil {
+		return nil, err
+	}
+
+	promotion.UsageCount = existing.UsageCount
+	promotion.CreatedAt = existing.CreatedAt
+	if err := s.repo.Update(ctx, promotion); err != nil {
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			return nil, ErrCouponCodeExists
+		}
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (s *PromotionService) DeletePromotion(ctx context.Context, id uint) error {
+	if _, err := s.GetPromotion(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// uniqueIDs returns ids without duplicates, keeping their order.
+func uniqueIDs(ids []uint) []uint {
+	unique := make([]uint, 0, len(ids))
+	seen := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,188 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+var (
+	ErrReviewNotFound       = notFoundError("review not found")
+	ErrReviewExists         = conflictError("you have already reviewed this product")
+	ErrInvalidReviewRating  = fieldError("rating", "range", "review rating must be between 1 and 5")
+	ErrInvalidReviewTitle   = fieldError("title", "max", "review title must be at most 200 characters")
+	ErrInvalidReviewBody    = fieldError("body", "max", "review body must be at most 5000 characters")
+	ErrInvalidReviewStatus  = fieldError("status", "oneof", "review status must be pending, approved or rejected")
+	ErrReviewForbidden      = forbiddenError("only the author can change this review")
+	ErrProductNotReviewable = conflictError("only published products can be reviewed")
+)
+
+const (
+	maxReviewTitleLength = 200
+	maxReviewBodyLength  = 5000
+)
+
+// ReviewInput is the part of a review its author writes.
+type ReviewInput struct {
+	Rating int
+	Title  string
+	Body   string
+}
+
+type ReviewService struct {
+	repo        repository.ReviewRepository
+	productRepo repository.ProductRepository
+}
+
+func NewReviewService(repo repository.ReviewRepository, productRepo repository.ProductRepository) *ReviewService {
+	return &ReviewService{
+		repo:        repo,
+		productRepo: productRepo,
+	}
+}
+
+func validateReview(input *ReviewInput) error {
+	input.Title = strings.TrimSpace(input.Title)
+	input.Body = strings.TrimSpace(input.Body)
+	if input.Rating < 1 || input.Rating > 5 {
+		return ErrInvalidReviewRating
+	}
+	if utf8.RuneCountInString(input.Title) > maxReviewTitleLength {
+		return ErrInvalidReviewTitle
+	}
+	if utf8.RuneCountInString(input.Body) > maxReviewBodyLength {
+		return ErrInvalidReviewBody
+	}
+	return nil
+}
+
+// GetReviews returns the reviews of a product in status, newest first.
+// An empty status returns reviews in every status.
+func (s *ReviewService) GetReviews(ctx context.Context, productID uint, status domain.ReviewStatus) ([]domain.Review, error) {
+	if status != "" && !status.Valid() {
+		return nil, ErrInvalidReviewStatus
+	}
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+
+	reviews, err := s.repo.GetByProduct(ctx, productID, status)
+	if err != nil {
+		return nil, err
+	}
+	if reviews == nil {
+		return []domain.Review{}, nil
+	}
+	return reviews, nil
+}
+
+func (s *ReviewService) GetReview(ctx context.Context, id uint) (*domain.Review, error) {
+	review, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrReviewNotFound
+	}
+	return review, nil
+}
+
+// CreateReview adds the review of author for a live product. It waits for
+// moderation before it is shown or counted in the product rating.
+func (s *ReviewService) CreateReview(ctx context.Context, productID uint, author *domain.User, input ReviewInput) (*domain.Review, error) {
+	if err := validateReview(&input); err != nil {
+		return nil, err
+	}
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+	if !product.IsLive(time.Now()) {
+		return nil, ErrProductNotReviewable
+	}
+
+	review := &domain.Review{
+		ProductID: productID,
+		UserID:    author.ID,
+		Author:    author.Name,
+		Rating:    input.Rating,
+		Title:     input.Title,
+		Body:      input.Body,
+		Status:    domain.ReviewPending,
+	}
+	if err := s.repo.Create(ctx, review); err != nil {
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			return nil, ErrReviewExists
+		}
+		return nil, err
+	}
+	return review, nil
+}
+
+// UpdateReview changes a review on behalf of its author. The edited review
+// goes back to moderation.
+func (s *ReviewService) UpdateReview(ctx context.Context, id uint, author *domain.User, input ReviewInput) (*domain.Review, error) {
+	if err := validateReview(&input); err != nil {
+		return nil, err
+	}
+	review, err := s.GetReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != author.ID {
+		return nil, ErrReviewForbidden
+	}
+
+	review.Rating = input.Rating
+	review.Title = input.Title
+	review.Body = input.Body
+	review.Status = domain.ReviewPending
+	review.ModerationNote = ""
+	if err := s.repo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// DeleteReview removes a review on behalf of its author or an admin.
+func (s *ReviewService) DeleteReview(ctx context.Context, id uint, user *domain.User) error {
+	review, err := s.GetReview(ctx, id)
+	if err != nil {
+		return err
+	}
+	if review.UserID != user.ID && !user.HasRole(domain.RoleAdmin) {
+		return ErrReviewForbidden
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Moderate sets the moderation status of a review, with an optional note
+// for the author.
+func (s *ReviewService) Moderate(ctx context.Context, id uint, status domain.ReviewStatus, note string) (*domain.Review, error) {
+	if !status.Valid() {
+		return nil, ErrInvalidReviewStatus
+	}
+	review, err := s.GetReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Status = status
+	review.ModerationNote = strings.TrimSpace(note)
+	if err := s.repo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
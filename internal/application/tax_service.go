@@ -0,0 +1,238 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+var (
+	ErrTaxRateNotFound    = notFoundError("tax rate not found")
+	ErrTaxRateExists      = conflictError("a tax rate already exists for this jurisdiction and tax class")
+	ErrInvalidTaxCountry  = fieldError("country", "iso3166_1_alpha2", "country must be a two letter ISO 3166-1 code")
+	ErrInvalidTaxRegion   = fieldError("region", "format", "region must be at most 10 letters, digits or dashes")
+	ErrInvalidTaxClass    = fieldError("tax_class", "format", "tax class must be 1 to 32 lowercase letters, digits, dashes or underscores")
+	ErrInvalidTaxRate     = fieldError("rate", "range", "tax rate must be between 0 and 100")
+	ErrInvalidTaxRateName = fieldError("name", "length", "tax rate name must be between 1 and 100 characters")
+)
+
+const maxTaxRateNameLength = 100
+
+var (
+	taxCountryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+	taxRegionPattern  = regexp.MustCompile(`^[A-Z0-9-]{1,10}$`)
+	taxClassPattern   = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+)
+
+// TaxSettings describe how catalog prices relate to tax.
+type TaxSettings struct {
+	// PricesIncludeTax means catalog prices already contain tax, which is
+	// then extracted rather than added.
+	PricesIncludeTax bool
+	Rounding         domain.TaxRounding
+	// DefaultCountry taxes quotes that do not give an address. Without it
+	// such quotes are not taxed.
+	DefaultCountry string
+}
+
+// TaxService manages the tax rate table and calculates tax from it. It is
+// the built-in tax.TaxCalculator.
+type TaxService struct {
+	repo     repository.TaxRateRepository
+	settings TaxSettings
+}
+
+func NewTaxService(repo repository.TaxRateRepository, settings TaxSettings) *TaxService {
+	if !settings.Rounding.Valid() {
+		settings.Rounding = domain.TaxRoundPerLine
+	}
+	settings.DefaultCountry = strings.ToUpper(strings.TrimSpace(settings.DefaultCountry))
+
+	return &TaxService{
+		repo:     repo,
+		settings: settings,
+	}
+}
+
+// normalizeTaxClass trims and lowercases a tax class. An empty class is
+// returned as is for the caller to default.
+func normalizeTaxClass(class string) (string, error) {
+	class = strings.ToLower(strings.TrimSpace(class))
+	if class != "" && !taxClassPattern.MatchString(class) {
+		return "", ErrInvalidTaxClass
+	}
+	return class, nil
+}
+
+// normalizeTaxAddress trims and uppercases the codes of address.
+func normalizeTaxAddress(address domain.TaxAddress) (domain.TaxAddress, error) {
+	address.Country = strings.ToUpper(strings.TrimSpace(address.Country))
+	address.Region = strings.ToUpper(strings.TrimSpace(address.Region))
+	if address.Country != "" && !taxCountryPattern.MatchString(address.Country) {
+		return domain.TaxAddress{}, ErrInvalidTaxCountry
+	}
+	if address.Region != "" && !taxRegionPattern.MatchString(address.Region) {
+		return domain.TaxAddress{}, ErrInvalidTaxRegion
+	}
+	return address, nil
+}
+
+// prepareRate validates rate and normalizes its fields in place.
+func prepareRate(rate *domain.TaxRate) error {
+	address, err := normalizeTaxAddress(domain.TaxAddress{Country: rate.Country, Region: rate.Region})
+	if err != nil {
+		return err
+	}
+	if address.Country == "" {
+		return ErrInvalidTaxCountry
+	}
+	class, err := normalizeTaxClass(rate.TaxClass)
+	if err != nil {
+		return err
+	}
+	if class == "" {
+		class = domain.DefaultTaxClass
+	}
+	rate.Name = strings.TrimSpace(rate.Name)
+	if rate.Name == "" || utf8.RuneCountInString(rate.Name) > maxTaxRateNameLength {
+		return ErrInvalidTaxRateName
+	}
+	if rate.Rate < 0 || rate.Rate > 100 {
+		return ErrInvalidTaxRate
+	}
+
+	rate.Country = address.Country
+	rate.Region = address.Region
+	rate.TaxClass = class
+	return nil
+}
+
+func (s *TaxService) GetRates(ctx context.Context) ([]domain.TaxRate, error) {
+	rates, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rates == nil {
+		return []domain.TaxRate{}, nil
+	}
+	return rates, nil
+}
+
+func (s *TaxService) GetRate(ctx context.Context, id uint) (*domain.TaxRate, error) {
+	rate, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rate == nil {
+		return nil, ErrTaxRateNotFound
+	}
+	return rate, nil
+}
+
+func (s *TaxService) CreateRate(ctx context.Context, rate *domain.TaxRate) (*domain.TaxRate, error) {
+	rate.ID = 0
+	if err := prepareRate(rate); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, rate); err != nil {
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			return nil, ErrTaxRateExists
+		}
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *TaxService) UpdateRate(ctx context.Context, rate *domain.TaxRate) (*domain.TaxRate, error) {
+	existing, err := s.GetRate(ctx, rate.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareRate(rate); err != nil {
+		return nil, err
+	}
+
+	rate.CreatedAt = existing.CreatedAt
+	if err := s.repo.Update(ctx, rate); err != nil {
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			return nil, ErrTaxRateExists
+		}
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *TaxService) DeleteRate(ctx context.Context, id uint) error {
+	if _, err := s.GetRate(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Calculate taxes lines at the rates of address, falling back to the default
+// country when address has none. A region rate takes precedence over the
+// country rate of the same tax class; a tax class without a rate is not
+// taxed. Line amounts include tax when catalog prices do.
+func (s *TaxService) Calculate(ctx context.Context, address domain.TaxAddress, lines []domain.TaxableLine) (*domain.TaxQuote, error) {
+	address, err := normalizeTaxAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if address.Country == "" {
+		address = domain.TaxAddress{Country: s.settings.DefaultCountry}
+	}
+
+	quote := &domain.TaxQuote{
+		Address:   address,
+		Inclusive: s.settings.PricesIncludeTax,
+		Lines:     make([]domain.LineTax, len(lines)),
+	}
+	if address.Country == "" {
+		return quote, nil
+	}
+
+	rates, err := s.repo.GetByCountry(ctx, address.Country)
+	if err != nil {
+		return nil, err
+	}
+	countryRates := make(map[string]float64)
+	regionRates := make(map[string]float64)
+	for _, rate := range rates {
+		switch rate.Region {
+		case "":
+			countryRates[rate.TaxClass] = rate.Rate
+		case address.Region:
+			regionRates[rate.TaxClass] = rate.Rate
+		}
+	}
+
+	var exact float64
+	for i, line := range lines {
+		class := line.TaxClass
+		if class == "" {
+			class = domain.DefaultTaxClass
+		}
+		rate, ok := regionRates[class]
+		if !ok {
+			rate = countryRates[class]
+		}
+
+		tax := line.Amount * rate / 100
+		if quote.Inclusive {
+			tax = line.Amount - line.Amount/(1+rate/100)
+		}
+		exact += tax
+		quote.Lines[i] = domain.LineTax{Rate: rate, Tax: domain.RoundMoney(tax)}
+		quote.Tax = domain.RoundMoney(quote.Tax + quote.Lines[i].Tax)
+	}
+	if s.settings.Rounding == domain.TaxRoundPerTotal {
+		quote.Tax = domain.RoundMoney(exact)
+	}
+	return quote, nil
+}
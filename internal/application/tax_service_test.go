@@ -0,0 +1,220 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/euro1061/gohex/internal/adapters/repository/memory"
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+// newTaxService returns a tax service with a 7% Thai VAT, a US sales tax
+// of 0% federally and 7.25% in California, and a 0% Thai rate for books.
+func newTaxService(t *testing.T, settings TaxSettings) *TaxService {
+	t.Helper()
+	taxes := NewTaxService(memory.NewTaxRateRepository(), settings)
+	for _, rate := range []*domain.TaxRate{
+		{Country: "th", Name: "VAT", Rate: 7},
+		{Country: "TH", TaxClass: "books", Name: "VAT exempt", Rate: 0},
+		{Country: "US", Name: "No federal sales tax", Rate: 0},
+		{Country: "US", Region: "ca", Name: "California sales tax", Rate: 7.25},
+	} {
+		if _, err := taxes.CreateRate(context.Background(), rate); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return taxes
+}
+
+func TestCalculateRates(t *testing.T) {
+	tests := []struct {
+		name      string
+		settings  TaxSettings
+		address   domain.TaxAddress
+		lines     []domain.TaxableLine
+		wantRates []float64
+		wantTax   float64
+	}{
+		{
+			name:      "country rate",
+			address:   domain.TaxAddress{Country: "TH"},
+			lines:     []domain.TaxableLine{{Amount: 100}},
+			wantRates: []float64{7},
+			wantTax:   7,
+		},
+		{
+			name:      "address is normalized",
+			address:   domain.TaxAddress{Country: " th "},
+			lines:     []domain.TaxableLine{{Amount: 100}},
+			wantRates: []float64{7},
+			wantTax:   7,
+		},
+		{
+			name:      "tax class rate",
+			address:   domain.TaxAddress{Country: "TH"},
+			lines:     []domain.TaxableLine{{Amount: 100}, {TaxClass: "books", Amount: 100}},
+			wantRates: []float64{7, 0},
+			wantTax:   7,
+		},
+		{
+			name:      "tax class without a rate",
+			address:   domain.TaxAddress{Country: "TH"},
+			lines:     []domain.TaxableLine{{TaxClass: "food", Amount: 100}},
+			wantRates: []float64{0},
+			wantTax:   0,
+		},
+		{
+			name:      "region rate takes precedence",
+			address:   domain.TaxAddress{Country: "US", Region: "CA"},
+			lines:     []domain.TaxableLine{{Amount: 100}},
+			wantRates: []float64{7.25},
+			wantTax:   7.25,
+		},
+		{
+			name:      "region without a rate falls back to the country",
+			address:   domain.TaxAddress{Country: "US", Region: "OR"},
+			lines:     []domain.TaxableLine{{Amount: 100}},
+			wantRates: []float64{0},
+			wantTax:   0,
+		},
+		{
+			name:      "country without rates",
+			address:   domain.TaxAddress{Country: "DE"},
+			lines:     []domain.TaxableLine{{Amount: 100}},
+			wantRates: []float64{0},
+			wantTax:   0,
+		},
+		{
+			name:      "no address and no default country",
+			lines:     []domain.TaxableLine{{Amount: 100}},
+			wantRates: []float64{0},
+			wantTax:   0,
+		},
+		{
+			name:      "default country",
+			settings:  TaxSettings{DefaultCountry: "th"},
+			lines:     []domain.TaxableLine{{Amount: 100}},
+			wantRates: []float64{7},
+			wantTax:   7,
+		},
+		{
+			name:      "inclusive prices extract tax",
+			settings:  TaxSettings{PricesIncludeTax: true},
+			address:   domain.TaxAddress{Country: "TH"},
+			lines:     []domain.TaxableLine{{Amount: 107}},
+			wantRates: []float64{7},
+			wantTax:   7,
+		},
+		{
+			name:      "inclusive prices in a region",
+			settings:  TaxSettings{PricesIncludeTax: true},
+			address:   domain.TaxAddress{Country: "US", Region: "CA"},
+			lines:     []domain.TaxableLine{{Amount: 10.73}},
+			wantRates: []float64{7.25},
+			wantTax:   0.73,
+		},
+		{
+			name:      "rounding per line",
+			address:   domain.TaxAddress{Country: "TH"},
+			lines:     []domain.TaxableLine{{Amount: 0.25}, {Amount: 0.25}, {Amount: 0.25}},
+			wantRates: []float64{7, 7, 7},
+			wantTax:   0.06,
+		},
+		{
+			name:      "rounding per total",
+			settings:  TaxSettings{Rounding: domain.TaxRoundPerTotal},
+			address:   domain.TaxAddress{Country: "TH"},
+			lines:     []domain.TaxableLine{{Amount: 0.25}, {Amount: 0.25}, {Amount: 0.25}},
+			wantRates: []float64{7, 7, 7},
+			wantTax:   0.05,
+		},
+		{
+			name:      "inclusive rounding per total",
+			settings:  TaxSettings{PricesIncludeTax: true, Rounding: domain.TaxRoundPerTotal},
+			address:   domain.TaxAddress{Country: "TH"},
+			lines:     []domain.TaxableLine{{Amount: 0.27}, {Amount: 0.27}, {Amount: 0.27}},
+			wantRates: []float64{7, 7, 7},
+			wantTax:   0.05,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taxes := newTaxService(t, tt.settings)
+			quote, err := taxes.Calculate(context.Background(), tt.address, tt.lines)
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if quote.Tax != tt.wantTax {
+				t.Errorf("Calculate() tax = %v, want %v", quote.Tax, tt.wantTax)
+			}
+			if quote.Inclusive != tt.settings.PricesIncludeTax {
+				t.Errorf("Calculate() inclusive = %v, want %v", quote.Inclusive, tt.settings.PricesIncludeTax)
+			}
+			for i, line := range quote.Lines {
+				if line.Rate != tt.wantRates[i] {
+					t.Errorf("line %d rate = %v, want %v", i, line.Rate, tt.wantRates[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateRejectsInvalidAddress(t *testing.T) {
+	taxes := newTaxService(t, TaxSettings{})
+	for _, address := range []domain.TaxAddress{{Country: "THA"}, {Country: "TH", Region: "BANGKOK NOI"}} {
+		if _, err := taxes.Calculate(context.Background(), address, []domain.TaxableLine{{Amount: 1}}); err == nil {
+			t.Errorf("Calculate(%+v) succeeded, want an error", address)
+		}
+	}
+}
+
+func TestCreateRateValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    domain.TaxRate
+		wantErr error
+	}{
+		{"missing country", domain.TaxRate{Name: "VAT", Rate: 7}, ErrInvalidTaxCountry},
+		{"bad tax class", domain.TaxRate{Country: "DE", TaxClass: "Food & Drink", Name: "VAT", Rate: 7}, ErrInvalidTaxClass},
+		{"missing name", domain.TaxRate{Country: "DE", Rate: 7}, ErrInvalidTaxRateName},
+		{"negative rate", domain.TaxRate{Country: "DE", Name: "VAT", Rate: -1}, ErrInvalidTaxRate},
+		{"rate above 100", domain.TaxRate{Country: "DE", Name: "VAT", Rate: 101}, ErrInvalidTaxRate},
+		{"same jurisdiction and class", domain.TaxRate{Country: "TH", Name: "VAT again", Rate: 10}, ErrTaxRateExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taxes := newTaxService(t, TaxSettings{})
+			if _, err := taxes.CreateRate(context.Background(), &tt.rate); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateRate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPriceItemsTax(t *testing.T) {
+	tests := []struct {
+		name      string
+		inclusive bool
+		wantTax   float64
+		wantTotal float64
+	}{
+		{"exclusive prices add tax", false, 1.4, 21.4},
+		{"inclusive prices keep the total", true, 1.31, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPricingFixture(t)
+			f.pricing.taxes = newTaxService(t, TaxSettings{PricesIncludeTax: tt.inclusive})
+
+			quote, err := f.pricing.PriceProduct(context.Background(), f.tee.ID, nil, 2, "", 0, domain.TaxAddress{Country: "TH"}, "")
+			if err != nil {
+				t.Fatalf("PriceProduct() error = %v", err)
+			}
+			if quote.Tax != tt.wantTax || quote.Total != tt.wantTotal || quote.PricesIncludeTax != tt.inclusive {
+				t.Errorf("PriceProduct() tax = %v, total = %v, inclusive = %v, want %v, %v, %v",
+					quote.Tax, quote.Total, quote.PricesIncludeTax, tt.wantTax, tt.wantTotal, tt.inclusive)
+			}
+		})
+	}
+}
@@ -1,7 +1,7 @@
 package application
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -13,6 +13,15 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrUserNotFound       = notFoundError("user not found")
+	ErrInvalidRole        = fieldError("role", "oneof", "role must be customer, editor, reviewer or admin")
+	ErrUsernameTaken      = conflictError("username already taken")
+	ErrEmailTaken         = conflictError("email already taken")
+	ErrInvalidCredentials = unauthorizedError("invalid username or password")
+	ErrInvalidToken       = unauthorizedError("invalid or expired token")
+)
+
 type UserService struct {
 	repo repository.UserRepository
 }
@@ -23,61 +32,62 @@ func NewUserService(repo repository.UserRepository) *UserService {
 	}
 }
 
-func (s *UserService) Register(user *domain.User) error {
+func (s *UserService) Register(ctx context.Context, user *domain.User) error {
 	// Check if username already exists
-	existingUser, err := s.repo.GetByUsername(user.Username)
+	existingUser, err := s.repo.GetByUsername(ctx, user.Username)
 	if err != nil {
-		return fmt.Errorf("error checking username: %v", err)
+		return fmt.Errorf("error checking username: %w", err)
 	}
 	if existingUser != nil {
-		return errors.New("username already exists")
+		return ErrUsernameTaken
 	}
 
 	// Check if email already exists
-	existingUser, err = s.repo.GetByEmail(user.Email)
+	existingUser, err = s.repo.GetByEmail(ctx, user.Email)
 	if err != nil {
-		return fmt.Errorf("error checking email: %v", err)
+		return fmt.Errorf("error checking email: %w", err)
 	}
 	if existingUser != nil {
-		return errors.New("email already exists")
+		return ErrEmailTaken
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return fmt.Errorf("error hashing password: %v", err)
+		return fmt.Errorf("error hashing password: %w", err)
 	}
 	user.Password = string(hashedPassword)
+	user.Role = domain.RoleCustomer
 
 	// Create user
-	if err := s.repo.Create(user); err != nil {
-		return fmt.Errorf("error creating user: %v", err)
+	if err := s.repo.Create(ctx, user); err != nil {
+		return fmt.Errorf("error creating user: %w", err)
 	}
 
 	return nil
 }
 
-func (s *UserService) Login(username, password string) (string, error) {
+func (s *UserService) Login(ctx context.Context, username, password string) (string, error) {
 	// Get user by username
-	user, err := s.repo.GetByUsername(username)
+	user, err := s.repo.GetByUsername(ctx, username)
 	if err != nil {
-		return "", fmt.Errorf("error getting user: %v", err)
+		return "", fmt.Errorf("error getting user: %w", err)
 	}
 	if user == nil {
-		return "", errors.New("invalid username or password")
+		return "", ErrInvalidCredentials
 	}
 
 	// Check password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return "", errors.New("invalid username or password")
+		return "", ErrInvalidCredentials
 	}
 
 	// Update last login time
 	now := time.Now()
 	user.LastLoginAt = &now
-	if err := s.repo.Update(user); err != nil {
-		return "", fmt.Errorf("error updating last login time: %v", err)
+	if err := s.repo.Update(ctx, user); err != nil {
+		return "", fmt.Errorf("error updating last login time: %w", err)
 	}
 
 	claims := jwt.MapClaims{
@@ -87,29 +97,29 @@ func (s *UserService) Login(username, password string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
 	if err != nil {
-		return "", fmt.Errorf("error generating token: %v", err)
+		return "", fmt.Errorf("error generating token: %w", err)
 	}
 
 	return tokenString, nil
 }
 
-func (s *UserService) Update(user *domain.User) error {
+func (s *UserService) Update(ctx context.Context, user *domain.User) error {
 	// Check if username is taken by another user
-	existingUser, err := s.repo.GetByUsername(user.Username)
+	existingUser, err := s.repo.GetByUsername(ctx, user.Username)
 	if err != nil {
-		return fmt.Errorf("error checking username: %v", err)
+		return fmt.Errorf("error checking username: %w", err)
 	}
 	if existingUser != nil && existingUser.ID != user.ID {
-		return errors.New("username already taken")
+		return ErrUsernameTaken
 	}
 
 	// Check if email is taken by another user
-	existingUser, err = s.repo.GetByEmail(user.Email)
+	existingUser, err = s.repo.GetByEmail(ctx, user.Email)
 	if err != nil {
-		return fmt.Errorf("error checking email: %v", err)
+		return fmt.Errorf("error checking email: %w", err)
 	}
 	if existingUser != nil && existingUser.ID != user.ID {
-		return errors.New("email already taken")
+		return ErrEmailTaken
 	}
 
 	// Clean input data
@@ -119,30 +129,53 @@ func (s *UserService) Update(user *domain.User) error {
 	user.Gender = strings.ToLower(strings.TrimSpace(user.Gender))
 
 	// Update user
-	return s.repo.Update(user)
+	return s.repo.Update(ctx, user)
 }
 
-func (s *UserService) GetUserFromToken(token string) (*domain.User, error) {
+func (s *UserService) GetUserFromToken(ctx context.Context, token string) (*domain.User, error) {
 	claims := jwt.MapClaims{}
 	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
 		return []byte(os.Getenv("JWT_SECRET")), nil
 	})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
 	}
 
 	id, ok := claims["id"].(float64)
 	if !ok {
-		return nil, errors.New("invalid token")
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.repo.GetByID(ctx, uint(id))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		// The user was deleted after the token was issued
+		return nil, ErrInvalidToken
+	}
+
+	return user, nil
+}
+
+// SetRole changes the role of a user. New users are customers; the first
+// admin has to be assigned in the database.
+func (s *UserService) SetRole(ctx context.Context, id uint, role domain.Role) (*domain.User, error) {
+	if !role.Valid() {
+		return nil, ErrInvalidRole
 	}
 
-	user, err := s.repo.GetByID(uint(id))
+	user, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
+	user.Role = role
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, err
+	}
 	return user, nil
 }
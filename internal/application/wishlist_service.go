@@ -0,0 +1,339 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/ports/repository"
+)
+
+var (
+	ErrWishlistNotFound     = notFoundError("wishlist not found")
+	ErrInvalidWishlistName  = fieldError("name", "length", "wishlist name must be between 1 and 100 characters")
+	ErrTooManyWishlists     = conflictError("a user can have at most 20 wishlists")
+	ErrWishlistFull         = conflictError("a wishlist can hold at most 200 products")
+	ErrWishlistItemExists   = conflictError("product is already in the wishlist")
+	ErrWishlistItemNotFound = notFoundError("product is not in the wishlist")
+)
+
+const (
+	maxWishlistNameLength = 100
+	maxWishlistsPerUser   = 20
+	maxWishlistItems      = 200
+)
+
+// WishlistService manages the wishlists users save products in. Products are
+// looked up through the ProductService, so only live products can be saved
+// and shown.
+type WishlistService struct {
+	repo     repository.WishlistRepository
+	products *ProductService
+	now      func() time.Time
+
+	// mu serializes changes so the wishlist and item limits hold
+	mu sync.Mutex
+}
+
+func NewWishlistService(repo repository.WishlistRepository, products *ProductService) *WishlistService {
+	return &WishlistService{
+		repo:     repo,
+		products: products,
+		now:      time.Now,
+	}
+}
+
+// GetWishlists returns the wishlists of a user, oldest first.
+func (s *WishlistService) GetWishlists(ctx context.Context, userID uint) ([]domain.Wishlist, error) {
+	wishlists, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if wishlists == nil {
+		return []domain.Wishlist{}, nil
+	}
+	listed := make([]*domain.Wishlist, len(wishlists))
+	for i := range wishlists {
+		listed[i] = &wishlists[i]
+	}
+	if err := s.attachProducts(ctx, listed...); err != nil {
+		return nil, err
+	}
+	return wishlists, nil
+}
+
+// GetWishlist returns a wishlist of a user. The wishlists of other users are
+// reported as not found.
+func (s *WishlistService) GetWishlist(ctx context.Context, id, userID uint) (*domain.Wishlist, error) {
+	wishlist, err := s.owned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachProducts(ctx, wishlist); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+// GetSharedWishlist returns the public wishlist with the share token. A
+// private wishlist is reported as not found.
+func (s *WishlistService) GetSharedWishlist(ctx context.Context, token string) (*domain.Wishlist, error) {
+	wishlist, err := s.repo.GetByShareToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if wishlist == nil || !wishlist.Public {
+		return nil, ErrWishlistNotFound
+	}
+	if err := s.attachProducts(ctx, wishlist); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+func (s *WishlistService) CreateWishlist(ctx context.Context, userID uint, name string, public bool) (*domain.Wishlist, error) {
+	name, err := normalizeWishlistName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count, err := s.repo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxWishlistsPerUser {
+		return nil, ErrTooManyWishlists
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	wishlist := &domain.Wishlist{
+		UserID:     userID,
+		Name:       name,
+		Public:     public,
+		ShareToken: token,
+	}
+	if err := s.repo.Create(ctx, wishlist); err != nil {
+		return nil, err
+	}
+	wishlist.Items = []domain.WishlistItem{}
+	return wishlist, nil
+}
+
+// UpdateWishlist renames a wishlist and sets whether its share link works.
+func (s *WishlistService) UpdateWishlist(ctx context.Context, id, userID uint, name string, public bool) (*domain.Wishlist, error) {
+	name, err := normalizeWishlistName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wishlist, err := s.owned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	wishlist.Name = name
+	wishlist.Public = public
+	return s.update(ctx, wishlist)
+}
+
+// ResetShareLink gives a wishlist a new share token, so links shared
+// before stop working.
+func (s *WishlistService) ResetShareLink(ctx context.Context, id, userID uint) (*domain.Wishlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wishlist, err := s.owned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	wishlist.ShareToken = token
+	return s.update(ctx, wishlist)
+}
+
+func (s *WishlistService) DeleteWishlist(ctx context.Context, id, userID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.owned(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// AddItem saves a live product in a wishlist of a user.
+func (s *WishlistService) AddItem(ctx context.Context, id, userID, productID uint) (*domain.Wishlist, error) {
+	product, err := s.products.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !product.IsLive(s.now()) {
+		return nil, ErrProductNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wishlist, err := s.owned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(wishlist.Items) >= maxWishlistItems {
+		return nil, ErrWishlistFull
+	}
+
+	err = s.repo.AddItem(ctx, &domain.WishlistItem{
+		WishlistID: id,
+		ProductID:  productID,
+		AddedAt:    s.now(),
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			return nil, ErrWishlistItemExists
+		}
+		return nil, err
+	}
+	return s.reload(ctx, id)
+}
+
+// RemoveItem removes a product from a wishlist of a user. Products no longer
+// available can be removed too.
+func (s *WishlistService) RemoveItem(ctx context.Context, id, userID, productID uint) (*domain.Wishlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.owned(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	removed, err := s.repo.RemoveItem(ctx, id, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, ErrWishlistItemNotFound
+	}
+	return s.reload(ctx, id)
+}
+
+// MarkFavorites sets the Favorited flag of products for a user: whether
+// each product is in one of the wishlists of the user.
+func (s *WishlistService) MarkFavorites(ctx context.Context, userID uint, products ...*domain.Product) error {
+	ids := make([]uint, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	saved, err := s.repo.GetSavedProductIDs(ctx, userID, uniqueIDs(ids))
+	if err != nil {
+		return err
+	}
+
+	favorites := make(map[uint]bool, len(saved))
+	for _, id := range saved {
+		favorites[id] = true
+	}
+	for _, product := range products {
+		favorited := favorites[product.ID]
+		product.Favorited = &favorited
+	}
+	return nil
+}
+
+// owned returns a wishlist of a user, without its products.
+func (s *WishlistService) owned(ctx context.Context, id, userID uint) (*domain.Wishlist, error) {
+	wishlist, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wishlist == nil || wishlist.UserID != userID {
+		return nil, ErrWishlistNotFound
+	}
+	return wishlist, nil
+}
+
+func (s *WishlistService) update(ctx context.Context, wishlist *domain.Wishlist) (*domain.Wishlist, error) {
+	if err := s.repo.Update(ctx, wishlist); err != nil {
+		return nil, err
+	}
+	return s.reload(ctx, wishlist.ID)
+}
+
+// reload reads a wishlist back with its products.
+func (s *WishlistService) reload(ctx context.Context, id uint) (*domain.Wishlist, error) {
+	wishlist, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wishlist == nil {
+		return nil, ErrWishlistNotFound
+	}
+	if err := s.attachProducts(ctx, wishlist); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+// attachProducts fills the Product field of the items of wishlists in place
+// with the products that are live.
+func (s *WishlistService) attachProducts(ctx context.Context, wishlists ...*domain.Wishlist) error {
+	var ids []uint
+	for _, wishlist := range wishlists {
+		for _, item := range wishlist.Items {
+			ids = append(ids, item.ProductID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	products, err := s.products.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	live := make(map[uint]*domain.Product, len(products))
+	for i := range products {
+		if products[i].IsLive(now) {
+			live[products[i].ID] = &products[i]
+		}
+	}
+	for _, wishlist := range wishlists {
+		for i := range wishlist.Items {
+			wishlist.Items[i].Product = live[wishlist.Items[i].ProductID]
+		}
+	}
+	return nil
+}
+
+func normalizeWishlistName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxWishlistNameLength {
+		return "", ErrInvalidWishlistName
+	}
+	return name, nil
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// Cart holds the items a shopper intends to buy. A signed in user has one
+// cart, identified by UserID; a guest cart is identified by Token, which is
+// kept in a cookie until the guest signs in.
+type Cart struct {
+	ID        uint       `json:"id"`
+	UserID    *uint      `json:"user_id" gorm:"uniqueIndex"`
+	Token     *string    `json:"-" gorm:"uniqueIndex"`
+	Items     []CartItem `json:"items" gorm:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Filled when the cart is read
+	Subtotal float64     `json:"subtotal" gorm:"-"`
+	Issues   []CartIssue `json:"issues" gorm:"-"`
+}
+
+// CartItem is a quantity of a product, or of one of its variants. UnitPrice,
+// SKU and Name are copied from the catalog when the item is added.
+type CartItem struct {
+	ID        uint      `json:"id"`
+	CartID    uint      `json:"-" gorm:"index;not null"`
+	ProductID uint      `json:"product_id" gorm:"not null"`
+	VariantID *uint     `json:"variant_id"`
+	SKU       string    `json:"sku" gorm:"not null"`
+	Name      string    `json:"name" gorm:"not null"`
+	Quantity  int       `json:"quantity" gorm:"not null"`
+	UnitPrice float64   `json:"unit_price" gorm:"not null"`
+	AddedAt   time.Time `json:"added_at"`
+}
+
+type CartIssueType string
+
+const (
+	// CartPriceChanged means the catalog price differs from the price the
+	// item was added at.
+	CartPriceChanged CartIssueType = "price_changed"
+	// CartItemUnavailable means the product or variant can no longer be bought.
+	CartItemUnavailable CartIssueType = "unavailable"
+)
+
+// CartIssue is a difference between a cart item and the catalog found by the
+// re-pricing check.
+type CartIssue struct {
+	ItemID       uint          `json:"item_id"`
+	Type         CartIssueType `json:"type"`
+	UnitPrice    float64       `json:"unit_price"`
+	CurrentPrice *float64      `json:"current_price,omitempty"`
+}
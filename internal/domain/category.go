@@ -0,0 +1,8 @@
+package domain
+
+type Category struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name" gorm:"not null"`
+	Description string `json:"description"`
+	ParentID    *uint  `json:"parent_id" gorm:"index"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "math"
+
+// RoundingMode decides which way converted prices are rounded.
+type RoundingMode string
+
+const (
+	RoundNearest RoundingMode = "nearest"
+	RoundUp      RoundingMode = "up"
+	RoundDown    RoundingMode = "down"
+)
+
+func (m RoundingMode) Valid() bool {
+	return m == RoundNearest || m == RoundUp || m == RoundDown
+}
+
+// Round rounds amount to a multiple of increment, such as 0.01 for cents or
+// 1 for whole units.
+func (m RoundingMode) Round(amount, increment float64) float64 {
+	// Drop the float error of the division so exact multiples stay put
+	units := math.Round(amount/increment*1e6) / 1e6
+	switch m {
+	case RoundUp:
+		units = math.Ceil(units)
+	case RoundDown:
+		units = math.Floor(units)
+	default:
+		units = math.Round(units)
+	}
+	return math.Round(units*increment*1e6) / 1e6
+}
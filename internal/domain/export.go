@@ -0,0 +1,9 @@
+package domain
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"
+	ExportFormatNDJSON ExportFormat = "ndjson"
+	ExportFormatXLSX   ExportFormat = "xlsx"
+)
@@ -0,0 +1,26 @@
+package domain
+
+type ProductFacets struct {
+	Total        int             `json:"total"`
+	Tags         []TagFacet      `json:"tags"`
+	Categories   []CategoryFacet `json:"categories"`
+	PriceBuckets []PriceBucket   `json:"price_buckets"`
+}
+
+type TagFacet struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type CategoryFacet struct {
+	CategoryID uint   `json:"category_id"`
+	Name       string `json:"name"`
+	Count      int    `json:"count"`
+}
+
+// PriceBucket counts products with Min <= price < Max. A nil Max means no upper bound.
+type PriceBucket struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int      `json:"count"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// ProductImage is a photo of a product stored in a blob store. Images are
+// shown in Position order; at most one image per product is primary.
+type ProductImage struct {
+	ID           uint      `json:"id"`
+	ProductID    uint      `json:"product_id" gorm:"index;not null"`
+	Key          string    `json:"-" gorm:"not null"`
+	ThumbnailKey string    `json:"-" gorm:"not null"`
+	URL          string    `json:"url" gorm:"not null"`
+	ThumbnailURL string    `json:"thumbnail_url" gorm:"not null"`
+	ContentType  string    `json:"content_type" gorm:"not null"`
+	Size         int64     `json:"size" gorm:"not null"`
+	Width        int       `json:"width" gorm:"not null"`
+	Height       int       `json:"height" gorm:"not null"`
+	Position     int       `json:"position" gorm:"not null"`
+	IsPrimary    bool      `json:"is_primary" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV    ImportFormat = "csv"
+	ImportFormatNDJSON ImportFormat = "ndjson"
+)
+
+// ImportMode controls what happens to rows whose SKU already exists: create
+// reports them as errors, upsert updates the existing product.
+type ImportMode string
+
+const (
+	ImportModeCreate ImportMode = "create"
+	ImportModeUpsert ImportMode = "upsert"
+)
+
+// ImportRowError describes why one row of an import file was rejected.
+// Line is the 1-based line of the row in the file.
+type ImportRowError struct {
+	Line  int    `json:"line"`
+	SKU   string `json:"sku,omitempty"`
+	Error string `json:"error"`
+}
+
+// ImportReport summarizes an import. Committed is false for dry runs and for
+// single-transaction imports that were rolled back because a row failed; the
+// counts then describe what would have been written.
+type ImportReport struct {
+	Total           int              `json:"total"`
+	Created         int              `json:"created"`
+	Updated         int              `json:"updated"`
+	Failed          int              `json:"failed"`
+	DryRun          bool             `json:"dry_run"`
+	Committed       bool             `json:"committed"`
+	Errors          []ImportRowError `json:"errors"`
+	ErrorsTruncated bool             `json:"errors_truncated"`
+}
+
+type ImportJobStatus string
+
+const (
+	ImportJobPending   ImportJobStatus = "pending"
+	ImportJobRunning   ImportJobStatus = "running"
+	ImportJobCompleted ImportJobStatus = "completed"
+	ImportJobFailed    ImportJobStatus = "failed"
+)
+
+// ImportJob tracks an import running in the background. Processed counts the
+// rows read so far; Report is set once the job has completed.
+type ImportJob struct {
+	ID         string          `json:"id"`
+	Status     ImportJobStatus `json:"status"`
+	Processed  int             `json:"processed"`
+	Report     *ImportReport   `json:"report,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
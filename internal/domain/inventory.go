@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// DefaultWarehouse is used when a stock operation does not name a warehouse.
+const DefaultWarehouse = "main"
+
+// StockLevel is the stock of one product in one warehouse. Reserved units are
+// held for pending orders and are not available for sale.
+type StockLevel struct {
+	ProductID         uint      `json:"product_id" gorm:"primaryKey;autoIncrement:false"`
+	Warehouse         string    `json:"warehouse" gorm:"primaryKey"`
+	OnHand            int       `json:"on_hand" gorm:"not null"`
+	Reserved          int       `json:"reserved" gorm:"not null"`
+	LowStockThreshold int       `json:"low_stock_threshold" gorm:"not null"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func (l *StockLevel) Available() int {
+	return l.OnHand - l.Reserved
+}
+
+func (l *StockLevel) IsLow() bool {
+	return l.Available() <= l.LowStockThreshold
+}
+
+type StockMovementType string
+
+const (
+	StockMovementAdjustment StockMovementType = "adjustment"
+	StockMovementReserve    StockMovementType = "reserve"
+	StockMovementRelease    StockMovementType = "release"
+	StockMovementCommit     StockMovementType = "commit"
+)
+
+// StockMovement is an immutable ledger entry for a change to a StockLevel.
+// OnHandDelta and ReservedDelta record how each counter moved.
+type StockMovement struct {
+	ID            uint              `json:"id"`
+	ProductID     uint              `json:"product_id" gorm:"index;not null"`
+	Warehouse     string            `json:"warehouse" gorm:"not null"`
+	Type          StockMovementType `json:"type" gorm:"not null"`
+	OnHandDelta   int               `json:"on_hand_delta" gorm:"not null"`
+	ReservedDelta int               `json:"reserved_delta" gorm:"not null"`
+	Reference     string            `json:"reference"`
+	Reason        string            `json:"reason"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// StockAvailability summarizes the stock of a product across warehouses.
+type StockAvailability struct {
+	ProductID  uint         `json:"product_id"`
+	OnHand     int          `json:"on_hand"`
+	Reserved   int          `json:"reserved"`
+	Available  int          `json:"available"`
+	InStock    bool         `json:"in_stock"`
+	Warehouses []StockLevel `json:"warehouses"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// ProductStatus is the lifecycle state of a product. Only published products
+// are shown to the public, and only within their publish window.
+type ProductStatus string
+
+const (
+	ProductDraft         ProductStatus = "draft"
+	ProductPendingReview ProductStatus = "pending_review"
+	ProductPublished     ProductStatus = "published"
+	ProductArchived      ProductStatus = "archived"
+)
+
+func (s ProductStatus) Valid() bool {
+	switch s {
+	case ProductDraft, ProductPendingReview, ProductPublished, ProductArchived:
+		return true
+	}
+	return false
+}
+
+// ProductAction moves a product from one lifecycle state to another.
+type ProductAction string
+
+const (
+	ProductSubmit    ProductAction = "submit"
+	ProductApprove   ProductAction = "approve"
+	ProductReject    ProductAction = "reject"
+	ProductUnpublish ProductAction = "unpublish"
+	ProductArchive   ProductAction = "archive"
+	ProductRestore   ProductAction = "restore"
+)
+
+// IsLive reports whether the product is visible to the public at t: it is
+// published, its publish time (if any) has passed and its unpublish time
+// (if any) has not.
+func (p *Product) IsLive(t time.Time) bool {
+	if p.Status != ProductPublished {
+		return false
+	}
+	if p.PublishAt != nil && p.PublishAt.After(t) {
+		return false
+	}
+	return p.UnpublishAt == nil || p.UnpublishAt.After(t)
+}
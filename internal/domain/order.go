@@ -0,0 +1,81 @@
+package domain
+
+import "time"
+
+// OrderStatus is the state of an order, from checkout to delivery.
+type OrderStatus string
+
+const (
+	OrderPending   OrderStatus = "pending"
+	OrderPaid      OrderStatus = "paid"
+	OrderFulfilled OrderStatus = "fulfilled"
+	OrderShipped   OrderStatus = "shipped"
+	OrderDelivered OrderStatus = "delivered"
+	OrderCancelled OrderStatus = "cancelled"
+	OrderRefunded  OrderStatus = "refunded"
+)
+
+func (s OrderStatus) Valid() bool {
+	switch s {
+	case OrderPending, OrderPaid, OrderFulfilled, OrderShipped, OrderDelivered, OrderCancelled, OrderRefunded:
+		return true
+	}
+	return false
+}
+
+// Order is a checked out cart. Its items and amounts are copied when the
+// order is placed and do not follow later catalog changes. Amounts are in
+// Currency, converted from the catalog prices in BaseCurrency at
+// ExchangeRate.
+type Order struct {
+	ID         uint               `json:"id"`
+	Number     string             `json:"number" gorm:"uniqueIndex;not null"`
+	UserID     uint               `json:"user_id" gorm:"index;not null"`
+	Status     OrderStatus        `json:"status" gorm:"index;not null"`
+	Items      []OrderItem        `json:"items" gorm:"-"`
+	Coupon     string             `json:"coupon,omitempty"`
+	Promotions []AppliedPromotion `json:"promotions" gorm:"serializer:json"`
+	Subtotal   float64            `json:"subtotal" gorm:"not null"`
+	Discount   float64            `json:"discount" gorm:"not null"`
+	Tax        float64            `json:"tax" gorm:"not null;default:0"`
+	Total      float64            `json:"total" gorm:"not null"`
+	// TaxAddress is where the order was taxed
+	TaxAddress       TaxAddress   `json:"tax_address" gorm:"embedded;embeddedPrefix:tax_"`
+	PricesIncludeTax bool         `json:"prices_include_tax" gorm:"not null;default:false"`
+	Currency         string       `json:"currency" gorm:"size:3"`
+	BaseCurrency     string       `json:"base_currency" gorm:"size:3"`
+	ExchangeRate     float64      `json:"exchange_rate" gorm:"not null;default:1"`
+	History          []OrderEvent `json:"history" gorm:"-"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// OrderItem is a line of an order, with the product details and price it
+// was bought at.
+type OrderItem struct {
+	ID        uint    `json:"id"`
+	OrderID   uint    `json:"-" gorm:"index;not null"`
+	ProductID uint    `json:"product_id" gorm:"index;not null"`
+	VariantID *uint   `json:"variant_id"`
+	SKU       string  `json:"sku" gorm:"not null"`
+	Name      string  `json:"name" gorm:"not null"`
+	Quantity  int     `json:"quantity" gorm:"not null"`
+	UnitPrice float64 `json:"unit_price" gorm:"not null"`
+	Subtotal  float64 `json:"subtotal" gorm:"not null"`
+	Discount  float64 `json:"discount" gorm:"not null"`
+	TaxRate   float64 `json:"tax_rate" gorm:"not null;default:0"`
+	Tax       float64 `json:"tax" gorm:"not null;default:0"`
+	Total     float64 `json:"total" gorm:"not null"`
+}
+
+// OrderEvent records a change of order status. From is empty for the event
+// that places the order.
+type OrderEvent struct {
+	ID        uint        `json:"id"`
+	OrderID   uint        `json:"-" gorm:"index;not null"`
+	From      OrderStatus `json:"from,omitempty"`
+	To        OrderStatus `json:"to" gorm:"not null"`
+	Actor     string      `json:"actor"`
+	Note      string      `json:"note,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
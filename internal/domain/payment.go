@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// PaymentStatus is the state of a payment at the payment provider.
+type PaymentStatus string
+
+const (
+	// PaymentPending waits for the customer to pay.
+	PaymentPending PaymentStatus = "pending"
+	// PaymentAuthorized holds the funds until they are captured or voided.
+	PaymentAuthorized PaymentStatus = "authorized"
+	PaymentCaptured   PaymentStatus = "captured"
+	PaymentFailed     PaymentStatus = "failed"
+	PaymentRefunded   PaymentStatus = "refunded"
+	PaymentVoided     PaymentStatus = "voided"
+)
+
+// Payment is an attempt to pay for an order, backed by a payment intent at
+// the provider. An order may have several payments when earlier ones failed.
+type Payment struct {
+	ID        uint          `json:"id"`
+	OrderID   uint          `json:"order_id" gorm:"index;not null"`
+	IntentID  string        `json:"intent_id" gorm:"uniqueIndex;not null"`
+	Amount    float64       `json:"amount" gorm:"not null"`
+	Currency  string        `json:"currency" gorm:"not null"`
+	Status    PaymentStatus `json:"status" gorm:"index;not null"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+
+	// ClientSecret lets the client complete the payment with the provider.
+	// It is not stored.
+	ClientSecret string `json:"client_secret,omitempty" gorm:"-"`
+}
+
+// PaymentEvent records a provider webhook event that was applied, so a
+// redelivered event is recognized.
+type PaymentEvent struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	Type      string    `json:"type" gorm:"not null"`
+	IntentID  string    `json:"intent_id" gorm:"index;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
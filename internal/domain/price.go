@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// PriceChange records one change of a product's price. Actor names the user
+// or process that made the change.
+type PriceChange struct {
+	ID        uint      `json:"id"`
+	ProductID uint      `json:"product_id" gorm:"index;not null"`
+	OldPrice  float64   `json:"old_price" gorm:"not null"`
+	NewPrice  float64   `json:"new_price" gorm:"not null"`
+	Actor     string    `json:"actor" gorm:"not null"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+type PriceScheduleStatus string
+
+const (
+	PriceScheduleScheduled PriceScheduleStatus = "scheduled"
+	PriceScheduleActive    PriceScheduleStatus = "active"
+	PriceScheduleCompleted PriceScheduleStatus = "completed"
+	PriceScheduleCancelled PriceScheduleStatus = "cancelled"
+)
+
+// PriceSchedule is a future price change. With EndsAt set it is a sale
+// window: Price applies from StartsAt until EndsAt, after which the price in
+// effect before the window (OriginalPrice) is restored. Without EndsAt the
+// new price is permanent.
+type PriceSchedule struct {
+	ID            uint                `json:"id"`
+	ProductID     uint                `json:"product_id" gorm:"index;not null"`
+	Price         float64             `json:"price" gorm:"not null"`
+	StartsAt      time.Time           `json:"starts_at" gorm:"index;not null"`
+	EndsAt        *time.Time          `json:"ends_at"`
+	Reason        string              `json:"reason"`
+	Actor         string              `json:"actor" gorm:"not null"`
+	Status        PriceScheduleStatus `json:"status" gorm:"index;not null"`
+	OriginalPrice *float64            `json:"original_price"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
@@ -1,8 +1,60 @@
 package domain
 
+import "time"
+
 type Product struct {
-	ID          uint    `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	ID          uint     `json:"id"`
+	SKU         string   `json:"sku" gorm:"uniqueIndex"`
+	Barcode     *string  `json:"barcode" gorm:"uniqueIndex"`
+	Slug        string   `json:"slug" gorm:"uniqueIndex"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Price       float64  `json:"price"`
+	Tags        []string `json:"tags" gorm:"-"`
+	// TaxClass selects the tax rates that apply to the product
+	TaxClass string `json:"tax_class" gorm:"not null;default:standard"`
+	// CurrencyPrices are set prices per currency code that replace the
+	// converted price of the product, but not of its variants
+	CurrencyPrices map[string]float64 `json:"currency_prices" gorm:"serializer:json"`
+	// Currency is set when Price and the variant prices were converted from
+	// the base currency for display
+	Currency string `json:"currency,omitempty" gorm:"-"`
+
+	// Products created before lifecycle states existed were already live,
+	// hence the published column default
+	Status      ProductStatus `json:"status" gorm:"index;not null;default:published"`
+	PublishAt   *time.Time    `json:"publish_at"`
+	UnpublishAt *time.Time    `json:"unpublish_at"`
+	ReviewNote  string        `json:"review_note"`
+
+	Options  []ProductOption  `json:"options" gorm:"-"`
+	Variants []ProductVariant `json:"variants" gorm:"-"`
+	Images   []ProductImage   `json:"images" gorm:"-"`
+
+	// Rating is computed from the approved reviews when the product is read
+	Rating *ProductRating `json:"rating,omitempty" gorm:"-"`
+	// Favorited is set for signed in users: whether the product is in one
+	// of their wishlists
+	Favorited *bool `json:"favorited,omitempty" gorm:"-"`
+}
+
+// TagMatch controls how ProductFilter.Tags are combined.
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any"
+	TagMatchAll TagMatch = "all"
+)
+
+// ProductFilter narrows a product listing. Zero values mean "no restriction",
+// except ProductIDs where a non-nil empty slice matches nothing. LiveAt keeps
+// only the products that are live at that time.
+type ProductFilter struct {
+	Tags       []string
+	TagMatch   TagMatch
+	MinPrice   *float64
+	MaxPrice   *float64
+	ProductIDs []uint
+	Statuses   []ProductStatus
+	LiveAt     *time.Time
 }
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+type PromotionType string
+
+const (
+	// PromotionPercentage takes Value percent off the targeted items.
+	PromotionPercentage PromotionType = "percentage"
+	// PromotionFixed takes Value off each targeted unit.
+	PromotionFixed PromotionType = "fixed"
+	// PromotionBuyXGetY discounts GetQuantity units by Value percent for every
+	// BuyQuantity units bought of the same item; a Value of 100 makes them free.
+	PromotionBuyXGetY PromotionType = "buy_x_get_y"
+)
+
+// Promotion is a discount rule. Without a Code it applies automatically to
+// every targeted item; with one it applies only when the coupon is given.
+// Empty ProductIDs and CategoryIDs target every product, and a category
+// target includes the categories below it. Promotions stack in descending
+// Priority, each discounting what the previous ones left.
+type Promotion struct {
+	ID           uint          `json:"id"`
+	Name         string        `json:"name" gorm:"not null"`
+	Type         PromotionType `json:"type" gorm:"not null"`
+	Value        float64       `json:"value" gorm:"not null"`
+	BuyQuantity  int           `json:"buy_quantity"`
+	GetQuantity  int           `json:"get_quantity"`
+	Code         *string       `json:"code" gorm:"uniqueIndex"`
+	UsageLimit   *int          `json:"usage_limit"`
+	PerUserLimit *int          `json:"per_user_limit"`
+	UsageCount   int           `json:"usage_count" gorm:"not null;default:0"`
+	StartsAt     *time.Time    `json:"starts_at"`
+	EndsAt       *time.Time    `json:"ends_at"`
+	Active       bool          `json:"active" gorm:"not null"`
+	Priority     int           `json:"priority" gorm:"not null;default:0"`
+	ProductIDs   []uint        `json:"product_ids" gorm:"serializer:json"`
+	CategoryIDs  []uint        `json:"category_ids" gorm:"serializer:json"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// InEffect reports whether the promotion is active and inside its validity
+// window at t.
+func (p *Promotion) InEffect(t time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && t.Before(*p.StartsAt) {
+		return false
+	}
+	return p.EndsAt == nil || t.Before(*p.EndsAt)
+}
+
+// Exhausted reports whether the promotion reached its global usage limit.
+func (p *Promotion) Exhausted() bool {
+	return p.UsageLimit != nil && p.UsageCount >= *p.UsageLimit
+}
+
+// PromotionRedemption records one use of a promotion by a user.
+type PromotionRedemption struct {
+	ID          uint      `json:"id"`
+	PromotionID uint      `json:"promotion_id" gorm:"index;not null"`
+	UserID      uint      `json:"user_id" gorm:"index;not null"`
+	Reference   string    `json:"reference"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// LineItem is a quantity of a product, or of one of its variants, to price.
+type LineItem struct {
+	ProductID uint  `json:"product_id"`
+	VariantID *uint `json:"variant_id"`
+	Quantity  int   `json:"quantity"`
+}
+
+// AppliedPromotion is the discount one promotion gave.
+type AppliedPromotion struct {
+	PromotionID uint          `json:"promotion_id"`
+	Name        string        `json:"name"`
+	Type        PromotionType `json:"type"`
+	Code        string        `json:"code,omitempty"`
+	Amount      float64       `json:"amount"`
+}
+
+// PricedLine is a line item with its price before and after discounts, and
+// the tax on what remains. TaxRate is a percentage.
+type PricedLine struct {
+	LineItem
+	SKU       string             `json:"sku"`
+	Name      string             `json:"name"`
+	TaxClass  string             `json:"tax_class"`
+	UnitPrice float64            `json:"unit_price"`
+	Subtotal  float64            `json:"subtotal"`
+	Discount  float64            `json:"discount"`
+	TaxRate   float64            `json:"tax_rate"`
+	Tax       float64            `json:"tax"`
+	Total     float64            `json:"total"`
+	Applied   []AppliedPromotion `json:"applied"`
+	// BaseUnitPrice is the catalog price in the base currency, before any
+	// conversion
+	BaseUnitPrice float64 `json:"-"`
+}
+
+// PriceQuote is the effective price of a set of line items in Currency,
+// with the promotions that applied to the whole quote in Applied.
+// ExchangeRate converted the catalog prices from BaseCurrency, and is 1 when
+// they are the same. With
+// PricesIncludeTax, Tax is contained in the line totals and Total;
+// otherwise it is added to Total but not to the line totals.
+type PriceQuote struct {
+	Lines            []PricedLine       `json:"lines"`
+	Currency         string             `json:"currency"`
+	BaseCurrency     string             `json:"base_currency"`
+	ExchangeRate     float64            `json:"exchange_rate"`
+	Coupon           string             `json:"coupon,omitempty"`
+	Subtotal         float64            `json:"subtotal"`
+	Discount         float64            `json:"discount"`
+	Tax              float64            `json:"tax"`
+	Total            float64            `json:"total"`
+	TaxAddress       TaxAddress         `json:"tax_address"`
+	PricesIncludeTax bool               `json:"prices_include_tax"`
+	Applied          []AppliedPromotion `json:"applied"`
+}
+
+// RoundMoney rounds an amount to cents.
+func RoundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
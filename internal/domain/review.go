@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// ReviewStatus is the moderation state of a review. New and edited reviews
+// wait for an admin; only approved reviews are shown and counted.
+type ReviewStatus string
+
+const (
+	ReviewPending  ReviewStatus = "pending"
+	ReviewApproved ReviewStatus = "approved"
+	ReviewRejected ReviewStatus = "rejected"
+)
+
+func (s ReviewStatus) Valid() bool {
+	switch s {
+	case ReviewPending, ReviewApproved, ReviewRejected:
+		return true
+	}
+	return false
+}
+
+// Review is a customer's rating of a product. A user reviews a product once.
+type Review struct {
+	ID             uint         `json:"id"`
+	ProductID      uint         `json:"product_id" gorm:"uniqueIndex:idx_review_product_user;not null"`
+	UserID         uint         `json:"user_id" gorm:"uniqueIndex:idx_review_product_user;not null"`
+	Author         string       `json:"author" gorm:"not null"`
+	Rating         int          `json:"rating" gorm:"not null"`
+	Title          string       `json:"title"`
+	Body           string       `json:"body"`
+	Status         ReviewStatus `json:"status" gorm:"index;not null"`
+	ModerationNote string       `json:"moderation_note"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// ProductRating summarizes the approved reviews of a product. Histogram
+// counts the reviews per star rating from 1 to 5.
+type ProductRating struct {
+	Average   float64     `json:"average"`
+	Count     int         `json:"count"`
+	Histogram map[int]int `json:"histogram"`
+}
+
+// NewProductRating builds a rating summary from the number of reviews per
+// star rating, rounding the average to two decimals.
+func NewProductRating(counts map[int]int) *ProductRating {
+	rating := &ProductRating{Histogram: make(map[int]int, 5)}
+	total := 0
+	for stars := 1; stars <= 5; stars++ {
+		rating.Histogram[stars] = counts[stars]
+		rating.Count += counts[stars]
+		total += stars * counts[stars]
+	}
+	if rating.Count > 0 {
+		rating.Average = math.Round(float64(total)/float64(rating.Count)*100) / 100
+	}
+	return rating
+}
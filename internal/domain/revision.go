@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ProductSnapshotVersion is the schema version of new snapshots. Version 1
+// records the currency prices even when there are none; snapshots without a
+// version may predate them.
+const ProductSnapshotVersion = 1
+
+// ProductSnapshot is the content of a product at one point in time. It is
+// stored as JSON so revisions stay readable as the product schema evolves.
+type ProductSnapshot struct {
+	Version     int              `json:"version,omitempty"`
+	SKU         string           `json:"sku"`
+	Barcode     *string          `json:"barcode"`
+	Slug        string           `json:"slug"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Price       float64          `json:"price"`
+	Tags        []string         `json:"tags"`
+	TaxClass    string           `json:"tax_class,omitempty"`
+	Status      ProductStatus    `json:"status"`
+	PublishAt   *time.Time       `json:"publish_at"`
+	UnpublishAt *time.Time       `json:"unpublish_at"`
+	Options     []ProductOption  `json:"options"`
+	Variants    []ProductVariant `json:"variants"`
+	// CurrencyPrices is nil in revisions recorded before it existed
+	CurrencyPrices map[string]float64 `json:"currency_prices,omitempty"`
+}
+
+// HasCurrencyPrices reports whether the snapshot recorded the currency
+// prices of the product, which unversioned snapshots only did when there
+// were some.
+func (s ProductSnapshot) HasCurrencyPrices() bool {
+	return s.Version >= 1 || s.CurrencyPrices != nil
+}
+
+// NewProductSnapshot copies the content of product. Empty lists are kept as
+// empty rather than nil so snapshots compare equal after a round trip.
+func NewProductSnapshot(product *Product) ProductSnapshot {
+	snapshot := ProductSnapshot{
+		Version:     ProductSnapshotVersion,
+		SKU:         product.SKU,
+		Barcode:     product.Barcode,
+		Slug:        product.Slug,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		Tags:        append([]string{}, product.Tags...),
+		TaxClass:    product.TaxClass,
+		Status:      product.Status,
+		PublishAt:   product.PublishAt,
+		UnpublishAt: product.UnpublishAt,
+		Options:     make([]ProductOption, len(product.Options)),
+		Variants:    make([]ProductVariant, len(product.Variants)),
+	}
+	for i, option := range product.Options {
+		option.Values = append([]string{}, option.Values...)
+		snapshot.Options[i] = option
+	}
+	for i, variant := range product.Variants {
+		attributes := make(map[string]string, len(variant.Attributes))
+		for name, value := range variant.Attributes {
+			attributes[name] = value
+		}
+		variant.Attributes = attributes
+		snapshot.Variants[i] = variant
+	}
+	if product.CurrencyPrices != nil {
+		snapshot.CurrencyPrices = make(map[string]float64, len(product.CurrencyPrices))
+		for code, price := range product.CurrencyPrices {
+			snapshot.CurrencyPrices[code] = price
+		}
+	}
+	return snapshot
+}
+
+func (s ProductSnapshot) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *ProductSnapshot) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case []byte:
+		return json.Unmarshal(data, s)
+	case string:
+		return json.Unmarshal([]byte(data), s)
+	}
+	return errors.New("unsupported product snapshot value")
+}
+
+// ProductRevision is an immutable record of a product after one change.
+// Number counts the revisions of a product from 1.
+type ProductRevision struct {
+	ID        uint            `json:"id"`
+	ProductID uint            `json:"product_id" gorm:"uniqueIndex:idx_product_revision;not null"`
+	Number    int             `json:"number" gorm:"uniqueIndex:idx_product_revision;not null"`
+	Snapshot  ProductSnapshot `json:"snapshot" gorm:"type:jsonb;not null"`
+	Actor     string          `json:"actor" gorm:"not null"`
+	Reason    string          `json:"reason"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// FieldChange is one field that differs between two revisions.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// RevisionDiff lists the fields changed from one revision to another.
+type RevisionDiff struct {
+	ProductID uint          `json:"product_id"`
+	From      int           `json:"from"`
+	To        int           `json:"to"`
+	Changes   []FieldChange `json:"changes"`
+}
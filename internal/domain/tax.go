@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// DefaultTaxClass is the tax class of products that do not name one.
+const DefaultTaxClass = "standard"
+
+// TaxRounding decides where tax amounts are rounded to cents.
+type TaxRounding string
+
+const (
+	// TaxRoundPerLine rounds the tax of every line; the total is their sum.
+	TaxRoundPerLine TaxRounding = "line"
+	// TaxRoundPerTotal rounds the sum of the unrounded line taxes.
+	TaxRoundPerTotal TaxRounding = "total"
+)
+
+func (r TaxRounding) Valid() bool {
+	return r == TaxRoundPerLine || r == TaxRoundPerTotal
+}
+
+// TaxRate is the rate of a tax class in a jurisdiction: a country, or a
+// region of it. A region rate takes precedence over the country rate.
+type TaxRate struct {
+	ID        uint      `json:"id"`
+	Country   string    `json:"country" gorm:"uniqueIndex:idx_tax_rate;size:2;not null"`
+	Region    string    `json:"region" gorm:"uniqueIndex:idx_tax_rate;not null"`
+	TaxClass  string    `json:"tax_class" gorm:"uniqueIndex:idx_tax_rate;not null"`
+	Name      string    `json:"name" gorm:"not null"`
+	Rate      float64   `json:"rate" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaxAddress is where goods are taxed. Country is an ISO 3166-1 alpha-2
+// code and Region a subdivision code such as a state or province.
+type TaxAddress struct {
+	Country string `json:"country"`
+	Region  string `json:"region,omitempty"`
+}
+
+// TaxableLine is an amount to tax: a line total after discounts, with or
+// without tax depending on the pricing mode.
+type TaxableLine struct {
+	TaxClass string
+	Amount   float64
+}
+
+// LineTax is the tax on one TaxableLine. Rate is a percentage.
+type LineTax struct {
+	Rate float64 `json:"rate"`
+	Tax  float64 `json:"tax"`
+}
+
+// TaxQuote is the tax on a set of lines, with one LineTax per line in the
+// same order. With Inclusive, the line amounts already contained the tax.
+type TaxQuote struct {
+	Address   TaxAddress `json:"address"`
+	Inclusive bool       `json:"inclusive"`
+	Lines     []LineTax  `json:"lines"`
+	Tax       float64    `json:"tax"`
+}
@@ -13,5 +13,41 @@ type User struct {
 	Password    string     `json:"-" gorm:"not null"` // "-" means this field won't be included in JSON
 	Gender      string     `json:"gender" gorm:"not null"`
 	Email       string     `json:"email" gorm:"uniqueIndex;not null"`
+	Role        Role       `json:"role" gorm:"not null;default:customer"`
 	LastLoginAt *time.Time `json:"last_login_at"`
 }
+
+// Role grants a user permissions. Each role includes the permissions of the
+// roles before it: customer, editor, reviewer, admin.
+type Role string
+
+const (
+	RoleCustomer Role = "customer"
+	RoleEditor   Role = "editor"
+	RoleReviewer Role = "reviewer"
+	RoleAdmin    Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	RoleCustomer: 1,
+	RoleEditor:   2,
+	RoleReviewer: 3,
+	RoleAdmin:    4,
+}
+
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// AtLeast reports whether r has every permission of other. Unknown roles
+// have no permissions.
+func (r Role) AtLeast(other Role) bool {
+	rank, ok := roleRanks[r]
+	return ok && rank >= roleRanks[other]
+}
+
+// HasRole reports whether the user has at least role. It is false for a nil user.
+func (u *User) HasRole(role Role) bool {
+	return u != nil && u.Role.AtLeast(role)
+}
@@ -0,0 +1,22 @@
+package domain
+
+// ProductOption is a dimension a product varies in, such as size or color,
+// together with its allowed values in display order.
+type ProductOption struct {
+	ID        uint     `json:"id"`
+	ProductID uint     `json:"-" gorm:"index;not null"`
+	Name      string   `json:"name" gorm:"not null"`
+	Values    []string `json:"values" gorm:"serializer:json;not null"`
+	Position  int      `json:"position" gorm:"not null"`
+}
+
+// ProductVariant is one sellable combination of option values. Attributes maps
+// each option name of the product to the value of this variant. Variants share
+// the stock of their product.
+type ProductVariant struct {
+	ID         uint              `json:"id"`
+	ProductID  uint              `json:"-" gorm:"index;not null"`
+	SKU        string            `json:"sku" gorm:"uniqueIndex;not null"`
+	Price      float64           `json:"price" gorm:"not null"`
+	Attributes map[string]string `json:"attributes" gorm:"serializer:json;not null"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// Wishlist is a named list of products a user saved for later. A public
+// wishlist can be viewed by anyone who has its share link; a private one
+// only by its owner.
+type Wishlist struct {
+	ID         uint           `json:"id"`
+	UserID     uint           `json:"user_id" gorm:"index;not null"`
+	Name       string         `json:"name" gorm:"not null"`
+	Public     bool           `json:"public" gorm:"not null;default:false"`
+	ShareToken string         `json:"share_token" gorm:"uniqueIndex;not null"`
+	Items      []WishlistItem `json:"items" gorm:"-"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// WishlistItem is a product saved in a wishlist. Product is filled when the
+// wishlist is read and left nil once the product is no longer available.
+type WishlistItem struct {
+	ID         uint      `json:"id"`
+	WishlistID uint      `json:"-" gorm:"uniqueIndex:idx_wishlist_item;not null"`
+	ProductID  uint      `json:"product_id" gorm:"uniqueIndex:idx_wishlist_item;index;not null"`
+	AddedAt    time.Time `json:"added_at"`
+	Product    *Product  `json:"product" gorm:"-"`
+}
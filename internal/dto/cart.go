@@ -0,0 +1,13 @@
+package dto
+
+// CartItemRequest represents the request body for adding an item to the cart
+type CartItemRequest struct {
+	ProductID uint  `json:"product_id" validate:"required"`
+	VariantID *uint `json:"variant_id"`
+	Quantity  int   `json:"quantity" validate:"required,min=1,max=1000"`
+}
+
+// CartQuantityRequest represents the request body for changing the quantity of a cart item
+type CartQuantityRequest struct {
+	Quantity int `json:"quantity" validate:"required,min=1,max=1000"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+// ProductCategoriesRequest represents the request body for assigning categories to a product
+type ProductCategoriesRequest struct {
+	CategoryIDs []uint `json:"category_ids"`
+}
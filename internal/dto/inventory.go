@@ -0,0 +1,14 @@
+package dto
+
+// StockAdjustmentRequest represents the request body for a stock adjustment
+type StockAdjustmentRequest struct {
+	Warehouse string `json:"warehouse" validate:"max=50"`
+	Quantity  int    `json:"quantity" validate:"required"`
+	Reason    string `json:"reason" validate:"required,max=255"`
+}
+
+// StockThresholdRequest represents the request body for setting a low stock threshold
+type StockThresholdRequest struct {
+	Warehouse string `json:"warehouse" validate:"max=50"`
+	Threshold int    `json:"threshold" validate:"gte=0"`
+}
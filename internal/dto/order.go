@@ -0,0 +1,24 @@
+package dto
+
+// CheckoutRequest represents the optional request body for placing an order from the cart
+type CheckoutRequest struct {
+	Coupon   string `json:"coupon" validate:"max=32"`
+	Currency string `json:"currency" validate:"omitempty,len=3"`
+	TaxAddressRequest
+}
+
+// OrderStatusRequest represents the request body for changing the status of an order
+type OrderStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending paid fulfilled shipped delivered cancelled refunded"`
+	Note   string `json:"note" validate:"max=255"`
+}
+
+// OrderCancelRequest represents the optional request body for cancelling an order
+type OrderCancelRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+// RefundRequest represents the optional request body for refunding an order
+type RefundRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
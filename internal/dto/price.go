@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+// PriceScheduleRequest represents the request body for scheduling a price change.
+// Without ends_at the new price is permanent; with it the price reverts when the window ends.
+type PriceScheduleRequest struct {
+	Price    float64    `json:"price" validate:"required,gt=0"`
+	StartsAt time.Time  `json:"starts_at" validate:"required"`
+	EndsAt   *time.Time `json:"ends_at"`
+	Reason   string     `json:"reason" validate:"max=255"`
+}
@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+// ProductRequest represents the writable fields of a product, the request body
+// for creating and updating one. Patches are applied to this shape as well, so
+// computed fields such as the status, images and rating cannot be patched.
+type ProductRequest struct {
+	SKU            string                  `json:"sku"`
+	Barcode        *string                 `json:"barcode"`
+	Slug           string                  `json:"slug"`
+	Name           string                  `json:"name" validate:"required"`
+	Description    string                  `json:"description" validate:"required"`
+	Price          float64                 `json:"price" validate:"gt=0"`
+	Tags           []string                `json:"tags"`
+	TaxClass       string                  `json:"tax_class"`
+	CurrencyPrices map[string]float64      `json:"currency_prices"`
+	PublishAt      *time.Time              `json:"publish_at"`
+	UnpublishAt    *time.Time              `json:"unpublish_at"`
+	Options        []ProductOptionRequest  `json:"options" validate:"dive"`
+	Variants       []ProductVariantRequest `json:"variants" validate:"dive"`
+}
+
+// ProductOptionRequest represents an option of a product and its values
+type ProductOptionRequest struct {
+	Name   string   `json:"name" validate:"required"`
+	Values []string `json:"values" validate:"required,min=1"`
+}
+
+// ProductVariantRequest represents a variant of a product. Variants without an
+// ID are added, the others keep their ID.
+type ProductVariantRequest struct {
+	ID         uint              `json:"id"`
+	SKU        string            `json:"sku"`
+	Price      float64           `json:"price" validate:"gt=0"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// NewProductRequest returns the writable fields of product
+func NewProductRequest(product *domain.Product) *ProductRequest {
+	req := &ProductRequest{
+		SKU:            product.SKU,
+		Barcode:        product.Barcode,
+		Slug:           product.Slug,
+		Name:           product.Name,
+		Description:    product.Description,
+		Price:          product.Price,
+		Tags:           product.Tags,
+		TaxClass:       product.TaxClass,
+		CurrencyPrices: product.CurrencyPrices,
+		PublishAt:      product.PublishAt,
+		UnpublishAt:    product.UnpublishAt,
+		Options:        make([]ProductOptionRequest, len(product.Options)),
+		Variants:       make([]ProductVariantRequest, len(product.Variants)),
+	}
+	for i, option := range product.Options {
+		req.Options[i] = ProductOptionRequest{Name: option.Name, Values: option.Values}
+	}
+	for i, variant := range product.Variants {
+		req.Variants[i] = ProductVariantRequest{
+			ID:         variant.ID,
+			SKU:        variant.SKU,
+			Price:      variant.Price,
+			Attributes: variant.Attributes,
+		}
+	}
+	return req
+}
+
+// ToProduct converts ProductRequest to domain.Product
+func (r *ProductRequest) ToProduct() *domain.Product {
+	product := &domain.Product{
+		SKU:            r.SKU,
+		Barcode:        r.Barcode,
+		Slug:           r.Slug,
+		Name:           r.Name,
+		Description:    r.Description,
+		Price:          r.Price,
+		Tags:           r.Tags,
+		TaxClass:       r.TaxClass,
+		CurrencyPrices: r.CurrencyPrices,
+		PublishAt:      r.PublishAt,
+		UnpublishAt:    r.UnpublishAt,
+	}
+	for _, option := range r.Options {
+		product.Options = append(product.Options, domain.ProductOption{Name: option.Name, Values: option.Values})
+	}
+	for _, variant := range r.Variants {
+		product.Variants = append(product.Variants, domain.ProductVariant{
+			ID:         variant.ID,
+			SKU:        variant.SKU,
+			Price:      variant.Price,
+			Attributes: variant.Attributes,
+		})
+	}
+	return product
+}
+
+// ProductTransitionRequest represents the optional request body of a product lifecycle action
+type ProductTransitionRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+// ProductImageOrderRequest represents the request body for reordering product images
+type ProductImageOrderRequest struct {
+	ImageIDs []uint `json:"image_ids" validate:"required,min=1,dive,gt=0"`
+}
@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+// PromotionRequest represents the request body for creating or updating a promotion.
+// Without a code the promotion applies automatically; with one it is a coupon.
+type PromotionRequest struct {
+	Name         string               `json:"name" validate:"required,max=100"`
+	Type         domain.PromotionType `json:"type" validate:"required,oneof=percentage fixed buy_x_get_y"`
+	Value        float64              `json:"value" validate:"gte=0"`
+	BuyQuantity  int                  `json:"buy_quantity" validate:"gte=0"`
+	GetQuantity  int                  `json:"get_quantity" validate:"gte=0"`
+	Code         *string              `json:"code" validate:"omitempty,max=32"`
+	UsageLimit   *int                 `json:"usage_limit" validate:"omitempty,min=1"`
+	PerUserLimit *int                 `json:"per_user_limit" validate:"omitempty,min=1"`
+	StartsAt     *time.Time           `json:"starts_at"`
+	EndsAt       *time.Time           `json:"ends_at"`
+	Active       bool                 `json:"active"`
+	Priority     int                  `json:"priority"`
+	ProductIDs   []uint               `json:"product_ids"`
+	CategoryIDs  []uint               `json:"category_ids"`
+}
+
+// ToPromotion converts PromotionRequest to domain.Promotion
+func (r *PromotionRequest) ToPromotion() *domain.Promotion {
+	return &domain.Promotion{
+		Name:         r.Name,
+		Type:         r.Type,
+		Value:        r.Value,
+		BuyQuantity:  r.BuyQuantity,
+		GetQuantity:  r.GetQuantity,
+		Code:         r.Code,
+		UsageLimit:   r.UsageLimit,
+		PerUserLimit: r.PerUserLimit,
+		StartsAt:     r.StartsAt,
+		EndsAt:       r.EndsAt,
+		Active:       r.Active,
+		Priority:     r.Priority,
+		ProductIDs:   r.ProductIDs,
+		CategoryIDs:  r.CategoryIDs,
+	}
+}
+
+// LineItemRequest represents one item to price
+type LineItemRequest struct {
+	ProductID uint  `json:"product_id" validate:"required"`
+	VariantID *uint `json:"variant_id"`
+	Quantity  int   `json:"quantity" validate:"required,min=1,max=1000"`
+}
+
+// PriceQuoteRequest represents the request body for pricing a set of items
+type PriceQuoteRequest struct {
+	Items    []LineItemRequest `json:"items" validate:"required,min=1,max=100,dive"`
+	Coupon   string            `json:"coupon" validate:"max=32"`
+	Currency string            `json:"currency" validate:"omitempty,len=3"`
+	TaxAddressRequest
+}
+
+// ToLineItems converts the requested items to domain.LineItem values
+func (r *PriceQuoteRequest) ToLineItems() []domain.LineItem {
+	items := make([]domain.LineItem, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = domain.LineItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		}
+	}
+	return items
+}
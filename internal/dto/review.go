@@ -0,0 +1,16 @@
+package dto
+
+import "github.com/euro1061/gohex/internal/domain"
+
+// ReviewRequest represents the request body for writing or editing a review
+type ReviewRequest struct {
+	Rating int    `json:"rating" validate:"required,min=1,max=5"`
+	Title  string `json:"title" validate:"max=200"`
+	Body   string `json:"body" validate:"max=5000"`
+}
+
+// ReviewModerationRequest represents the request body for moderating a review
+type ReviewModerationRequest struct {
+	Status domain.ReviewStatus `json:"status" validate:"required,oneof=pending approved rejected"`
+	Note   string              `json:"note" validate:"max=500"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "github.com/euro1061/gohex/internal/domain"
+
+// TaxRateRequest represents the request body for creating or updating a tax rate
+type TaxRateRequest struct {
+	Country  string  `json:"country" validate:"required,len=2"`
+	Region   string  `json:"region" validate:"max=10"`
+	TaxClass string  `json:"tax_class" validate:"max=32"`
+	Name     string  `json:"name" validate:"required,max=100"`
+	Rate     float64 `json:"rate" validate:"gte=0,lte=100"`
+}
+
+// ToTaxRate converts the request to a domain.TaxRate
+func (r *TaxRateRequest) ToTaxRate() *domain.TaxRate {
+	return &domain.TaxRate{
+		Country:  r.Country,
+		Region:   r.Region,
+		TaxClass: r.TaxClass,
+		Name:     r.Name,
+		Rate:     r.Rate,
+	}
+}
+
+// TaxAddressRequest represents where a quote or order is taxed. Without a
+// country the default tax country applies.
+type TaxAddressRequest struct {
+	Country string `json:"country" validate:"omitempty,len=2"`
+	Region  string `json:"region" validate:"max=10"`
+}
+
+// ToTaxAddress converts the request to a domain.TaxAddress
+func (r *TaxAddressRequest) ToTaxAddress() domain.TaxAddress {
+	return domain.TaxAddress{Country: r.Country, Region: r.Region}
+}
@@ -30,14 +30,20 @@ type UserUpdateRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 }
 
+// UserRoleRequest represents the request body for changing a user's role
+type UserRoleRequest struct {
+	Role domain.Role `json:"role" validate:"required,oneof=customer editor reviewer admin"`
+}
+
 // UserResponse represents the response body for user-related operations
 type UserResponse struct {
-	ID          uint       `json:"id"`
-	Name        string     `json:"name"`
-	Username    string     `json:"username"`
-	Gender      string     `json:"gender"`
-	Email       string     `json:"email"`
-	LastLoginAt *time.Time `json:"last_login_at"`
+	ID          uint        `json:"id"`
+	Name        string      `json:"name"`
+	Username    string      `json:"username"`
+	Gender      string      `json:"gender"`
+	Email       string      `json:"email"`
+	Role        domain.Role `json:"role"`
+	LastLoginAt *time.Time  `json:"last_login_at"`
 }
 
 // ToUser converts UserRegisterRequest to domain.User
@@ -51,6 +57,16 @@ func (r *UserRegisterRequest) ToUser() *domain.User {
 	}
 }
 
+// UserUpdateRequestFromUser creates UserUpdateRequest from domain.User
+func UserUpdateRequestFromUser(user *domain.User) *UserUpdateRequest {
+	return &UserUpdateRequest{
+		Name:     user.Name,
+		Username: user.Username,
+		Gender:   user.Gender,
+		Email:    user.Email,
+	}
+}
+
 // FromUser creates UserResponse from domain.User
 func UserResponseFromUser(user *domain.User) *UserResponse {
 	return &UserResponse{
@@ -59,6 +75,7 @@ func UserResponseFromUser(user *domain.User) *UserResponse {
 		Username:    user.Username,
 		Gender:      user.Gender,
 		Email:       user.Email,
+		Role:        user.Role,
 		LastLoginAt: user.LastLoginAt,
 	}
 }
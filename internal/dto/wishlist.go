@@ -0,0 +1,12 @@
+package dto
+
+// WishlistRequest represents the request body for creating or updating a wishlist
+type WishlistRequest struct {
+	Name   string `json:"name" validate:"required,max=100"`
+	Public bool   `json:"public"`
+}
+
+// WishlistItemRequest represents the request body for saving a product in a wishlist
+type WishlistItemRequest struct {
+	ProductID uint `json:"product_id" validate:"required"`
+}
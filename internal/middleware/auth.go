@@ -9,10 +9,7 @@ func Auth() fiber.Handler {
 		// Get token from cookie
 		token := c.Cookies("token")
 		if token == "" {
-			return c.Status(401).JSON(fiber.Map{
-				"success": false,
-				"message": "Unauthorized - Please login first",
-			})
+			return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized - Please login first")
 		}
 
 		// Store token in locals for later use
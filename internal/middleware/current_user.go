@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserResolver looks up the user a session token belongs to.
+type UserResolver interface {
+	GetUserFromToken(ctx context.Context, token string) (*domain.User, error)
+}
+
+// CurrentUser resolves the session cookie, when present and valid, and
+// stores the user in the "user" local. It never rejects a request; routes
+// that require a session use Auth.
+func CurrentUser(users UserResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token := c.Cookies("token"); token != "" {
+			if user, err := users.GetUserFromToken(c.UserContext(), token); err == nil {
+				c.Locals("user", user)
+			}
+		}
+		return c.Next()
+	}
+}
+
+// User returns the user stored by CurrentUser, or nil.
+func User(c *fiber.Ctx) *domain.User {
+	user, _ := c.Locals("user").(*domain.User)
+	return user
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole rejects requests unless the user resolved by CurrentUser has at
+// least role. Use it after Auth.
+func RequireRole(role domain.Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user := User(c)
+		if user == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized - Please login first")
+		}
+		if !user.HasRole(role) {
+			return fiber.NewError(fiber.StatusForbidden, "Forbidden - This action requires the "+string(role)+" role")
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *domain.User
+		required domain.Role
+		want     int
+	}{
+		{"no user", nil, domain.RoleEditor, fiber.StatusUnauthorized},
+		{"customer for editor", &domain.User{Role: domain.RoleCustomer}, domain.RoleEditor, fiber.StatusForbidden},
+		{"editor for editor", &domain.User{Role: domain.RoleEditor}, domain.RoleEditor, fiber.StatusOK},
+		{"reviewer for editor", &domain.User{Role: domain.RoleReviewer}, domain.RoleEditor, fiber.StatusOK},
+		{"editor for reviewer", &domain.User{Role: domain.RoleEditor}, domain.RoleReviewer, fiber.StatusForbidden},
+		{"reviewer for reviewer", &domain.User{Role: domain.RoleReviewer}, domain.RoleReviewer, fiber.StatusOK},
+		{"reviewer for admin", &domain.User{Role: domain.RoleReviewer}, domain.RoleAdmin, fiber.StatusForbidden},
+		{"admin for reviewer", &domain.User{Role: domain.RoleAdmin}, domain.RoleReviewer, fiber.StatusOK},
+		{"unknown role", &domain.User{Role: domain.Role("owner")}, domain.RoleCustomer, fiber.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				if tt.user != nil {
+					c.Locals("user", tt.user)
+				}
+				return c.Next()
+			})
+			app.Get("/", RequireRole(tt.required), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
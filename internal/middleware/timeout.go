@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TimeoutConfig bounds how long a request may run before its context is
+// cancelled.
+type TimeoutConfig struct {
+	// Default applies to every request without a route timeout. Zero means
+	// no timeout.
+	Default time.Duration
+	// Routes overrides Default per route. Keys are a method and a path
+	// pattern such as "POST /products/:id/images", where ":name" matches one
+	// path segment and a trailing "*" the rest of the path. When several
+	// patterns match, the one with the most literal segments wins. A zero
+	// duration disables the timeout for the route.
+	Routes map[string]time.Duration
+}
+
+type routeTimeout struct {
+	method   string
+	segments []string
+	timeout  time.Duration
+}
+
+// Timeout sets a deadline on the user context of every request, which
+// handlers pass down to the services and repositories. The error of a
+// request that fails after its deadline passed wraps
+// context.DeadlineExceeded, so the error handler can report a timeout even
+// when the adapter that gave up did not wrap it.
+func Timeout(config TimeoutConfig) fiber.Handler {
+	routes := make([]routeTimeout, 0, len(config.Routes))
+	for key, timeout := range config.Routes {
+		method, path, ok := strings.Cut(strings.TrimSpace(key), " ")
+		if !ok {
+			continue
+		}
+		routes = append(routes, routeTimeout{
+			method:   strings.ToUpper(method),
+			segments: pathSegments(strings.TrimSpace(path)),
+			timeout:  timeout,
+		})
+	}
+
+	return func(c *fiber.Ctx) error {
+		timeout := config.Default
+		if route := matchRoute(routes, c.Method(), c.Path()); route != nil {
+			timeout = route.timeout
+		}
+		if timeout <= 0 {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && !errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("%w (%w)", err, context.DeadlineExceeded)
+		}
+		return err
+	}
+}
+
+// matchRoute returns the most specific route matching method and path, or nil.
+func matchRoute(routes []routeTimeout, method, path string) *routeTimeout {
+	segments := pathSegments(path)
+	var best *routeTimeout
+	bestLiterals := -1
+	for i := range routes {
+		route := &routes[i]
+		if route.method != method || !matchSegments(route.segments, segments) {
+			continue
+		}
+		literals := 0
+		for _, segment := range route.segments {
+			if segment != "*" && !strings.HasPrefix(segment, ":") {
+				literals++
+			}
+		}
+		if literals > bestLiterals {
+			best, bestLiterals = route, literals
+		}
+	}
+	return best
+}
+
+func matchSegments(pattern, path []string) bool {
+	for i, segment := range pattern {
+		if segment == "*" && i == len(pattern)-1 {
+			return true
+		}
+		if i >= len(path) {
+			return false
+		}
+		if !strings.HasPrefix(segment, ":") && segment != path[i] {
+			return false
+		}
+	}
+	return len(pattern) == len(path)
+}
+
+func pathSegments(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
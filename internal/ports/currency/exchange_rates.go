@@ -0,0 +1,16 @@
+package currency
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRateNotFound is returned when no rate is known between two currencies.
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// ExchangeRateProvider supplies exchange rates between currencies given as
+// ISO 4217 codes such as "USD" or "THB".
+type ExchangeRateProvider interface {
+	// Rate returns how many units of to one unit of from buys.
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
@@ -0,0 +1,252 @@
+package http
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/dto"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// CartCookie holds the token of a guest cart.
+	CartCookie = "cart_token"
+	// GuestCartLifetime is how long a guest cart is kept after its last change.
+	GuestCartLifetime = 30 * 24 * time.Hour
+)
+
+type CartHandler struct {
+	service   *application.CartService
+	validator *validator.Validate
+}
+
+func NewCartHandler(service *application.CartService) *CartHandler {
+	return &CartHandler{
+		service:   service,
+		validator: newValidator(),
+	}
+}
+
+func (h *CartHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/cart", h.GetCart)
+	app.Delete("/cart", h.ClearCart)
+	app.Post("/cart/items", h.AddItem)
+	app.Put("/cart/items/:itemId", h.UpdateItem)
+	app.Delete("/cart/items/:itemId", h.RemoveItem)
+	app.Post("/cart/reprice", h.RepriceCart)
+	app.Get("/cart/quote", h.QuoteCart)
+}
+
+// cartOwner identifies the cart of the request: the signed in user's cart,
+// otherwise the guest cart of the cart cookie.
+func cartOwner(c *fiber.Ctx) application.CartOwner {
+	if user := middleware.User(c); user != nil {
+		return application.CartOwner{UserID: user.ID}
+	}
+	return application.CartOwner{Token: c.Cookies(CartCookie)}
+}
+
+// setCartCookie hands the token of a new guest cart to the client.
+func setCartCookie(c *fiber.Ctx, cart *domain.Cart) {
+	if cart.Token == nil || *cart.Token == c.Cookies(CartCookie) {
+		return
+	}
+	c.Cookie(&fiber.Cookie{
+		Name:     CartCookie,
+		Value:    *cart.Token,
+		Expires:  time.Now().Add(GuestCartLifetime),
+		HTTPOnly: true,
+		SameSite: "lax",
+	})
+}
+
+// @Summary Get the cart
+// @Description Get the cart of the signed in user, or the guest cart of the cart cookie, with the items whose price changed or that can no longer be bought
+// @Tags cart
+// @Produce json
+// @Success 200 {object} Response{data=domain.Cart}
+// @Router /cart [get]
+func (h *CartHandler) GetCart(c *fiber.Ctx) error {
+	cart, err := h.service.GetCart(c.UserContext(), cartOwner(c))
+	if err != nil {
+		return fail("Failed to get cart", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Cart retrieved successfully",
+		Data:    cart,
+	})
+}
+
+// @Summary Add an item to the cart
+// @Description Add a product, or one of its variants, at its current price. Guests get a cart cookie on their first item.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param item body dto.CartItemRequest true "Item"
+// @Success 200 {object} Response{data=domain.Cart}
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /cart/items [post]
+func (h *CartHandler) AddItem(c *fiber.Ctx) error {
+	var req dto.CartItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	cart, err := h.service.AddItem(c.UserContext(), cartOwner(c), domain.LineItem{
+		ProductID: req.ProductID,
+		VariantID: req.VariantID,
+		Quantity:  req.Quantity,
+	})
+	if err != nil {
+		return fail("Failed to add item to cart", err)
+	}
+
+	setCartCookie(c, cart)
+	return c.JSON(Response{
+		Success: true,
+		Message: "Item added to cart",
+		Data:    cart,
+	})
+}
+
+// @Summary Change the quantity of a cart item
+// @Description Set the quantity of an item in the cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param itemId path int true "Cart item ID"
+// @Param quantity body dto.CartQuantityRequest true "Quantity"
+// @Success 200 {object} Response{data=domain.Cart}
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /cart/items/{itemId} [put]
+func (h *CartHandler) UpdateItem(c *fiber.Ctx) error {
+	itemID, err := strconv.ParseUint(c.Params("itemId"), 10, 32)
+	if err != nil {
+		return fail("Failed to update cart item", invalidParam("itemId", "Invalid cart item ID"))
+	}
+
+	var req dto.CartQuantityRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	cart, err := h.service.UpdateItem(c.UserContext(), cartOwner(c), uint(itemID), req.Quantity)
+	if err != nil {
+		return fail("Failed to update cart item", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Cart item updated",
+		Data:    cart,
+	})
+}
+
+// @Summary Remove a cart item
+// @Description Remove an item from the cart
+// @Tags cart
+// @Produce json
+// @Param itemId path int true "Cart item ID"
+// @Success 200 {object} Response{data=domain.Cart}
+// @Failure 404 {object} ProblemDetails
+// @Router /cart/items/{itemId} [delete]
+func (h *CartHandler) RemoveItem(c *fiber.Ctx) error {
+	itemID, err := strconv.ParseUint(c.Params("itemId"), 10, 32)
+	if err != nil {
+		return fail("Failed to remove cart item", invalidParam("itemId", "Invalid cart item ID"))
+	}
+
+	cart, err := h.service.RemoveItem(c.UserContext(), cartOwner(c), uint(itemID))
+	if err != nil {
+		return fail("Failed to remove cart item", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Cart item removed",
+		Data:    cart,
+	})
+}
+
+// @Summary Empty the cart
+// @Description Remove every item from the cart
+// @Tags cart
+// @Produce json
+// @Success 200 {object} Response{data=domain.Cart}
+// @Router /cart [delete]
+func (h *CartHandler) ClearCart(c *fiber.Ctx) error {
+	cart, err := h.service.Clear(c.UserContext(), cartOwner(c))
+	if err != nil {
+		return fail("Failed to empty cart", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Cart emptied",
+		Data:    cart,
+	})
+}
+
+// @Summary Accept current prices
+// @Description Update every available cart item to its current catalog price
+// @Tags cart
+// @Produce json
+// @Success 200 {object} Response{data=domain.Cart}
+// @Router /cart/reprice [post]
+func (h *CartHandler) RepriceCart(c *fiber.Ctx) error {
+	cart, err := h.service.Reprice(c.UserContext(), cartOwner(c))
+	if err != nil {
+		return fail("Failed to reprice cart", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Cart repriced",
+		Data:    cart,
+	})
+}
+
+// @Summary Price the cart
+// @Description Price the cart after the promotions in effect and an optional coupon, taxed for an optional address and converted to an optional currency
+// @Tags cart
+// @Produce json
+// @Param coupon query string false "Coupon code"
+// @Param country query string false "Tax country, ISO 3166-1 alpha-2"
+// @Param region query string false "Tax region"
+// @Param currency query string false "Currency, ISO 4217"
+// @Success 200 {object} Response{data=domain.PriceQuote}
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /cart/quote [get]
+func (h *CartHandler) QuoteCart(c *fiber.Ctx) error {
+	quote, err := h.service.Quote(c.UserContext(), cartOwner(c), c.Query("coupon"), requestTaxAddress(c), c.Query("currency"))
+	if err != nil {
+		return fail("Failed to price cart", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Cart priced successfully",
+		Data:    quote,
+	})
+}
@@ -0,0 +1,262 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/dto"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CategoryHandler struct {
+	service *application.CategoryService
+}
+
+func NewCategoryHandler(service *application.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		service: service,
+	}
+}
+
+func (h *CategoryHandler) RegisterRoutes(app *fiber.App) {
+	app.Post("/categories", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.CreateCategory)
+	app.Get("/categories", h.GetAllCategories)
+	app.Get("/categories/:id", h.GetCategory)
+	app.Get("/categories/:id/children", h.GetChildren)
+	app.Put("/categories/:id", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.UpdateCategory)
+	app.Delete("/categories/:id", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.DeleteCategory)
+	app.Get("/products/:id/categories", h.GetProductCategories)
+	app.Put("/products/:id/categories", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.SetProductCategories)
+}
+
+// @Summary Create a new category
+// @Description Create a new category, optionally below a parent category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param category body domain.Category true "Category info"
+// @Success 201 {object} Response{data=domain.Category}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Router /categories [post]
+func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
+	var category domain.Category
+	if err := c.BodyParser(&category); err != nil {
+		return fail("Failed to create category", malformed("Invalid request payload"))
+	}
+
+	createdCategory, err := h.service.CreateCategory(c.UserContext(), category.Name, category.Description, category.ParentID)
+	if err != nil {
+		return fail("Failed to create category", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(Response{
+		Success: true,
+		Message: "Category created successfully",
+		Data:    createdCategory,
+	})
+}
+
+// @Summary Get all categories
+// @Description Get a flat list of all categories
+// @Tags categories
+// @Produce json
+// @Success 200 {object} Response{data=[]domain.Category}
+// @Router /categories [get]
+func (h *CategoryHandler) GetAllCategories(c *fiber.Ctx) error {
+	categories, err := h.service.GetAllCategories(c.UserContext())
+	if err != nil {
+		return fail("Failed to get categories", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Categories retrieved successfully",
+		Data:    categories,
+	})
+}
+
+// @Summary Get a category
+// @Description Get a category by its ID
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} Response{data=domain.Category}
+// @Failure 404 {object} ProblemDetails
+// @Router /categories/{id} [get]
+func (h *CategoryHandler) GetCategory(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get category", invalidParam("id", "Invalid category ID"))
+	}
+
+	category, err := h.service.GetCategory(c.UserContext(), uint(id))
+	if err != nil {
+		return fail("Failed to get category", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Category retrieved successfully",
+		Data:    category,
+	})
+}
+
+// @Summary Get category children
+// @Description Get the direct child categories of a category
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} Response{data=[]domain.Category}
+// @Failure 404 {object} ProblemDetails
+// @Router /categories/{id}/children [get]
+func (h *CategoryHandler) GetChildren(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get child categories", invalidParam("id", "Invalid category ID"))
+	}
+
+	children, err := h.service.GetChildren(c.UserContext(), uint(id))
+	if err != nil {
+		return fail("Failed to get child categories", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Child categories retrieved successfully",
+		Data:    children,
+	})
+}
+
+// @Summary Update a category
+// @Description Update a category, including moving it below another parent
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Category ID"
+// @Param category body domain.Category true "Category info"
+// @Success 200 {object} Response{data=domain.Category}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to update category", invalidParam("id", "Invalid category ID"))
+	}
+
+	var category domain.Category
+	if err := c.BodyParser(&category); err != nil {
+		return fail("Failed to update category", malformed("Invalid request payload"))
+	}
+
+	category.ID = uint(id)
+	if err := h.service.UpdateCategory(c.UserContext(), &category); err != nil {
+		return fail("Failed to update category", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Category updated successfully",
+		Data:    category,
+	})
+}
+
+// @Summary Delete a category
+// @Description Delete a category that has no child categories
+// @Tags categories
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Category ID"
+// @Success 200 {object} Response
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to delete category", invalidParam("id", "Invalid category ID"))
+	}
+
+	if err := h.service.DeleteCategory(c.UserContext(), uint(id)); err != nil {
+		return fail("Failed to delete category", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Category deleted successfully",
+		Data:    nil,
+	})
+}
+
+// @Summary Get product categories
+// @Description Get the categories a product belongs to
+// @Tags categories
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} Response{data=[]domain.Category}
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/categories [get]
+func (h *CategoryHandler) GetProductCategories(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get product categories", invalidParam("id", "Invalid product ID"))
+	}
+
+	categories, err := h.service.GetProductCategories(c.UserContext(), uint(id))
+	if err != nil {
+		return fail("Failed to get product categories", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product categories retrieved successfully",
+		Data:    categories,
+	})
+}
+
+// @Summary Set product categories
+// @Description Replace the categories a product belongs to
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param categories body dto.ProductCategoriesRequest true "Category IDs"
+// @Success 200 {object} Response{data=[]domain.Category}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/categories [put]
+func (h *CategoryHandler) SetProductCategories(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to set product categories", invalidParam("id", "Invalid product ID"))
+	}
+
+	var req dto.ProductCategoriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Failed to set product categories", malformed("Invalid request payload"))
+	}
+
+	categories, err := h.service.SetProductCategories(c.UserContext(), uint(id), req.CategoryIDs)
+	if err != nil {
+		return fail("Failed to set product categories", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product categories updated successfully",
+		Data:    categories,
+	})
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"time"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderChangeReason optionally explains a change for the audit records.
+const HeaderChangeReason = "X-Change-Reason"
+
+// requestChange describes the change a request makes: the signed in user as
+// actor, their role and the reason from the X-Change-Reason header.
+func requestChange(c *fiber.Ctx) application.Change {
+	change := application.Change{Reason: c.Get(HeaderChangeReason)}
+	if user := middleware.User(c); user != nil {
+		change.Actor = user.Username
+		change.Role = user.Role
+	}
+	return change
+}
+
+// canSeeUnpublished reports whether the signed in user may see products that
+// are not live, which editors and above can.
+func canSeeUnpublished(c *fiber.Ctx) bool {
+	return middleware.User(c).HasRole(domain.RoleEditor)
+}
+
+// visibleTo reports whether product may be shown to the requester.
+func visibleTo(c *fiber.Ctx, product *domain.Product) bool {
+	return product.IsLive(time.Now()) || canSeeUnpublished(c)
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// requestError is the error a handler returns when a service call fails.
+// Message says what the request was doing; ErrorHandler derives the status
+// code from the cause.
+type requestError struct {
+	message string
+	err     error
+}
+
+func (e *requestError) Error() string {
+	return e.message + ": " + e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// fail reports that a request failed with message because of err.
+func fail(message string, err error) error {
+	return &requestError{message: message, err: err}
+}
+
+// ErrorFormat selects how error responses are written.
+type ErrorFormat string
+
+const (
+	// ProblemFormat writes RFC 9457 problem details as
+	// application/problem+json.
+	ProblemFormat ErrorFormat = "problem"
+	// EnvelopeFormat writes an ErrorResponse, the format of earlier versions
+	// of the API.
+	EnvelopeFormat ErrorFormat = "envelope"
+)
+
+const mimeProblemJSON = "application/problem+json"
+
+// ErrorConfig configures NewErrorHandler.
+type ErrorConfig struct {
+	// Format defaults to ProblemFormat.
+	Format ErrorFormat
+	// TypeBase is prefixed to the problem type of each error kind, as in
+	// "https://api.example.com/problems/". Defaults to "/problems/".
+	TypeBase string
+}
+
+// ProblemDetails describes a failed request as defined by RFC 9457.
+type ProblemDetails struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Errors   []ProblemField `json:"errors,omitempty"`
+}
+
+// ProblemField says which rule the value of a request field broke.
+type ProblemField struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type errorKind struct {
+	status int
+	// problem is the last part of the problem type URI.
+	problem string
+	title   string
+}
+
+// errorKinds maps the application error kinds to HTTP status codes and
+// problem types.
+var errorKinds = map[application.ErrorKind]errorKind{
+	application.KindValidation:   {fiber.StatusBadRequest, "validation-error", "Your request is not valid"},
+	application.KindNotFound:     {fiber.StatusNotFound, "not-found", "The resource was not found"},
+	application.KindConflict:     {fiber.StatusConflict, "conflict", "The request conflicts with the current state"},
+	application.KindUnauthorized: {fiber.StatusUnauthorized, "unauthorized", "Authentication is required"},
+	application.KindForbidden:    {fiber.StatusForbidden, "forbidden", "You are not allowed to do this"},
+	application.KindTooLarge:     {fiber.StatusRequestEntityTooLarge, "too-large", "The upload is too large"},
+	application.KindUnsupported:  {fiber.StatusUnsupportedMediaType, "unsupported-media-type", "The upload format is not supported"},
+}
+
+// NewErrorHandler returns the Fiber error handler for the errors returned by
+// handlers and middleware. Application errors get the status code of their
+// kind; anything else is logged and reported without its details, which are
+// not meant for clients, or as a timeout when the request ran past its
+// deadline.
+func NewErrorHandler(config ErrorConfig) fiber.ErrorHandler {
+	if config.Format == "" {
+		config.Format = ProblemFormat
+	}
+	if config.TypeBase == "" {
+		config.TypeBase = "/problems/"
+	}
+
+	return func(c *fiber.Ctx, err error) error {
+		message, cause := "", err
+		var failed *requestError
+		if errors.As(err, &failed) {
+			message, cause = failed.message, failed.err
+		}
+
+		problem := config.problem(cause)
+		if problem.Status == fiber.StatusInternalServerError && errors.Is(err, context.DeadlineExceeded) {
+			problem.Status = fiber.StatusGatewayTimeout
+			problem.Title = utils.StatusMessage(fiber.StatusGatewayTimeout)
+			problem.Detail = "Request timed out"
+		}
+		if problem.Status >= fiber.StatusInternalServerError {
+			log.Printf("%s %s: %s: %v", c.Method(), c.Path(), message, cause)
+		}
+
+		if config.Format == EnvelopeFormat {
+			if message == "" {
+				message = problem.Detail
+			}
+			return c.Status(problem.Status).JSON(ErrorResponse{
+				Success: false,
+				Message: message,
+				Error:   problem.Detail,
+			})
+		}
+
+		if message != "" {
+			problem.Detail = message + ": " + problem.Detail
+		}
+		problem.Instance = c.Path()
+		return c.Status(problem.Status).JSON(problem, mimeProblemJSON)
+	}
+}
+
+// problem describes err, leaving out the details of unexpected errors.
+func (config ErrorConfig) problem(err error) ProblemDetails {
+	if kind, ok := errorKinds[application.KindOf(err)]; ok {
+		problem := ProblemDetails{
+			Type:   config.TypeBase + kind.problem,
+			Title:  kind.title,
+			Status: kind.status,
+			Detail: err.Error(),
+		}
+		for _, field := range application.FieldsOf(err) {
+			problem.Errors = append(problem.Errors, ProblemField(field))
+		}
+		return problem
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return ProblemDetails{
+			Type:   "about:blank",
+			Title:  utils.StatusMessage(fiberErr.Code),
+			Status: fiberErr.Code,
+			Detail: fiberErr.Message,
+		}
+	}
+	return ProblemDetails{
+		Type:   "about:blank",
+		Title:  utils.StatusMessage(fiber.StatusInternalServerError),
+		Status: fiber.StatusInternalServerError,
+		Detail: "Internal server error",
+	}
+}
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/dto"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type InventoryHandler struct {
+	service   *application.InventoryService
+	validator *validator.Validate
+}
+
+func NewInventoryHandler(service *application.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		service:   service,
+		validator: newValidator(),
+	}
+}
+
+func (h *InventoryHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/products/:id/stock", h.GetAvailability)
+
+	// Reservations are made and settled by checkout and order status
+	// changes only; staff manage on hand stock
+	app.Get("/inventory/low-stock", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.GetLowStock)
+	app.Get("/products/:id/stock/movements", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.GetMovements)
+	app.Post("/products/:id/stock/adjustments", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.Adjust)
+	app.Put("/products/:id/stock/threshold", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.SetThreshold)
+}
+
+// @Summary Get product availability
+// @Description Get the stock of a product in every warehouse along with totals
+// @Tags inventory
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} Response{data=domain.StockAvailability}
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/stock [get]
+func (h *InventoryHandler) GetAvailability(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get stock", invalidParam("id", "Invalid product ID"))
+	}
+
+	availability, err := h.service.GetAvailability(c.UserContext(), uint(id))
+	if err != nil {
+		return fail("Failed to get stock", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Stock retrieved successfully",
+		Data:    availability,
+	})
+}
+
+// @Summary Get low stock levels
+// @Description Get every stock level whose available quantity is at or below its threshold
+// @Tags inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} Response{data=[]domain.StockLevel}
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Router /inventory/low-stock [get]
+func (h *InventoryHandler) GetLowStock(c *fiber.Ctx) error {
+	levels, err := h.service.GetLowStock(c.UserContext())
+	if err != nil {
+		return fail("Failed to get low stock", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Low stock retrieved successfully",
+		Data:    levels,
+	})
+}
+
+// @Summary Get stock movements
+// @Description Get the most recent stock ledger entries of a product
+// @Tags inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param limit query int false "Maximum number of movements" default(50)
+// @Success 200 {object} Response{data=[]domain.StockMovement}
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/stock/movements [get]
+func (h *InventoryHandler) GetMovements(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get stock movements", invalidParam("id", "Invalid product ID"))
+	}
+
+	movements, err := h.service.GetMovements(c.UserContext(), uint(id), c.QueryInt("limit"))
+	if err != nil {
+		return fail("Failed to get stock movements", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Stock movements retrieved successfully",
+		Data:    movements,
+	})
+}
+
+// @Summary Adjust stock
+// @Description Add to or remove from the on hand stock of a product
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param adjustment body dto.StockAdjustmentRequest true "Stock adjustment"
+// @Success 200 {object} Response{data=domain.StockLevel}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /products/{id}/stock/adjustments [post]
+func (h *InventoryHandler) Adjust(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to adjust stock", invalidParam("id", "Invalid product ID"))
+	}
+
+	var req dto.StockAdjustmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	level, err := h.service.Adjust(c.UserContext(), uint(id), req.Warehouse, req.Quantity, req.Reason)
+	if err != nil {
+		return fail("Failed to adjust stock", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Stock adjusted successfully",
+		Data:    level,
+	})
+}
+
+// @Summary Set low stock threshold
+// @Description Set the available quantity at or below which a product is reported as low stock
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param threshold body dto.StockThresholdRequest true "Low stock threshold"
+// @Success 200 {object} Response{data=domain.StockLevel}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/stock/threshold [put]
+func (h *InventoryHandler) SetThreshold(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to set low stock threshold", invalidParam("id", "Invalid product ID"))
+	}
+
+	var req dto.StockThresholdRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	level, err := h.service.SetThreshold(c.UserContext(), uint(id), req.Warehouse, req.Threshold)
+	if err != nil {
+		return fail("Failed to set low stock threshold", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Low stock threshold updated successfully",
+		Data:    level,
+	})
+}
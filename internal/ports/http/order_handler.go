@@ -0,0 +1,258 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/dto"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrderHandler struct {
+	service   *application.OrderService
+	validator *validator.Validate
+}
+
+func NewOrderHandler(service *application.OrderService) *OrderHandler {
+	return &OrderHandler{
+		service:   service,
+		validator: newValidator(),
+	}
+}
+
+func (h *OrderHandler) RegisterRoutes(app *fiber.App) {
+	app.Post("/orders", middleware.Auth(), h.Checkout)
+	app.Get("/users/me/orders", middleware.Auth(), h.GetMyOrders)
+	app.Get("/orders", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.GetOrders)
+	app.Get("/orders/:id", middleware.Auth(), h.GetOrder)
+	app.Post("/orders/:id/cancel", middleware.Auth(), h.CancelOrder)
+	app.Put("/orders/:id/status", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.SetOrderStatus)
+}
+
+// @Summary Place an order
+// @Description Check out the cart of the signed in user, taxed for the given address and priced in the given currency: stock is reserved for every item and the cart is emptied. Fails when a cart item changed price or is no longer available.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param checkout body dto.CheckoutRequest false "Coupon to apply, tax address and currency"
+// @Success 201 {object} Response{data=domain.Order}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /orders [post]
+func (h *OrderHandler) Checkout(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to place order", application.ErrInvalidToken)
+	}
+
+	var req dto.CheckoutRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fail("Invalid request format", malformed(err.Error()))
+		}
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	order, err := h.service.Checkout(c.UserContext(), user, req.Coupon, req.ToTaxAddress(), req.Currency)
+	if err != nil {
+		return fail("Failed to place order", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(Response{
+		Success: true,
+		Message: "Order placed successfully",
+		Data:    order,
+	})
+}
+
+// @Summary Get my orders
+// @Description Get the orders of the signed in user, newest first
+// @Tags orders
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} Response{data=[]domain.Order}
+// @Failure 401 {object} ProblemDetails
+// @Router /users/me/orders [get]
+func (h *OrderHandler) GetMyOrders(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to get orders", application.ErrInvalidToken)
+	}
+
+	orders, err := h.service.GetUserOrders(c.UserContext(), user.ID)
+	if err != nil {
+		return fail("Failed to get orders", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Orders retrieved successfully",
+		Data:    orders,
+	})
+}
+
+// @Summary List orders
+// @Description Get every order, newest first, optionally in one status
+// @Tags orders
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Order status" Enums(pending, paid, fulfilled, shipped, delivered, cancelled, refunded)
+// @Success 200 {object} Response{data=[]domain.Order}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Router /orders [get]
+func (h *OrderHandler) GetOrders(c *fiber.Ctx) error {
+	status := domain.OrderStatus(strings.ToLower(c.Query("status")))
+	orders, err := h.service.GetOrders(c.UserContext(), status)
+	if err != nil {
+		return fail("Failed to get orders", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Orders retrieved successfully",
+		Data:    orders,
+	})
+}
+
+// @Summary Get an order
+// @Description Get an order with its items and status history. Users see their own orders, admins any order.
+// @Tags orders
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} Response{data=domain.Order}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /orders/{id} [get]
+func (h *OrderHandler) GetOrder(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to get order", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get order", invalidParam("id", "Invalid order ID"))
+	}
+
+	order, err := h.service.GetOrder(c.UserContext(), uint(id), user)
+	if err != nil {
+		return fail("Failed to get order", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Order retrieved successfully",
+		Data:    order,
+	})
+}
+
+// @Summary Cancel an order
+// @Description Cancel a pending order and release its stock. Users cancel their own orders, admins any order.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param cancel body dto.OrderCancelRequest false "Reason for the cancellation"
+// @Success 200 {object} Response{data=domain.Order}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /orders/{id}/cancel [post]
+func (h *OrderHandler) CancelOrder(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to cancel order", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to cancel order", invalidParam("id", "Invalid order ID"))
+	}
+
+	var req dto.OrderCancelRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fail("Invalid request format", malformed(err.Error()))
+		}
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	order, err := h.service.Cancel(c.UserContext(), uint(id), user, req.Reason)
+	if err != nil {
+		return fail("Failed to cancel order", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Order cancelled",
+		Data:    order,
+	})
+}
+
+// @Summary Change the status of an order
+// @Description Move an order along its lifecycle: pending, paid, fulfilled, shipped, delivered, with cancelled and refunded as exits. Fulfilling an order removes its stock; cancelling or refunding it before fulfilment releases it.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param status body dto.OrderStatusRequest true "New status"
+// @Success 200 {object} Response{data=domain.Order}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /orders/{id}/status [put]
+func (h *OrderHandler) SetOrderStatus(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to change order status", invalidParam("id", "Invalid order ID"))
+	}
+
+	var req dto.OrderStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	change := requestChange(c)
+	if req.Note != "" {
+		change.Reason = req.Note
+	}
+	order, err := h.service.SetStatus(c.UserContext(), uint(id), domain.OrderStatus(req.Status), change)
+	if err != nil {
+		return fail("Failed to change order status", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Order status changed to " + string(order.Status),
+		Data:    order,
+	})
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"encoding/json"
+	"mime"
+
+	"github.com/euro1061/gohex/internal/application"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	MIMEMergePatchJSON = "application/merge-patch+json"
+	MIMEJSONPatchJSON  = "application/json-patch+json"
+)
+
+var (
+	errUnsupportedPatchType error = &application.Error{
+		Kind:    application.KindUnsupported,
+		Message: "unsupported patch media type, use " + MIMEMergePatchJSON + " or " + MIMEJSONPatchJSON,
+	}
+	errInvalidPatchDocument error = &application.Error{
+		Kind:    application.KindValidation,
+		Message: "invalid patch document",
+	}
+)
+
+// applyPatch applies the request body to current according to the request
+// Content-Type and decodes the patched document into target.
+// Merge patches follow RFC 7396, JSON patches follow RFC 6902.
+func applyPatch(c *fiber.Ctx, current interface{}, target interface{}) error {
+	mediaType, _, err := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	if err != nil {
+		return errUnsupportedPatchType
+	}
+
+	original, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch mediaType {
+	case MIMEMergePatchJSON:
+		patched, err = jsonpatch.MergePatch(original, c.Body())
+		if err != nil {
+			return errInvalidPatchDocument
+		}
+	case MIMEJSONPatchJSON:
+		patch, err := jsonpatch.DecodePatch(c.Body())
+		if err != nil {
+			return errInvalidPatchDocument
+		}
+		patched, err = patch.Apply(original)
+		if err != nil {
+			return errInvalidPatchDocument
+		}
+	default:
+		return errUnsupportedPatchType
+	}
+
+	if err := json.Unmarshal(patched, target); err != nil {
+		return errInvalidPatchDocument
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/dto"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderPaymentSignature carries the signature of a payment webhook.
+const HeaderPaymentSignature = "X-Payment-Signature"
+
+type PaymentHandler struct {
+	service   *application.PaymentService
+	validator *validator.Validate
+}
+
+func NewPaymentHandler(service *application.PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		service:   service,
+		validator: newValidator(),
+	}
+}
+
+func (h *PaymentHandler) RegisterRoutes(app *fiber.App) {
+	app.Post("/orders/:id/payment", middleware.Auth(), h.StartPayment)
+	app.Get("/orders/:id/payments", middleware.Auth(), h.GetPayments)
+	app.Post("/orders/:id/refund", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.RefundOrder)
+	app.Post("/payments/webhook", h.Webhook)
+}
+
+// @Summary Pay for an order
+// @Description Start paying for a pending order. The payment carries the client secret to complete it with the payment provider; an order with a payment in progress gets that payment back.
+// @Tags payments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Success 201 {object} Response{data=domain.Payment}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /orders/{id}/payment [post]
+func (h *PaymentHandler) StartPayment(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to start payment", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to start payment", invalidParam("id", "Invalid order ID"))
+	}
+
+	p, err := h.service.StartPayment(c.UserContext(), uint(id), user)
+	if err != nil {
+		return fail("Failed to start payment", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(Response{
+		Success: true,
+		Message: "Payment started",
+		Data:    p,
+	})
+}
+
+// @Summary Get the payments of an order
+// @Description Get the payment attempts of an order, newest first. Users see their own orders, admins any order.
+// @Tags payments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} Response{data=[]domain.Payment}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /orders/{id}/payments [get]
+func (h *PaymentHandler) GetPayments(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to get payments", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get payments", invalidParam("id", "Invalid order ID"))
+	}
+
+	payments, err := h.service.GetPayments(c.UserContext(), uint(id), user)
+	if err != nil {
+		return fail("Failed to get payments", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Payments retrieved successfully",
+		Data:    payments,
+	})
+}
+
+// @Summary Refund an order
+// @Description Refund the captured payment of an order through the payment provider and mark the order refunded
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param refund body dto.RefundRequest false "Reason for the refund"
+// @Success 200 {object} Response{data=domain.Order}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /orders/{id}/refund [post]
+func (h *PaymentHandler) RefundOrder(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to refund order", invalidParam("id", "Invalid order ID"))
+	}
+
+	var req dto.RefundRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fail("Invalid request format", malformed(err.Error()))
+		}
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	change := requestChange(c)
+	if req.Reason != "" {
+		change.Reason = req.Reason
+	}
+	order, err := h.service.Refund(c.UserContext(), uint(id), change)
+	if err != nil {
+		return fail("Failed to refund order", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Order refunded",
+		Data:    order,
+	})
+}
+
+// @Summary Payment provider webhook
+// @Description Receives payment events from the payment provider. The body must be signed in the X-Payment-Signature header. Redelivered events are acknowledged without being applied again.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param X-Payment-Signature header string true "Webhook signature"
+// @Success 200 {object} Response
+// @Failure 400 {object} ProblemDetails
+// @Router /payments/webhook [post]
+func (h *PaymentHandler) Webhook(c *fiber.Ctx) error {
+	if err := h.service.HandleWebhook(c.UserContext(), c.Body(), c.Get(HeaderPaymentSignature)); err != nil {
+		// The provider retries deliveries that fail with a server error
+		return fail("Failed to handle payment event", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Payment event handled",
+	})
+}
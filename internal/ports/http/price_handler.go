@@ -0,0 +1,174 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/dto"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PriceHandler struct {
+	products  *application.ProductService
+	schedules *application.PriceScheduleService
+	validator *validator.Validate
+}
+
+func NewPriceHandler(products *application.ProductService, schedules *application.PriceScheduleService) *PriceHandler {
+	return &PriceHandler{
+		products:  products,
+		schedules: schedules,
+		validator: newValidator(),
+	}
+}
+
+func (h *PriceHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/products/:id/price-history", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.GetPriceHistory)
+	app.Get("/products/:id/price-schedules", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.GetSchedules)
+	app.Post("/products/:id/price-schedules", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.CreateSchedule)
+	app.Delete("/products/:id/price-schedules/:scheduleId", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.CancelSchedule)
+}
+
+// @Summary Get product price history
+// @Description Get the most recent price changes of a product, newest first
+// @Tags prices
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param limit query int false "Maximum number of changes" default(200)
+// @Success 200 {object} Response{data=[]domain.PriceChange}
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/price-history [get]
+func (h *PriceHandler) GetPriceHistory(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get price history", invalidParam("id", "Invalid product ID"))
+	}
+
+	changes, err := h.products.GetPriceHistory(c.UserContext(), uint(id), c.QueryInt("limit"))
+	if err != nil {
+		return fail("Failed to get price history", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Price history retrieved successfully",
+		Data:    changes,
+	})
+}
+
+// @Summary Get scheduled prices
+// @Description Get every price schedule of a product ordered by start time
+// @Tags prices
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} Response{data=[]domain.PriceSchedule}
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/price-schedules [get]
+func (h *PriceHandler) GetSchedules(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get price schedules", invalidParam("id", "Invalid product ID"))
+	}
+
+	schedules, err := h.schedules.GetSchedules(c.UserContext(), uint(id))
+	if err != nil {
+		return fail("Failed to get price schedules", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Price schedules retrieved successfully",
+		Data:    schedules,
+	})
+}
+
+// @Summary Schedule a price change
+// @Description Schedule a new price for a product. With ends_at it is a sale window after which the previous price is restored; without it the change is permanent.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param schedule body dto.PriceScheduleRequest true "Scheduled price"
+// @Success 201 {object} Response{data=domain.PriceSchedule}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /products/{id}/price-schedules [post]
+func (h *PriceHandler) CreateSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to schedule price", invalidParam("id", "Invalid product ID"))
+	}
+
+	var req dto.PriceScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	change := requestChange(c)
+	change.Reason = req.Reason
+	schedule, err := h.schedules.Schedule(c.UserContext(), uint(id), req.Price, req.StartsAt, req.EndsAt, change)
+	if err != nil {
+		return fail("Failed to schedule price", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(Response{
+		Success: true,
+		Message: "Price scheduled successfully",
+		Data:    schedule,
+	})
+}
+
+// @Summary Cancel a scheduled price
+// @Description Cancel a price schedule; an active sale window ends immediately and the previous price is restored
+// @Tags prices
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param scheduleId path int true "Schedule ID"
+// @Param X-Change-Reason header string false "Reason recorded in the price history"
+// @Success 200 {object} Response{data=domain.PriceSchedule}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /products/{id}/price-schedules/{scheduleId} [delete]
+func (h *PriceHandler) CancelSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to cancel price schedule", invalidParam("id", "Invalid product ID"))
+	}
+	scheduleID, err := strconv.ParseUint(c.Params("scheduleId"), 10, 32)
+	if err != nil {
+		return fail("Failed to cancel price schedule", invalidParam("scheduleId", "Invalid schedule ID"))
+	}
+
+	schedule, err := h.schedules.Cancel(c.UserContext(), uint(id), uint(scheduleID), requestChange(c))
+	if err != nil {
+		return fail("Failed to cancel price schedule", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Price schedule cancelled successfully",
+		Data:    schedule,
+	})
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/dto"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PricingHandler struct {
+	service   *application.PricingService
+	validator *validator.Validate
+}
+
+func NewPricingHandler(service *application.PricingService) *PricingHandler {
+	return &PricingHandler{
+		service:   service,
+		validator: newValidator(),
+	}
+}
+
+func (h *PricingHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/products/:id/price", h.GetProductPrice)
+	app.Post("/pricing/quote", h.QuoteItems)
+}
+
+// requestUserID returns the ID of the signed in user, or zero.
+func requestUserID(c *fiber.Ctx) uint {
+	if user := middleware.User(c); user != nil {
+		return user.ID
+	}
+	return 0
+}
+
+// requestTaxAddress reads the tax address of a quote from the country and
+// region query parameters.
+func requestTaxAddress(c *fiber.Ctx) domain.TaxAddress {
+	return domain.TaxAddress{Country: c.Query("country"), Region: c.Query("region")}
+}
+
+// @Summary Get the effective price of a product
+// @Description Price a product, or one of its variants, after the promotions in effect and an optional coupon, taxed for an optional address and converted to an optional currency
+// @Tags pricing
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variant_id query int false "Variant ID"
+// @Param quantity query int false "Quantity" default(1)
+// @Param coupon query string false "Coupon code"
+// @Param country query string false "Tax country, ISO 3166-1 alpha-2"
+// @Param region query string false "Tax region"
+// @Param currency query string false "Currency, ISO 4217"
+// @Success 200 {object} Response{data=domain.PriceQuote}
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /products/{id}/price [get]
+func (h *PricingHandler) GetProductPrice(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to price product", invalidParam("id", "Invalid product ID"))
+	}
+
+	var variantID *uint
+	if value := c.Query("variant_id"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return fail("Failed to price product", invalidParam("variant_id", "Invalid variant ID"))
+		}
+		id := uint(parsed)
+		variantID = &id
+	}
+
+	quote, err := h.service.PriceProduct(c.UserContext(), uint(id), variantID, c.QueryInt("quantity", 1), c.Query("coupon"), requestUserID(c), requestTaxAddress(c), c.Query("currency"))
+	if err != nil {
+		return fail("Failed to price product", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product priced successfully",
+		Data:    quote,
+	})
+}
+
+// @Summary Price a set of items
+// @Description Price line items, such as a cart, after the promotions in effect and an optional coupon, with a breakdown of the promotions that applied and the tax for an optional address, in an optional currency
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param quote body dto.PriceQuoteRequest true "Items to price"
+// @Success 200 {object} Response{data=domain.PriceQuote}
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /pricing/quote [post]
+func (h *PricingHandler) QuoteItems(c *fiber.Ctx) error {
+	var req dto.PriceQuoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	quote, err := h.service.PriceItems(c.UserContext(), req.ToLineItems(), req.Coupon, requestUserID(c), req.ToTaxAddress(), req.Currency)
+	if err != nil {
+		return fail("Failed to price items", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Items priced successfully",
+		Data:    quote,
+	})
+}
@@ -1,12 +1,19 @@
 package http
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"log"
+	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/euro1061/gohex/internal/application"
 	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/dto"
 	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -23,21 +30,53 @@ type ErrorResponse struct {
 }
 
 type ProductHandler struct {
-	service *application.ProductService
+	service    *application.ProductService
+	currencies *application.CurrencyService
+	wishlists  *application.WishlistService
+	validator  *validator.Validate
 }
 
-func NewProductHandler(service *application.ProductService) *ProductHandler {
+func NewProductHandler(service *application.ProductService, currencies *application.CurrencyService, wishlists *application.WishlistService) *ProductHandler {
 	return &ProductHandler{
-		service: service,
+		service:    service,
+		currencies: currencies,
+		wishlists:  wishlists,
+		validator:  newValidator(),
 	}
 }
 
 func (h *ProductHandler) RegisterRoutes(app *fiber.App) {
-	app.Post("/products", middleware.Auth(), h.CreateProduct)
+	app.Post("/products", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.CreateProduct)
 	app.Get("/products", h.GetAllProducts)
+	app.Get("/products/facets", h.GetFacets)
+	app.Get("/products/export", middleware.Auth(), h.ExportProducts)
+	app.Get("/products/by-sku/:sku", h.GetProductBySKU)
+	app.Get("/products/by-slug/:slug", h.GetProductBySlug)
 	app.Get("/products/:id", h.GetProduct)
-	app.Put("/products/:id", middleware.Auth(), h.UpdateProduct)
-	app.Delete("/products/:id", middleware.Auth(), h.DeleteProduct)
+	app.Get("/categories/:id/products", h.GetCategoryProducts)
+	app.Put("/products/:id", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.UpdateProduct)
+	app.Patch("/products/:id", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.PatchProduct)
+	app.Delete("/products/:id", middleware.Auth(), middleware.RequireRole(domain.RoleReviewer), h.DeleteProduct)
+	app.Post("/products/:id/variants/generate", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.GenerateVariants)
+	app.Post("/products/:id/submit", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.Transition(domain.ProductSubmit))
+	app.Post("/products/:id/approve", middleware.Auth(), middleware.RequireRole(domain.RoleReviewer), h.Transition(domain.ProductApprove))
+	app.Post("/products/:id/reject", middleware.Auth(), middleware.RequireRole(domain.RoleReviewer), h.Transition(domain.ProductReject))
+	app.Post("/products/:id/unpublish", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.Transition(domain.ProductUnpublish))
+	app.Post("/products/:id/archive", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.Transition(domain.ProductArchive))
+	app.Post("/products/:id/restore", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.Transition(domain.ProductRestore))
+}
+
+// present prepares products for the requester: prices are shown in the
+// currency query parameter, when one is given, and signed in users see
+// which products they favorited.
+func (h *ProductHandler) present(c *fiber.Ctx, products ...*domain.Product) error {
+	if err := h.currencies.LocalizeProducts(c.UserContext(), c.Query("currency"), products...); err != nil {
+		return err
+	}
+	if user := middleware.User(c); user != nil {
+		return h.wishlists.MarkFavorites(c.UserContext(), user.ID, products...)
+	}
+	return nil
 }
 
 // @Summary Create a new product
@@ -46,28 +85,27 @@ func (h *ProductHandler) RegisterRoutes(app *fiber.App) {
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
-// @Param product body domain.Product true "Product info"
+// @Param product body dto.ProductRequest true "Product info"
 // @Success 201 {object} Response{data=domain.Product}
-// @Failure 401 {object} ErrorResponse
-// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 400 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
 // @Router /products [post]
 func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
-	var product domain.Product
-	if err := c.BodyParser(&product); err != nil {
-		return c.Status(400).JSON(ErrorResponse{
-			Success: false,
-			Message: "Failed to create product",
-			Error:   "Invalid request payload",
-		})
+	var req dto.ProductRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Failed to create product", malformed("Invalid request payload"))
 	}
 
-	createdProduct, err := h.service.CreateProduct(product.Name, product.Description, product.Price)
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	createdProduct, err := h.service.CreateProduct(c.UserContext(), req.ToProduct(), requestChange(c))
 	if err != nil {
-		return c.Status(500).JSON(ErrorResponse{
-			Success: false,
-			Message: "Failed to create product",
-			Error:   err.Error(),
-		})
+		return fail("Failed to create product", err)
 	}
 
 	return c.Status(201).JSON(Response{
@@ -78,19 +116,29 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 }
 
 // @Summary Get all products
-// @Description Get a list of all products
+// @Description Get a list of live products, optionally filtered by tags, category and price in the base currency. Editors also see unpublished products and can filter them by status.
 // @Tags products
 // @Produce json
+// @Param tags query string false "Comma separated tags"
+// @Param tag_match query string false "Match any or all tags" Enums(any, all)
+// @Param category_id query int false "Category ID"
+// @Param include_descendants query bool false "Include products of descendant categories"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param status query string false "Comma separated lifecycle statuses (editors only)"
+// @Param currency query string false "Show prices in this currency, ISO 4217"
 // @Success 200 {object} Response{data=[]domain.Product}
+// @Failure 400 {object} ProblemDetails
 // @Router /products [get]
 func (h *ProductHandler) GetAllProducts(c *fiber.Ctx) error {
-	products, err := h.service.GetAllProducts()
+	query, err := parseProductQuery(c)
 	if err != nil {
-		return c.Status(500).JSON(ErrorResponse{
-			Success: false,
-			Message: "Failed to get products",
-			Error:   err.Error(),
-		})
+		return fail("Failed to get products", err)
+	}
+
+	products, err := h.findProducts(c, query)
+	if err != nil {
+		return fail("Failed to get products", err)
 	}
 
 	return c.JSON(Response{
@@ -100,31 +148,243 @@ func (h *ProductHandler) GetAllProducts(c *fiber.Ctx) error {
 	})
 }
 
+// @Summary Get category products
+// @Description Get the live products in a category, optionally including all descendant categories. Takes the filters of the product listing.
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param include_descendants query bool false "Include products of descendant categories"
+// @Param tags query string false "Comma separated tags"
+// @Param tag_match query string false "Match any or all tags" Enums(any, all)
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param status query string false "Comma separated lifecycle statuses (editors only)"
+// @Param currency query string false "Show prices in this currency, ISO 4217"
+// @Success 200 {object} Response{data=[]domain.Product}
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /categories/{id}/products [get]
+func (h *ProductHandler) GetCategoryProducts(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get category products", invalidParam("id", "Invalid category ID"))
+	}
+
+	query, err := parseProductQuery(c)
+	if err != nil {
+		return fail("Failed to get category products", err)
+	}
+	categoryID := uint(id)
+	query.CategoryID = &categoryID
+
+	products, err := h.findProducts(c, query)
+	if err != nil {
+		return fail("Failed to get category products", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Category products retrieved successfully",
+		Data:    products,
+	})
+}
+
+// findProducts lists the products matching query and prepares them for the
+// requester.
+func (h *ProductHandler) findProducts(c *fiber.Ctx, query application.ProductQuery) ([]domain.Product, error) {
+	products, err := h.service.FindProducts(c.UserContext(), query)
+	if err != nil {
+		return nil, err
+	}
+	listed := make([]*domain.Product, len(products))
+	for i := range products {
+		listed[i] = &products[i]
+	}
+	if err := h.present(c, listed...); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// @Summary Get product facets
+// @Description Count the products matching the listing filters per tag, category and price bucket
+// @Tags products
+// @Produce json
+// @Param tags query string false "Comma separated tags"
+// @Param tag_match query string false "Match any or all tags" Enums(any, all)
+// @Param category_id query int false "Category ID"
+// @Param include_descendants query bool false "Include products of descendant categories"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param price_buckets query string false "Comma separated ascending bucket upper bounds"
+// @Success 200 {object} Response{data=domain.ProductFacets}
+// @Failure 400 {object} ProblemDetails
+// @Router /products/facets [get]
+func (h *ProductHandler) GetFacets(c *fiber.Ctx) error {
+	query, err := parseProductQuery(c)
+	if err != nil {
+		return fail("Failed to get product facets", err)
+	}
+
+	var buckets []float64
+	for _, value := range splitQueryList(c.Query("price_buckets")) {
+		bound, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fail("Failed to get product facets", invalidParam("price_buckets", "Invalid price_buckets"))
+		}
+		buckets = append(buckets, bound)
+	}
+
+	facets, err := h.service.GetFacets(c.UserContext(), query, buckets)
+	if err != nil {
+		return fail("Failed to get product facets", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product facets retrieved successfully",
+		Data:    facets,
+	})
+}
+
+// @Summary Export products
+// @Description Download the products matching the listing filters as CSV, NDJSON or XLSX. The file is streamed as it is read from the database.
+// @Tags products
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security ApiKeyAuth
+// @Param format query string false "Export format" Enums(csv, ndjson, xlsx) default(csv)
+// @Param tags query string false "Comma separated tags"
+// @Param tag_match query string false "Match any or all tags" Enums(any, all)
+// @Param category_id query int false "Category ID"
+// @Param include_descendants query bool false "Include products of descendant categories"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Success 200 {file} file
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Router /products/export [get]
+func (h *ProductHandler) ExportProducts(c *fiber.Ctx) error {
+	query, err := parseProductQuery(c)
+	if err != nil {
+		return fail("Failed to export products", err)
+	}
+
+	format := domain.ExportFormat(c.Query("format", string(domain.ExportFormatCSV)))
+	export, err := h.service.Export(c.UserContext(), format, query)
+	if err != nil {
+		return fail("Failed to export products", err)
+	}
+
+	c.Attachment("products-" + time.Now().Format("20060102-150405") + "." + export.Extension())
+	c.Set(fiber.HeaderContentType, export.ContentType())
+	// The body is streamed after the handler returns, when the request
+	// context has already been cancelled
+	ctx := context.WithoutCancel(c.UserContext())
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// The status line has already been sent, so a failure can only
+		// be logged and the download ends early
+		if err := export.Write(ctx, w); err != nil {
+			log.Printf("error exporting products: %v", err)
+		}
+		w.Flush()
+	})
+	return nil
+}
+
 // @Summary Get a product
 // @Description Get a product by its ID
 // @Tags products
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param currency query string false "Show prices in this currency, ISO 4217"
 // @Success 200 {object} Response{data=domain.Product}
-// @Failure 404 {object} ErrorResponse
+// @Failure 404 {object} ProblemDetails
 // @Router /products/{id} [get]
 func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
-		return c.Status(400).JSON(ErrorResponse{
-			Success: false,
-			Message: "Failed to get product",
-			Error:   "Invalid product ID",
-		})
+		return fail("Failed to get product", invalidParam("id", "Invalid product ID"))
 	}
 
-	product, err := h.service.GetProduct(uint(id))
+	product, err := h.service.GetProduct(c.UserContext(), uint(id))
 	if err != nil {
-		return c.Status(404).JSON(ErrorResponse{
-			Success: false,
-			Message: "Failed to get product",
-			Error:   "Product not found",
-		})
+		return fail("Failed to get product", err)
+	}
+	if !visibleTo(c, product) {
+		return fail("Failed to get product", application.ErrProductNotFound)
+	}
+	if err := h.present(c, product); err != nil {
+		return fail("Failed to get product", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product retrieved successfully",
+		Data:    product,
+	})
+}
+
+// @Summary Get a product by SKU
+// @Description Get a product by its stock keeping unit
+// @Tags products
+// @Produce json
+// @Param sku path string true "Product SKU"
+// @Param currency query string false "Show prices in this currency, ISO 4217"
+// @Success 200 {object} Response{data=domain.Product}
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/by-sku/{sku} [get]
+func (h *ProductHandler) GetProductBySKU(c *fiber.Ctx) error {
+	sku, err := url.PathUnescape(c.Params("sku"))
+	if err != nil {
+		return fail("Failed to get product", invalidParam("sku", "Invalid product SKU"))
+	}
+
+	product, err := h.service.GetProductBySKU(c.UserContext(), sku)
+	if err != nil {
+		return fail("Failed to get product", err)
+	}
+	if !visibleTo(c, product) {
+		return fail("Failed to get product", application.ErrProductNotFound)
+	}
+	if err := h.present(c, product); err != nil {
+		return fail("Failed to get product", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product retrieved successfully",
+		Data:    product,
+	})
+}
+
+// @Summary Get a product by slug
+// @Description Get a product by its URL slug
+// @Tags products
+// @Produce json
+// @Param slug path string true "Product slug"
+// @Param currency query string false "Show prices in this currency, ISO 4217"
+// @Success 200 {object} Response{data=domain.Product}
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/by-slug/{slug} [get]
+func (h *ProductHandler) GetProductBySlug(c *fiber.Ctx) error {
+	slug, err := url.PathUnescape(c.Params("slug"))
+	if err != nil {
+		return fail("Failed to get product", invalidParam("slug", "Invalid product slug"))
+	}
+
+	product, err := h.service.GetProductBySlug(c.UserContext(), slug)
+	if err != nil {
+		return fail("Failed to get product", err)
+	}
+	if !visibleTo(c, product) {
+		return fail("Failed to get product", application.ErrProductNotFound)
+	}
+	if err := h.present(c, product); err != nil {
+		return fail("Failed to get product", err)
 	}
 
 	return c.JSON(Response{
@@ -141,37 +401,87 @@ func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
 // @Produce json
 // @Security ApiKeyAuth
 // @Param id path int true "Product ID"
-// @Param product body domain.Product true "Product info"
+// @Param product body dto.ProductRequest true "Product info"
+// @Param X-Change-Reason header string false "Reason recorded in the price history"
 // @Success 200 {object} Response{data=domain.Product}
-// @Failure 401 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
 // @Router /products/{id} [put]
 func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
-		return c.Status(400).JSON(ErrorResponse{
-			Success: false,
-			Message: "Failed to update product",
-			Error:   "Invalid product ID",
-		})
+		return fail("Failed to update product", invalidParam("id", "Invalid product ID"))
 	}
 
-	var product domain.Product
-	if err := json.Unmarshal(c.Body(), &product); err != nil {
-		return c.Status(400).JSON(ErrorResponse{
-			Success: false,
-			Message: "Failed to update product",
-			Error:   "Invalid request payload",
-		})
+	var req dto.ProductRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return fail("Failed to update product", malformed("Invalid request payload"))
 	}
 
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	product := req.ToProduct()
 	product.ID = uint(id)
-	if err := h.service.UpdateProduct(&product); err != nil {
-		return c.Status(500).JSON(ErrorResponse{
-			Success: false,
-			Message: "Failed to update product",
-			Error:   err.Error(),
-		})
+	if err := h.service.UpdateProduct(c.UserContext(), product, requestChange(c)); err != nil {
+		return fail("Failed to update product", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product updated successfully",
+		Data:    product,
+	})
+}
+
+// @Summary Partially update a product
+// @Description Apply a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) to the writable fields of a product, as accepted by PUT
+// @Tags products
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param patch body object true "Merge patch object or JSON patch operations"
+// @Param X-Change-Reason header string false "Reason recorded in the price history"
+// @Success 200 {object} Response{data=domain.Product}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Failure 415 {object} ProblemDetails
+// @Router /products/{id} [patch]
+func (h *ProductHandler) PatchProduct(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to update product", invalidParam("id", "Invalid product ID"))
+	}
+
+	current, err := h.service.GetProduct(c.UserContext(), uint(id))
+	if err != nil {
+		return fail("Failed to update product", err)
+	}
+
+	// Patch the writable fields only, the shape PUT accepts
+	var req dto.ProductRequest
+	if err := applyPatch(c, dto.NewProductRequest(current), &req); err != nil {
+		return fail("Failed to update product", err)
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	product := req.ToProduct()
+	product.ID = current.ID
+	if err := h.service.UpdateProduct(c.UserContext(), product, requestChange(c)); err != nil {
+		return fail("Failed to update product", err)
 	}
 
 	return c.JSON(Response{
@@ -181,6 +491,37 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 	})
 }
 
+// @Summary Generate product variants
+// @Description Add a variant for every combination of the product options that does not have one yet
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} Response{data=domain.Product}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /products/{id}/variants/generate [post]
+func (h *ProductHandler) GenerateVariants(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to generate variants", invalidParam("id", "Invalid product ID"))
+	}
+
+	product, err := h.service.GenerateVariants(c.UserContext(), uint(id))
+	if err != nil {
+		return fail("Failed to generate variants", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Variants generated successfully",
+		Data:    product,
+	})
+}
+
 // @Summary Delete a product
 // @Description Delete a product by its ID
 // @Tags products
@@ -188,25 +529,18 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 // @Security ApiKeyAuth
 // @Param id path int true "Product ID"
 // @Success 200 {object} Response
-// @Failure 401 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
 // @Router /products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
-		return c.Status(400).JSON(ErrorResponse{
-			Success: false,
-			Message: "Failed to delete product",
-			Error:   "Invalid product ID",
-		})
+		return fail("Failed to delete product", invalidParam("id", "Invalid product ID"))
 	}
 
-	if err := h.service.DeleteProduct(uint(id)); err != nil {
-		return c.Status(500).JSON(ErrorResponse{
-			Success: false,
-			Message: "Failed to delete product",
-			Error:   err.Error(),
-		})
+	if err := h.service.DeleteProduct(c.UserContext(), uint(id)); err != nil {
+		return fail("Failed to delete product", err)
 	}
 
 	return c.JSON(Response{
@@ -215,3 +549,52 @@ func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
 		Data:    nil,
 	})
 }
+
+// Transition returns a handler that applies a lifecycle action to a product.
+//
+// @Summary Change a product's lifecycle status
+// @Description Move a product through its lifecycle: submit (draft to pending_review, editor), approve (pending_review to published, reviewer), reject (pending_review to draft, reviewer, reason required), unpublish (published to draft, editor), archive (to archived, editor) and restore (archived to draft, editor)
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param action path string true "Lifecycle action" Enums(submit, approve, reject, unpublish, archive, restore)
+// @Param transition body dto.ProductTransitionRequest false "Reason for the transition"
+// @Success 200 {object} Response{data=domain.Product}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /products/{id}/{action} [post]
+func (h *ProductHandler) Transition(action domain.ProductAction) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+		if err != nil {
+			return fail("Failed to change product status", invalidParam("id", "Invalid product ID"))
+		}
+
+		var req dto.ProductTransitionRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&req); err != nil {
+				return fail("Invalid request format", malformed(err.Error()))
+			}
+		}
+
+		change := requestChange(c)
+		if req.Reason != "" {
+			change.Reason = req.Reason
+		}
+		product, err := h.service.Transition(c.UserContext(), uint(id), action, change)
+		if err != nil {
+			return fail("Failed to change product status", err)
+		}
+
+		return c.JSON(Response{
+			Success: true,
+			Message: "Product status changed to " + string(product.Status),
+			Data:    product,
+		})
+	}
+}
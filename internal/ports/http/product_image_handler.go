@@ -0,0 +1,234 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/dto"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductImageHandler struct {
+	service   *application.ProductImageService
+	validator *validator.Validate
+}
+
+func NewProductImageHandler(service *application.ProductImageService) *ProductImageHandler {
+	return &ProductImageHandler{
+		service:   service,
+		validator: newValidator(),
+	}
+}
+
+func (h *ProductImageHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/products/:id/images", h.GetImages)
+	app.Post("/products/:id/images", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.Upload)
+	app.Put("/products/:id/images/order", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.Reorder)
+	app.Put("/products/:id/images/:imageId/primary", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.SetPrimary)
+	app.Delete("/products/:id/images/:imageId", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.Delete)
+}
+
+// @Summary Get product images
+// @Description Get the images of a product in display order
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} Response{data=[]domain.ProductImage}
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/images [get]
+func (h *ProductImageHandler) GetImages(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get images", invalidParam("id", "Invalid product ID"))
+	}
+
+	images, err := h.service.GetImages(c.UserContext(), uint(id))
+	if err != nil {
+		return fail("Failed to get images", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Images retrieved successfully",
+		Data:    images,
+	})
+}
+
+// @Summary Upload a product image
+// @Description Upload a JPEG, PNG, GIF or WebP image; a thumbnail is generated automatically
+// @Tags products
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param image formData file true "Image file"
+// @Param primary formData bool false "Make this the primary image"
+// @Success 201 {object} Response{data=domain.ProductImage}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Failure 413 {object} ProblemDetails
+// @Failure 415 {object} ProblemDetails
+// @Router /products/{id}/images [post]
+func (h *ProductImageHandler) Upload(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to upload image", invalidParam("id", "Invalid product ID"))
+	}
+
+	header, err := c.FormFile("image")
+	if err != nil {
+		message := "An image file is required in the \"image\" field"
+		return fail("Invalid request format", &application.Error{
+			Kind:    application.KindValidation,
+			Message: message,
+			Fields:  []application.FieldError{{Field: "image", Rule: "required", Message: message}},
+		})
+	}
+	if header.Size > h.service.MaxSize() {
+		return fail("Failed to upload image", application.ErrImageTooLarge)
+	}
+
+	primary := false
+	if value := c.FormValue("primary"); value != "" {
+		if primary, err = strconv.ParseBool(value); err != nil {
+			return fail("Invalid request format", invalidParam("primary", "primary must be true or false"))
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return fail("Invalid request format", malformed(err.Error()))
+	}
+	defer file.Close()
+
+	image, err := h.service.Upload(c.UserContext(), uint(id), file, primary)
+	if err != nil {
+		return fail("Failed to upload image", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(Response{
+		Success: true,
+		Message: "Image uploaded successfully",
+		Data:    image,
+	})
+}
+
+// @Summary Reorder product images
+// @Description Set the display order of a product's images; every image must be listed once
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param order body dto.ProductImageOrderRequest true "Image IDs in display order"
+// @Success 200 {object} Response{data=[]domain.ProductImage}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/images/order [put]
+func (h *ProductImageHandler) Reorder(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to reorder images", invalidParam("id", "Invalid product ID"))
+	}
+
+	var req dto.ProductImageOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	images, err := h.service.Reorder(c.UserContext(), uint(id), req.ImageIDs)
+	if err != nil {
+		return fail("Failed to reorder images", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Images reordered successfully",
+		Data:    images,
+	})
+}
+
+// @Summary Set the primary product image
+// @Description Make an image the primary image of its product
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param imageId path int true "Image ID"
+// @Success 200 {object} Response{data=[]domain.ProductImage}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/images/{imageId}/primary [put]
+func (h *ProductImageHandler) SetPrimary(c *fiber.Ctx) error {
+	id, imageID, err := parseImageParams(c)
+	if err != nil {
+		return fail("Failed to set primary image", err)
+	}
+
+	images, err := h.service.SetPrimary(c.UserContext(), id, imageID)
+	if err != nil {
+		return fail("Failed to set primary image", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Primary image updated successfully",
+		Data:    images,
+	})
+}
+
+// @Summary Delete a product image
+// @Description Delete an image and its thumbnail
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param imageId path int true "Image ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/images/{imageId} [delete]
+func (h *ProductImageHandler) Delete(c *fiber.Ctx) error {
+	id, imageID, err := parseImageParams(c)
+	if err != nil {
+		return fail("Failed to delete image", err)
+	}
+
+	if err := h.service.Delete(c.UserContext(), id, imageID); err != nil {
+		return fail("Failed to delete image", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Image deleted successfully",
+	})
+}
+
+func parseImageParams(c *fiber.Ctx) (uint, uint, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return 0, 0, invalidParam("id", "Invalid product ID")
+	}
+	imageID, err := strconv.ParseUint(c.Params("imageId"), 10, 32)
+	if err != nil {
+		return 0, 0, invalidParam("imageId", "Invalid image ID")
+	}
+	return uint(id), uint(imageID), nil
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"bytes"
+	"mime"
+	"strconv"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// importSyncLimit is the largest file imported within the request; larger
+// files run as a background job.
+const importSyncLimit = 1 << 20
+
+type ProductImportHandler struct {
+	service *application.ProductImportService
+}
+
+func NewProductImportHandler(service *application.ProductImportService) *ProductImportHandler {
+	return &ProductImportHandler{
+		service: service,
+	}
+}
+
+func (h *ProductImportHandler) RegisterRoutes(app *fiber.App) {
+	app.Post("/products/import", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.Import)
+	app.Get("/products/import/jobs/:id", middleware.Auth(), h.GetJob)
+}
+
+// importFormat takes the format from the format query parameter or, failing
+// that, from the Content-Type header.
+func importFormat(c *fiber.Ctx) domain.ImportFormat {
+	if format := c.Query("format"); format != "" {
+		return domain.ImportFormat(format)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	switch mediaType {
+	case "text/csv":
+		return domain.ImportFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return domain.ImportFormatNDJSON
+	}
+	return ""
+}
+
+// @Summary Import products
+// @Description Create or update products from a CSV or NDJSON body. CSV files need a header row with the columns sku, name, description and price, and optionally barcode, slug and tags (separated by |). NDJSON lines use the same shape as POST /products. Files larger than 1 MiB, or any file with async=true, are imported by a background job.
+// @Tags products
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Security ApiKeyAuth
+// @Param format query string false "csv or ndjson; defaults to the Content-Type"
+// @Param mode query string false "create or upsert (update products matched by SKU)" default(create)
+// @Param dry_run query bool false "Validate every row without saving anything"
+// @Param batch_size query int false "Commit in batches of this many rows, skipping failing rows; 0 imports all rows in one transaction" default(0)
+// @Param async query bool false "Run the import as a background job"
+// @Success 200 {object} Response{data=domain.ImportReport}
+// @Success 202 {object} Response{data=domain.ImportJob}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 413 {object} ProblemDetails
+// @Failure 415 {object} ProblemDetails
+// @Router /products/import [post]
+func (h *ProductImportHandler) Import(c *fiber.Ctx) error {
+	body := c.Body()
+	if int64(len(body)) > h.service.MaxSize() {
+		return fail("Failed to import products", application.ErrImportTooLarge)
+	}
+
+	options := application.ImportOptions{
+		Format:    importFormat(c),
+		Mode:      domain.ImportMode(c.Query("mode")),
+		DryRun:    c.QueryBool("dry_run"),
+		BatchSize: c.QueryInt("batch_size"),
+		Actor:     requestChange(c).Actor,
+	}
+	if value := c.Query("batch_size"); value != "" {
+		if _, err := strconv.Atoi(value); err != nil {
+			return fail("Failed to import products", application.ErrInvalidImportBatchSize)
+		}
+	}
+
+	if c.QueryBool("async") || len(body) > importSyncLimit {
+		// The request body is reused once the handler returns, so the job
+		// needs its own copy
+		data := append([]byte(nil), body...)
+		job, err := h.service.StartImport(c.UserContext(), bytes.NewReader(data), options)
+		if err != nil {
+			return fail("Failed to import products", err)
+		}
+
+		c.Location("/products/import/jobs/" + job.ID)
+		return c.Status(fiber.StatusAccepted).JSON(Response{
+			Success: true,
+			Message: "Import started",
+			Data:    job,
+		})
+	}
+
+	report, err := h.service.Import(c.UserContext(), bytes.NewReader(body), options)
+	if err != nil {
+		return fail("Failed to import products", err)
+	}
+
+	message := "Products imported successfully"
+	switch {
+	case report.DryRun:
+		message = "Import validated, nothing was saved"
+	case !report.Committed:
+		message = "Import rolled back because some rows failed"
+	case report.Failed > 0:
+		message = "Products imported with some rows skipped"
+	}
+	return c.JSON(Response{
+		Success: report.Committed || report.DryRun,
+		Message: message,
+		Data:    report,
+	})
+}
+
+// @Summary Get an import job
+// @Description Get the status of a background product import and, once it has completed, its report
+// @Tags products
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Job ID"
+// @Success 200 {object} Response{data=domain.ImportJob}
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/import/jobs/{id} [get]
+func (h *ProductImportHandler) GetJob(c *fiber.Ctx) error {
+	job, err := h.service.GetJob(c.Params("id"))
+	if err != nil {
+		return fail("Failed to get import job", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Import job retrieved successfully",
+		Data:    job,
+	})
+}
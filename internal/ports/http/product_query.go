@@ -0,0 +1,65 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseProductQuery reads the product listing filters from the query string.
+func parseProductQuery(c *fiber.Ctx) (application.ProductQuery, error) {
+	query := application.ProductQuery{
+		Tags:               splitQueryList(c.Query("tags")),
+		TagMatch:           domain.TagMatch(strings.ToLower(c.Query("tag_match"))),
+		IncludeDescendants: c.QueryBool("include_descendants"),
+		IncludeUnpublished: canSeeUnpublished(c),
+	}
+
+	// Only editors see products that are not live, so only they can filter by status
+	if query.IncludeUnpublished {
+		for _, status := range splitQueryList(c.Query("status")) {
+			query.Statuses = append(query.Statuses, domain.ProductStatus(strings.ToLower(status)))
+		}
+	}
+
+	if value := c.Query("category_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return query, invalidParam("category_id", "invalid category_id")
+		}
+		categoryID := uint(id)
+		query.CategoryID = &categoryID
+	}
+
+	if value := c.Query("min_price"); value != "" {
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return query, invalidParam("min_price", "invalid min_price")
+		}
+		query.MinPrice = &price
+	}
+
+	if value := c.Query("max_price"); value != "" {
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return query, invalidParam("max_price", "invalid max_price")
+		}
+		query.MaxPrice = &price
+	}
+
+	return query, nil
+}
+
+// splitQueryList splits a comma separated query value, dropping empty items.
+func splitQueryList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
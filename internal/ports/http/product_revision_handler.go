@@ -0,0 +1,172 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductRevisionHandler struct {
+	service *application.ProductService
+}
+
+func NewProductRevisionHandler(service *application.ProductService) *ProductRevisionHandler {
+	return &ProductRevisionHandler{
+		service: service,
+	}
+}
+
+func (h *ProductRevisionHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/products/:id/revisions", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.GetRevisions)
+	app.Get("/products/:id/revisions/diff", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.DiffRevisions)
+	app.Get("/products/:id/revisions/:rev", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.GetRevision)
+	app.Post("/products/:id/revisions/:rev/restore", middleware.Auth(), middleware.RequireRole(domain.RoleEditor), h.RestoreRevision)
+}
+
+// parseRevisionParams reads the product ID and revision number path parameters.
+func parseRevisionParams(c *fiber.Ctx) (uint, int, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return 0, 0, invalidParam("id", "Invalid product ID")
+	}
+	number, err := strconv.Atoi(c.Params("rev"))
+	if err != nil || number < 1 {
+		return 0, 0, invalidParam("rev", "Invalid revision number")
+	}
+	return uint(id), number, nil
+}
+
+// @Summary Get product revisions
+// @Description Get the most recent revisions of a product, newest first
+// @Tags revisions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param limit query int false "Maximum number of revisions" default(200)
+// @Success 200 {object} Response{data=[]domain.ProductRevision}
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/revisions [get]
+func (h *ProductRevisionHandler) GetRevisions(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get product revisions", invalidParam("id", "Invalid product ID"))
+	}
+
+	revisions, err := h.service.GetRevisions(c.UserContext(), uint(id), c.QueryInt("limit"))
+	if err != nil {
+		return fail("Failed to get product revisions", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product revisions retrieved successfully",
+		Data:    revisions,
+	})
+}
+
+// @Summary Get a product revision
+// @Description Get one revision of a product by its number
+// @Tags revisions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} Response{data=domain.ProductRevision}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/revisions/{rev} [get]
+func (h *ProductRevisionHandler) GetRevision(c *fiber.Ctx) error {
+	id, number, err := parseRevisionParams(c)
+	if err != nil {
+		return fail("Failed to get product revision", err)
+	}
+
+	revision, err := h.service.GetRevision(c.UserContext(), id, number)
+	if err != nil {
+		return fail("Failed to get product revision", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product revision retrieved successfully",
+		Data:    revision,
+	})
+}
+
+// @Summary Compare product revisions
+// @Description List the fields that changed between two revisions of a product
+// @Tags revisions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param from query int true "Revision number to compare from"
+// @Param to query int true "Revision number to compare to"
+// @Success 200 {object} Response{data=domain.RevisionDiff}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/revisions/diff [get]
+func (h *ProductRevisionHandler) DiffRevisions(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to compare product revisions", invalidParam("id", "Invalid product ID"))
+	}
+
+	from, fromErr := strconv.Atoi(c.Query("from"))
+	to, toErr := strconv.Atoi(c.Query("to"))
+	if fromErr != nil || toErr != nil || from < 1 || to < 1 {
+		return fail("Failed to compare product revisions", malformed("from and to must be revision numbers"))
+	}
+
+	diff, err := h.service.DiffRevisions(c.UserContext(), uint(id), from, to)
+	if err != nil {
+		return fail("Failed to compare product revisions", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product revisions compared successfully",
+		Data:    diff,
+	})
+}
+
+// @Summary Restore a product revision
+// @Description Put the content of a revision back on the product, recording it as a new revision. The lifecycle status is left unchanged.
+// @Tags revisions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param rev path int true "Revision number"
+// @Param X-Change-Reason header string false "Reason recorded with the new revision"
+// @Success 200 {object} Response{data=domain.Product}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /products/{id}/revisions/{rev}/restore [post]
+func (h *ProductRevisionHandler) RestoreRevision(c *fiber.Ctx) error {
+	id, number, err := parseRevisionParams(c)
+	if err != nil {
+		return fail("Failed to restore product revision", err)
+	}
+
+	product, err := h.service.RestoreRevision(c.UserContext(), id, number, requestChange(c))
+	if err != nil {
+		return fail("Failed to restore product revision", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product revision restored successfully",
+		Data:    product,
+	})
+}
@@ -0,0 +1,195 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/dto"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PromotionHandler struct {
+	service   *application.PromotionService
+	validator *validator.Validate
+}
+
+func NewPromotionHandler(service *application.PromotionService) *PromotionHandler {
+	return &PromotionHandler{
+		service:   service,
+		validator: newValidator(),
+	}
+}
+
+func (h *PromotionHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/promotions", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.GetPromotions)
+	app.Get("/promotions/:id", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.GetPromotion)
+	app.Post("/promotions", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.CreatePromotion)
+	app.Put("/promotions/:id", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.UpdatePromotion)
+	app.Delete("/promotions/:id", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.DeletePromotion)
+}
+
+// parsePromotionRequest reads and validates a promotion body.
+func (h *PromotionHandler) parsePromotionRequest(c *fiber.Ctx) (*domain.Promotion, error) {
+	var req dto.PromotionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return nil, invalid(err)
+	}
+
+	return req.ToPromotion(), nil
+}
+
+// @Summary Get all promotions
+// @Description Get every promotion, automatic ones and coupons
+// @Tags promotions
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} Response{data=[]domain.Promotion}
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Router /promotions [get]
+func (h *PromotionHandler) GetPromotions(c *fiber.Ctx) error {
+	promotions, err := h.service.GetPromotions(c.UserContext())
+	if err != nil {
+		return fail("Failed to get promotions", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Promotions retrieved successfully",
+		Data:    promotions,
+	})
+}
+
+// @Summary Get a promotion
+// @Description Get a promotion by ID
+// @Tags promotions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} Response{data=domain.Promotion}
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /promotions/{id} [get]
+func (h *PromotionHandler) GetPromotion(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get promotion", invalidParam("id", "Invalid promotion ID"))
+	}
+
+	promotion, err := h.service.GetPromotion(c.UserContext(), uint(id))
+	if err != nil {
+		return fail("Failed to get promotion", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Promotion retrieved successfully",
+		Data:    promotion,
+	})
+}
+
+// @Summary Create a promotion
+// @Description Create a percentage, fixed or buy-X-get-Y promotion. With a code it is a coupon, otherwise it applies automatically.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param promotion body dto.PromotionRequest true "Promotion"
+// @Success 201 {object} Response{data=domain.Promotion}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /promotions [post]
+func (h *PromotionHandler) CreatePromotion(c *fiber.Ctx) error {
+	promotion, err := h.parsePromotionRequest(c)
+	if err != nil {
+		return err
+	}
+
+	promotion, err = h.service.CreatePromotion(c.UserContext(), promotion)
+	if err != nil {
+		return fail("Failed to create promotion", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(Response{
+		Success: true,
+		Message: "Promotion created successfully",
+		Data:    promotion,
+	})
+}
+
+// @Summary Update a promotion
+// @Description Replace a promotion. Its usage count is kept.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Promotion ID"
+// @Param promotion body dto.PromotionRequest true "Promotion"
+// @Success 200 {object} Response{data=domain.Promotion}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /promotions/{id} [put]
+func (h *PromotionHandler) UpdatePromotion(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to update promotion", invalidParam("id", "Invalid promotion ID"))
+	}
+
+	promotion, err := h.parsePromotionRequest(c)
+	if err != nil {
+		return err
+	}
+
+	promotion.ID = uint(id)
+	promotion, err = h.service.UpdatePromotion(c.UserContext(), promotion)
+	if err != nil {
+		return fail("Failed to update promotion", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Promotion updated successfully",
+		Data:    promotion,
+	})
+}
+
+// @Summary Delete a promotion
+// @Description Delete a promotion and its redemption records
+// @Tags promotions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} Response
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /promotions/{id} [delete]
+func (h *PromotionHandler) DeletePromotion(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to delete promotion", invalidParam("id", "Invalid promotion ID"))
+	}
+
+	if err := h.service.DeletePromotion(c.UserContext(), uint(id)); err != nil {
+		return fail("Failed to delete promotion", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Promotion deleted successfully",
+	})
+}
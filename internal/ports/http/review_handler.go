@@ -0,0 +1,240 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/dto"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReviewHandler struct {
+	service   *application.ReviewService
+	validator *validator.Validate
+}
+
+func NewReviewHandler(service *application.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		service:   service,
+		validator: newValidator(),
+	}
+}
+
+func (h *ReviewHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/products/:id/reviews", h.GetReviews)
+	app.Post("/products/:id/reviews", middleware.Auth(), h.CreateReview)
+	app.Put("/reviews/:id", middleware.Auth(), h.UpdateReview)
+	app.Delete("/reviews/:id", middleware.Auth(), h.DeleteReview)
+	app.Put("/reviews/:id/moderation", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.ModerateReview)
+}
+
+// parseReviewRequest reads and validates a review body.
+func (h *ReviewHandler) parseReviewRequest(c *fiber.Ctx) (application.ReviewInput, error) {
+	var req dto.ReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return application.ReviewInput{}, fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return application.ReviewInput{}, invalid(err)
+	}
+
+	return application.ReviewInput{Rating: req.Rating, Title: req.Title, Body: req.Body}, nil
+}
+
+// @Summary Get product reviews
+// @Description Get the approved reviews of a product, newest first. Admins can list reviews in any status.
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param status query string false "Moderation status, admins only" Enums(pending, approved, rejected)
+// @Success 200 {object} Response{data=[]domain.Review}
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /products/{id}/reviews [get]
+func (h *ReviewHandler) GetReviews(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get reviews", invalidParam("id", "Invalid product ID"))
+	}
+
+	// Only admins see reviews that are not approved
+	status := domain.ReviewApproved
+	if middleware.User(c).HasRole(domain.RoleAdmin) {
+		status = domain.ReviewStatus(strings.ToLower(c.Query("status")))
+	}
+
+	reviews, err := h.service.GetReviews(c.UserContext(), uint(id), status)
+	if err != nil {
+		return fail("Failed to get reviews", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Reviews retrieved successfully",
+		Data:    reviews,
+	})
+}
+
+// @Summary Review a product
+// @Description Rate a published product. Each user reviews a product once, and the review is shown after moderation.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param review body dto.ReviewRequest true "Review"
+// @Success 201 {object} Response{data=domain.Review}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /products/{id}/reviews [post]
+func (h *ReviewHandler) CreateReview(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to create review", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to create review", invalidParam("id", "Invalid product ID"))
+	}
+
+	input, err := h.parseReviewRequest(c)
+	if err != nil {
+		return err
+	}
+
+	review, err := h.service.CreateReview(c.UserContext(), uint(id), user, input)
+	if err != nil {
+		return fail("Failed to create review", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(Response{
+		Success: true,
+		Message: "Review submitted for moderation",
+		Data:    review,
+	})
+}
+
+// @Summary Edit a review
+// @Description Change your review of a product. The edited review goes back to moderation.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Review ID"
+// @Param review body dto.ReviewRequest true "Review"
+// @Success 200 {object} Response{data=domain.Review}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /reviews/{id} [put]
+func (h *ReviewHandler) UpdateReview(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to update review", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to update review", invalidParam("id", "Invalid review ID"))
+	}
+
+	input, err := h.parseReviewRequest(c)
+	if err != nil {
+		return err
+	}
+
+	review, err := h.service.UpdateReview(c.UserContext(), uint(id), user, input)
+	if err != nil {
+		return fail("Failed to update review", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Review updated and submitted for moderation",
+		Data:    review,
+	})
+}
+
+// @Summary Delete a review
+// @Description Delete your review, or any review as an admin
+// @Tags reviews
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Review ID"
+// @Success 200 {object} Response
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /reviews/{id} [delete]
+func (h *ReviewHandler) DeleteReview(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to delete review", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to delete review", invalidParam("id", "Invalid review ID"))
+	}
+
+	if err := h.service.DeleteReview(c.UserContext(), uint(id), user); err != nil {
+		return fail("Failed to delete review", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Review deleted successfully",
+	})
+}
+
+// @Summary Moderate a review
+// @Description Approve or reject a review. Only approved reviews are shown and counted in the product rating.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Review ID"
+// @Param moderation body dto.ReviewModerationRequest true "Moderation decision"
+// @Success 200 {object} Response{data=domain.Review}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /reviews/{id}/moderation [put]
+func (h *ReviewHandler) ModerateReview(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to moderate review", invalidParam("id", "Invalid review ID"))
+	}
+
+	var req dto.ReviewModerationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	review, err := h.service.Moderate(c.UserContext(), uint(id), req.Status, req.Note)
+	if err != nil {
+		return fail("Failed to moderate review", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Review moderated successfully",
+		Data:    review,
+	})
+}
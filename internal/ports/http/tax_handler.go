@@ -0,0 +1,195 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
+	"github.com/euro1061/gohex/internal/dto"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TaxHandler struct {
+	service   *application.TaxService
+	validator *validator.Validate
+}
+
+func NewTaxHandler(service *application.TaxService) *TaxHandler {
+	return &TaxHandler{
+		service:   service,
+		validator: newValidator(),
+	}
+}
+
+func (h *TaxHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/tax/rates", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.GetTaxRates)
+	app.Get("/tax/rates/:id", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.GetTaxRate)
+	app.Post("/tax/rates", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.CreateTaxRate)
+	app.Put("/tax/rates/:id", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.UpdateTaxRate)
+	app.Delete("/tax/rates/:id", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.DeleteTaxRate)
+}
+
+// parseTaxRateRequest reads and validates a tax rate body.
+func (h *TaxHandler) parseTaxRateRequest(c *fiber.Ctx) (*domain.TaxRate, error) {
+	var req dto.TaxRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return nil, invalid(err)
+	}
+
+	return req.ToTaxRate(), nil
+}
+
+// @Summary Get all tax rates
+// @Description Get every tax rate, ordered by country, region and tax class
+// @Tags tax
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} Response{data=[]domain.TaxRate}
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Router /tax/rates [get]
+func (h *TaxHandler) GetTaxRates(c *fiber.Ctx) error {
+	rates, err := h.service.GetRates(c.UserContext())
+	if err != nil {
+		return fail("Failed to get tax rates", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Tax rates retrieved successfully",
+		Data:    rates,
+	})
+}
+
+// @Summary Get a tax rate
+// @Description Get a tax rate by ID
+// @Tags tax
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Tax rate ID"
+// @Success 200 {object} Response{data=domain.TaxRate}
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /tax/rates/{id} [get]
+func (h *TaxHandler) GetTaxRate(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get tax rate", invalidParam("id", "Invalid tax rate ID"))
+	}
+
+	rate, err := h.service.GetRate(c.UserContext(), uint(id))
+	if err != nil {
+		return fail("Failed to get tax rate", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Tax rate retrieved successfully",
+		Data:    rate,
+	})
+}
+
+// @Summary Create a tax rate
+// @Description Create the rate of a tax class in a country, or in a region of it. Region rates take precedence over country rates.
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param rate body dto.TaxRateRequest true "Tax rate"
+// @Success 201 {object} Response{data=domain.TaxRate}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /tax/rates [post]
+func (h *TaxHandler) CreateTaxRate(c *fiber.Ctx) error {
+	rate, err := h.parseTaxRateRequest(c)
+	if err != nil {
+		return err
+	}
+
+	rate, err = h.service.CreateRate(c.UserContext(), rate)
+	if err != nil {
+		return fail("Failed to create tax rate", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(Response{
+		Success: true,
+		Message: "Tax rate created successfully",
+		Data:    rate,
+	})
+}
+
+// @Summary Update a tax rate
+// @Description Replace a tax rate
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Tax rate ID"
+// @Param rate body dto.TaxRateRequest true "Tax rate"
+// @Success 200 {object} Response{data=domain.TaxRate}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /tax/rates/{id} [put]
+func (h *TaxHandler) UpdateTaxRate(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to update tax rate", invalidParam("id", "Invalid tax rate ID"))
+	}
+
+	rate, err := h.parseTaxRateRequest(c)
+	if err != nil {
+		return err
+	}
+
+	rate.ID = uint(id)
+	rate, err = h.service.UpdateRate(c.UserContext(), rate)
+	if err != nil {
+		return fail("Failed to update tax rate", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Tax rate updated successfully",
+		Data:    rate,
+	})
+}
+
+// @Summary Delete a tax rate
+// @Description Delete a tax rate. Quotes and orders placed afterwards are no longer taxed at it.
+// @Tags tax
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Tax rate ID"
+// @Success 200 {object} Response
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /tax/rates/{id} [delete]
+func (h *TaxHandler) DeleteTaxRate(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to delete tax rate", invalidParam("id", "Invalid tax rate ID"))
+	}
+
+	if err := h.service.DeleteRate(c.UserContext(), uint(id)); err != nil {
+		return fail("Failed to delete tax rate", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Tax rate deleted successfully",
+	})
+}
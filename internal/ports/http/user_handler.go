@@ -1,9 +1,12 @@
 package http
 
 import (
+	"log"
+	"strconv"
 	"time"
 
 	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/domain"
 	"github.com/euro1061/gohex/internal/dto"
 	"github.com/euro1061/gohex/internal/middleware"
 	"github.com/go-playground/validator/v10"
@@ -12,13 +15,15 @@ import (
 
 type UserHandler struct {
 	service   *application.UserService
+	carts     *application.CartService
 	validator *validator.Validate
 }
 
-func NewUserHandler(service *application.UserService) *UserHandler {
+func NewUserHandler(service *application.UserService, carts *application.CartService) *UserHandler {
 	return &UserHandler{
 		service:   service,
-		validator: validator.New(),
+		carts:     carts,
+		validator: newValidator(),
 	}
 }
 
@@ -26,8 +31,10 @@ func (h *UserHandler) RegisterRoutes(app *fiber.App) {
 	app.Post("/register", h.Register)
 	app.Post("/login", h.Login)
 	app.Put("/users/profile", middleware.Auth(), h.UpdateProfile)
+	app.Patch("/users/profile", middleware.Auth(), h.PatchProfile)
 	app.Get("/users/profile", middleware.Auth(), h.GetProfile)
 	app.Post("/logout", middleware.Auth(), h.Logout)
+	app.Put("/users/:id/role", middleware.Auth(), middleware.RequireRole(domain.RoleAdmin), h.SetRole)
 }
 
 // @Summary Register a new user
@@ -37,35 +44,24 @@ func (h *UserHandler) RegisterRoutes(app *fiber.App) {
 // @Produce json
 // @Param user body dto.UserRegisterRequest true "User registration info"
 // @Success 201 {object} Response{data=dto.UserResponse}
-// @Failure 400 {object} ErrorResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
 // @Router /register [post]
 func (h *UserHandler) Register(c *fiber.Ctx) error {
 	var req dto.UserRegisterRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
-			Success: false,
-			Message: "Invalid request format",
-			Error:   err.Error(),
-		})
+		return fail("Invalid request format", malformed(err.Error()))
 	}
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		return invalid(err)
 	}
 
 	// Convert DTO to domain model and register
 	user := req.ToUser()
-	if err := h.service.Register(user); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
-			Success: false,
-			Message: "Failed to register user",
-			Error:   err.Error(),
-		})
+	if err := h.service.Register(c.UserContext(), user); err != nil {
+		return fail("Failed to register user", err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(Response{
@@ -82,34 +78,22 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 // @Produce json
 // @Param user body dto.UserLoginRequest true "User credentials"
 // @Success 200 {object} Response{data=string}
-// @Failure 401 {object} ErrorResponse
+// @Failure 401 {object} ProblemDetails
 // @Router /login [post]
 func (h *UserHandler) Login(c *fiber.Ctx) error {
 	var req dto.UserLoginRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
-			Success: false,
-			Message: "Invalid request format",
-			Error:   err.Error(),
-		})
+		return fail("Invalid request format", malformed(err.Error()))
 	}
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		return invalid(err)
 	}
 
-	token, err := h.service.Login(req.Username, req.Password)
+	token, err := h.service.Login(c.UserContext(), req.Username, req.Password)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{
-			Success: false,
-			Message: "Login failed",
-			Error:   err.Error(),
-		})
+		return fail("Login failed", err)
 	}
 
 	c.Cookie(&fiber.Cookie{
@@ -120,6 +104,8 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 		SameSite: "lax",
 	})
 
+	h.mergeGuestCart(c, token)
+
 	return c.Status(fiber.StatusOK).JSON(Response{
 		Success: true,
 		Message: "Login successful",
@@ -127,6 +113,24 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 	})
 }
 
+// mergeGuestCart moves the guest cart of the request into the cart of the
+// user who just signed in. A failed merge does not fail the login.
+func (h *UserHandler) mergeGuestCart(c *fiber.Ctx, token string) {
+	guestToken := c.Cookies(CartCookie)
+	if guestToken == "" {
+		return
+	}
+	user, err := h.service.GetUserFromToken(c.UserContext(), token)
+	if err == nil {
+		err = h.carts.MergeGuestCart(c.UserContext(), guestToken, user.ID)
+	}
+	if err != nil {
+		log.Printf("error merging guest cart: %v", err)
+		return
+	}
+	c.ClearCookie(CartCookie)
+}
+
 // @Summary Update user profile
 // @Description Update the authenticated user's profile information
 // @Tags users
@@ -135,37 +139,26 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 // @Security ApiKeyAuth
 // @Param user body dto.UserUpdateRequest true "User update info"
 // @Success 200 {object} Response{data=dto.UserResponse}
-// @Failure 401 {object} ErrorResponse
-// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ProblemDetails
+// @Failure 400 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
 // @Router /users/profile [put]
 func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
 	var req dto.UserUpdateRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
-			Success: false,
-			Message: "Invalid request format",
-			Error:   err.Error(),
-		})
+		return fail("Invalid request format", malformed(err.Error()))
 	}
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		return invalid(err)
 	}
 
 	// Get user from token
 	token := c.Locals("token").(string)
-	user, err := h.service.GetUserFromToken(token)
+	user, err := h.service.GetUserFromToken(c.UserContext(), token)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{
-			Success: false,
-			Message: "Invalid or expired token",
-			Error:   err.Error(),
-		})
+		return fail("Invalid or expired token", err)
 	}
 
 	// Update user fields
@@ -175,16 +168,58 @@ func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
 	user.Email = req.Email
 
 	// Update user
-	if err := h.service.Update(user); err != nil {
-		status := fiber.StatusInternalServerError
-		if err.Error() == "username already taken" || err.Error() == "email already taken" {
-			status = fiber.StatusConflict
-		}
-		return c.Status(status).JSON(ErrorResponse{
-			Success: false,
-			Message: "Failed to update profile",
-			Error:   err.Error(),
-		})
+	if err := h.service.Update(c.UserContext(), user); err != nil {
+		return fail("Failed to update profile", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(Response{
+		Success: true,
+		Message: "Profile updated successfully",
+		Data:    dto.UserResponseFromUser(user),
+	})
+}
+
+// @Summary Partially update user profile
+// @Description Apply a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) to the authenticated user's profile
+// @Tags users
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param patch body object true "Merge patch object or JSON patch operations"
+// @Success 200 {object} Response{data=dto.UserResponse}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Failure 415 {object} ProblemDetails
+// @Router /users/profile [patch]
+func (h *UserHandler) PatchProfile(c *fiber.Ctx) error {
+	// Get user from token
+	token := c.Locals("token").(string)
+	user, err := h.service.GetUserFromToken(c.UserContext(), token)
+	if err != nil {
+		return fail("Invalid or expired token", err)
+	}
+
+	// Patch the current profile as an update request
+	var req dto.UserUpdateRequest
+	if err := applyPatch(c, dto.UserUpdateRequestFromUser(user), &req); err != nil {
+		return fail("Invalid request format", err)
+	}
+
+	// Validate patched request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	// Update user fields
+	user.Name = req.Name
+	user.Username = req.Username
+	user.Gender = req.Gender
+	user.Email = req.Email
+
+	// Update user
+	if err := h.service.Update(c.UserContext(), user); err != nil {
+		return fail("Failed to update profile", err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(Response{
@@ -200,18 +235,14 @@ func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
 // @Produce json
 // @Security ApiKeyAuth
 // @Success 200 {object} Response{data=dto.UserResponse}
-// @Failure 401 {object} ErrorResponse
+// @Failure 401 {object} ProblemDetails
 // @Router /users/profile [get]
 func (h *UserHandler) GetProfile(c *fiber.Ctx) error {
 	// Get user from token
 	token := c.Locals("token").(string)
-	user, err := h.service.GetUserFromToken(token)
+	user, err := h.service.GetUserFromToken(c.UserContext(), token)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{
-			Success: false,
-			Message: "Invalid or expired token",
-			Error:   err.Error(),
-		})
+		return fail("Invalid or expired token", err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(Response{
@@ -227,7 +258,7 @@ func (h *UserHandler) GetProfile(c *fiber.Ctx) error {
 // @Produce json
 // @Security ApiKeyAuth
 // @Success 200 {object} Response
-// @Failure 401 {object} ErrorResponse
+// @Failure 401 {object} ProblemDetails
 // @Router /logout [post]
 func (h *UserHandler) Logout(c *fiber.Ctx) error {
 	c.ClearCookie("token")
@@ -236,3 +267,45 @@ func (h *UserHandler) Logout(c *fiber.Ctx) error {
 		Message: "Logout successful",
 	})
 }
+
+// @Summary Change a user's role
+// @Description Set the role of a user: customer, editor, reviewer or admin. Requires the admin role.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Param role body dto.UserRoleRequest true "New role"
+// @Success 200 {object} Response{data=dto.UserResponse}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 403 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /users/{id}/role [put]
+func (h *UserHandler) SetRole(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to change role", invalidParam("id", "Invalid user ID"))
+	}
+
+	var req dto.UserRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	user, err := h.service.SetRole(c.UserContext(), uint(id), req.Role)
+	if err != nil {
+		return fail("Failed to change role", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Role changed successfully",
+		Data:    dto.UserResponseFromUser(user),
+	})
+}
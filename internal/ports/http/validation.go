@@ -0,0 +1,105 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/go-playground/validator/v10"
+)
+
+// newValidator returns a validator that names fields as requests do, by
+// their JSON names.
+func newValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return validate
+}
+
+// invalid reports that a request body failed validation, listing each field
+// that broke a rule.
+func invalid(err error) error {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return fail("Validation failed", malformed(err.Error()))
+	}
+
+	fields := make([]application.FieldError, 0, len(errs))
+	messages := make([]string, 0, len(errs))
+	for _, fieldErr := range errs {
+		field := fieldPath(fieldErr)
+		message := fieldMessage(field, fieldErr)
+		fields = append(fields, application.FieldError{Field: field, Rule: fieldErr.Tag(), Message: message})
+		messages = append(messages, message)
+	}
+	return fail("Validation failed", &application.Error{
+		Kind:    application.KindValidation,
+		Message: strings.Join(messages, "; "),
+		Fields:  fields,
+	})
+}
+
+// malformed reports a request that could not be decoded.
+func malformed(message string) error {
+	return &application.Error{Kind: application.KindValidation, Message: message}
+}
+
+// invalidParam reports a path or query parameter that could not be parsed.
+func invalidParam(name, message string) error {
+	return &application.Error{
+		Kind:    application.KindValidation,
+		Message: message,
+		Fields:  []application.FieldError{{Field: name, Rule: "format", Message: message}},
+	}
+}
+
+// fieldPath returns the path of the field in the request body, such as
+// "items[0].quantity", without the name of the request struct.
+func fieldPath(fieldErr validator.FieldError) string {
+	_, path, found := strings.Cut(fieldErr.Namespace(), ".")
+	if !found {
+		return fieldErr.Field()
+	}
+	return path
+}
+
+// fieldMessage describes the rule a field broke.
+func fieldMessage(field string, fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	switch fieldErr.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "oneof":
+		return field + " must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "eqfield":
+		return field + " must match " + param
+	case "len", "min", "max":
+		bound := map[string]string{"len": "exactly", "min": "at least", "max": "at most"}[fieldErr.Tag()]
+		switch fieldErr.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s must be %s %s characters long", field, bound, param)
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("%s must have %s %s items", field, bound, param)
+		}
+		return fmt.Sprintf("%s must be %s %s", field, bound, param)
+	case "gt":
+		return field + " must be greater than " + param
+	case "gte":
+		return field + " must be at least " + param
+	case "lt":
+		return field + " must be less than " + param
+	case "lte":
+		return field + " must be at most " + param
+	}
+	return field + " is not valid"
+}
@@ -0,0 +1,360 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/euro1061/gohex/internal/application"
+	"github.com/euro1061/gohex/internal/dto"
+	"github.com/euro1061/gohex/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WishlistHandler struct {
+	service   *application.WishlistService
+	validator *validator.Validate
+}
+
+func NewWishlistHandler(service *application.WishlistService) *WishlistHandler {
+	return &WishlistHandler{
+		service:   service,
+		validator: newValidator(),
+	}
+}
+
+func (h *WishlistHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/wishlists", middleware.Auth(), h.GetWishlists)
+	app.Post("/wishlists", middleware.Auth(), h.CreateWishlist)
+	app.Get("/wishlists/shared/:token", h.GetSharedWishlist)
+	app.Get("/wishlists/:id", middleware.Auth(), h.GetWishlist)
+	app.Put("/wishlists/:id", middleware.Auth(), h.UpdateWishlist)
+	app.Delete("/wishlists/:id", middleware.Auth(), h.DeleteWishlist)
+	app.Post("/wishlists/:id/share-link", middleware.Auth(), h.ResetShareLink)
+	app.Post("/wishlists/:id/items", middleware.Auth(), h.AddItem)
+	app.Delete("/wishlists/:id/items/:productId", middleware.Auth(), h.RemoveItem)
+}
+
+// parseWishlistRequest reads and validates a wishlist body.
+func (h *WishlistHandler) parseWishlistRequest(c *fiber.Ctx) (*dto.WishlistRequest, error) {
+	var req dto.WishlistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return nil, invalid(err)
+	}
+
+	return &req, nil
+}
+
+// @Summary Get my wishlists
+// @Description Get the wishlists of the signed in user with their products, oldest first
+// @Tags wishlists
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} Response{data=[]domain.Wishlist}
+// @Failure 401 {object} ProblemDetails
+// @Router /wishlists [get]
+func (h *WishlistHandler) GetWishlists(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to get wishlists", application.ErrInvalidToken)
+	}
+
+	wishlists, err := h.service.GetWishlists(c.UserContext(), user.ID)
+	if err != nil {
+		return fail("Failed to get wishlists", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Wishlists retrieved successfully",
+		Data:    wishlists,
+	})
+}
+
+// @Summary Create a wishlist
+// @Description Create a named wishlist. A public wishlist can be viewed by anyone with its share link.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param wishlist body dto.WishlistRequest true "Wishlist"
+// @Success 201 {object} Response{data=domain.Wishlist}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /wishlists [post]
+func (h *WishlistHandler) CreateWishlist(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to create wishlist", application.ErrInvalidToken)
+	}
+
+	req, err := h.parseWishlistRequest(c)
+	if err != nil {
+		return err
+	}
+
+	wishlist, err := h.service.CreateWishlist(c.UserContext(), user.ID, req.Name, req.Public)
+	if err != nil {
+		return fail("Failed to create wishlist", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(Response{
+		Success: true,
+		Message: "Wishlist created successfully",
+		Data:    wishlist,
+	})
+}
+
+// @Summary Get a shared wishlist
+// @Description Get a public wishlist by the token of its share link
+// @Tags wishlists
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} Response{data=domain.Wishlist}
+// @Failure 404 {object} ProblemDetails
+// @Router /wishlists/shared/{token} [get]
+func (h *WishlistHandler) GetSharedWishlist(c *fiber.Ctx) error {
+	wishlist, err := h.service.GetSharedWishlist(c.UserContext(), c.Params("token"))
+	if err != nil {
+		return fail("Failed to get wishlist", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Wishlist retrieved successfully",
+		Data:    wishlist,
+	})
+}
+
+// @Summary Get a wishlist
+// @Description Get a wishlist of the signed in user with its products
+// @Tags wishlists
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Wishlist ID"
+// @Success 200 {object} Response{data=domain.Wishlist}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /wishlists/{id} [get]
+func (h *WishlistHandler) GetWishlist(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to get wishlist", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to get wishlist", invalidParam("id", "Invalid wishlist ID"))
+	}
+
+	wishlist, err := h.service.GetWishlist(c.UserContext(), uint(id), user.ID)
+	if err != nil {
+		return fail("Failed to get wishlist", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Wishlist retrieved successfully",
+		Data:    wishlist,
+	})
+}
+
+// @Summary Update a wishlist
+// @Description Rename a wishlist of the signed in user and make it public or private
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Wishlist ID"
+// @Param wishlist body dto.WishlistRequest true "Wishlist"
+// @Success 200 {object} Response{data=domain.Wishlist}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /wishlists/{id} [put]
+func (h *WishlistHandler) UpdateWishlist(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to update wishlist", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to update wishlist", invalidParam("id", "Invalid wishlist ID"))
+	}
+
+	req, err := h.parseWishlistRequest(c)
+	if err != nil {
+		return err
+	}
+
+	wishlist, err := h.service.UpdateWishlist(c.UserContext(), uint(id), user.ID, req.Name, req.Public)
+	if err != nil {
+		return fail("Failed to update wishlist", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Wishlist updated successfully",
+		Data:    wishlist,
+	})
+}
+
+// @Summary Delete a wishlist
+// @Description Delete a wishlist of the signed in user and the products saved in it
+// @Tags wishlists
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Wishlist ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /wishlists/{id} [delete]
+func (h *WishlistHandler) DeleteWishlist(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to delete wishlist", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to delete wishlist", invalidParam("id", "Invalid wishlist ID"))
+	}
+
+	if err := h.service.DeleteWishlist(c.UserContext(), uint(id), user.ID); err != nil {
+		return fail("Failed to delete wishlist", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Wishlist deleted successfully",
+	})
+}
+
+// @Summary Reset the share link of a wishlist
+// @Description Give a wishlist of the signed in user a new share token. Links shared before stop working.
+// @Tags wishlists
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Wishlist ID"
+// @Success 200 {object} Response{data=domain.Wishlist}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /wishlists/{id}/share-link [post]
+func (h *WishlistHandler) ResetShareLink(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to reset share link", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to reset share link", invalidParam("id", "Invalid wishlist ID"))
+	}
+
+	wishlist, err := h.service.ResetShareLink(c.UserContext(), uint(id), user.ID)
+	if err != nil {
+		return fail("Failed to reset share link", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Share link reset successfully",
+		Data:    wishlist,
+	})
+}
+
+// @Summary Save a product in a wishlist
+// @Description Add a live product to a wishlist of the signed in user
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Wishlist ID"
+// @Param item body dto.WishlistItemRequest true "Product to save"
+// @Success 200 {object} Response{data=domain.Wishlist}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Router /wishlists/{id}/items [post]
+func (h *WishlistHandler) AddItem(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to add product to wishlist", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to add product to wishlist", invalidParam("id", "Invalid wishlist ID"))
+	}
+
+	var req dto.WishlistItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fail("Invalid request format", malformed(err.Error()))
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return invalid(err)
+	}
+
+	wishlist, err := h.service.AddItem(c.UserContext(), uint(id), user.ID, req.ProductID)
+	if err != nil {
+		return fail("Failed to add product to wishlist", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product added to wishlist",
+		Data:    wishlist,
+	})
+}
+
+// @Summary Remove a product from a wishlist
+// @Description Remove a product from a wishlist of the signed in user
+// @Tags wishlists
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Wishlist ID"
+// @Param productId path int true "Product ID"
+// @Success 200 {object} Response{data=domain.Wishlist}
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Router /wishlists/{id}/items/{productId} [delete]
+func (h *WishlistHandler) RemoveItem(c *fiber.Ctx) error {
+	user := middleware.User(c)
+	if user == nil {
+		return fail("Failed to remove product from wishlist", application.ErrInvalidToken)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return fail("Failed to remove product from wishlist", invalidParam("id", "Invalid wishlist ID"))
+	}
+
+	productID, err := strconv.ParseUint(c.Params("productId"), 10, 32)
+	if err != nil {
+		return fail("Failed to remove product from wishlist", invalidParam("productId", "Invalid product ID"))
+	}
+
+	wishlist, err := h.service.RemoveItem(c.UserContext(), uint(id), user.ID, uint(productID))
+	if err != nil {
+		return fail("Failed to remove product from wishlist", err)
+	}
+
+	return c.JSON(Response{
+		Success: true,
+		Message: "Product removed from wishlist",
+		Data:    wishlist,
+	})
+}
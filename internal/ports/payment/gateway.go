@@ -0,0 +1,57 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+var (
+	// ErrIntentNotFound is returned when the provider has no such intent.
+	ErrIntentNotFound = errors.New("payment intent not found")
+	// ErrInvalidIntentState is returned when an intent cannot make the
+	// requested change in its current state.
+	ErrInvalidIntentState = errors.New("payment intent cannot make this change in its current state")
+	// ErrInvalidSignature is returned for webhook payloads whose signature
+	// does not verify.
+	ErrInvalidSignature = errors.New("webhook signature is not valid")
+)
+
+// Intent is a payment at the provider, from creation to capture or refund.
+type Intent struct {
+	ID        string               `json:"id"`
+	Amount    float64              `json:"amount"`
+	Currency  string               `json:"currency"`
+	Reference string               `json:"reference"`
+	Status    domain.PaymentStatus `json:"status"`
+	// ClientSecret lets the customer complete the payment with the provider.
+	ClientSecret string    `json:"client_secret,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Event is a change to an intent reported by the provider's webhook.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Intent    Intent    `json:"intent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PaymentGateway takes payments through a payment provider. Amounts are in
+// currency units, not cents.
+type PaymentGateway interface {
+	// CreateIntent starts a payment of amount for reference, such as an
+	// order number.
+	CreateIntent(ctx context.Context, amount float64, currency, reference string) (*Intent, error)
+	GetIntent(ctx context.Context, id string) (*Intent, error)
+	// Capture collects the funds of an authorized intent.
+	Capture(ctx context.Context, id string) (*Intent, error)
+	// Refund returns the funds of a captured intent.
+	Refund(ctx context.Context, id string) (*Intent, error)
+	// Void cancels an intent that has not been captured.
+	Void(ctx context.Context, id string) (*Intent, error)
+	// ParseEvent verifies the signature of a webhook payload and decodes it.
+	ParseEvent(payload []byte, signature string) (*Event, error)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type CartRepository interface {
+	// Create returns ErrDuplicateKey when the user already has a cart.
+	Create(ctx context.Context, cart *domain.Cart) error
+	GetByUser(ctx context.Context, userID uint) (*domain.Cart, error)
+	// LockByUser returns the cart of a user like GetByUser and locks it until
+	// the unit of work it runs in ends.
+	LockByUser(ctx context.Context, userID uint) (*domain.Cart, error)
+	GetByToken(ctx context.Context, token string) (*domain.Cart, error)
+	// Save stores the items of a cart: items with an ID are updated, new
+	// ones created and those no longer listed deleted.
+	Save(ctx context.Context, cart *domain.Cart) error
+	Delete(ctx context.Context, id uint) error
+	// DeleteGuestCarts deletes the guest carts not updated since before.
+	DeleteGuestCarts(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *domain.Category) error
+	GetByID(ctx context.Context, id uint) (*domain.Category, error)
+	GetAll(ctx context.Context) ([]domain.Category, error)
+	GetChildren(ctx context.Context, id uint) ([]domain.Category, error)
+	// GetDescendantIDs returns the IDs of the category and every category below it.
+	GetDescendantIDs(ctx context.Context, id uint) ([]uint, error)
+	Update(ctx context.Context, category *domain.Category) error
+	Delete(ctx context.Context, id uint) error
+
+	SetProductCategories(ctx context.Context, productID uint, categoryIDs []uint) error
+	GetProductCategories(ctx context.Context, productID uint) ([]domain.Category, error)
+	GetProductIDs(ctx context.Context, categoryIDs []uint) ([]uint, error)
+	// GetCategoryIDsByProducts returns the category IDs of each given product.
+	GetCategoryIDsByProducts(ctx context.Context, productIDs []uint) (map[uint][]uint, error)
+}
@@ -0,0 +1,6 @@
+package repository
+
+import "errors"
+
+// ErrDuplicateKey is returned by adapters when a write violates a uniqueness constraint.
+var ErrDuplicateKey = errors.New("duplicate key")
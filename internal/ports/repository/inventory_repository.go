@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+// StockChange mutates a locked stock level and returns the movement to record,
+// or nil when nothing should be written to the ledger. Returning an error
+// aborts the change.
+type StockChange func(level *domain.StockLevel) (*domain.StockMovement, error)
+
+type InventoryRepository interface {
+	GetStock(ctx context.Context, productID uint, warehouse string) (*domain.StockLevel, error)
+	GetProductStock(ctx context.Context, productID uint) ([]domain.StockLevel, error)
+	GetLowStock(ctx context.Context) ([]domain.StockLevel, error)
+	GetMovements(ctx context.Context, productID uint, limit int) ([]domain.StockMovement, error)
+	// Change applies change to the stock level of a product in a warehouse,
+	// creating an empty level first if needed. The level is locked for the
+	// duration of the call, so concurrent changes are serialized.
+	Change(ctx context.Context, productID uint, warehouse string, change StockChange) (*domain.StockLevel, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type OrderRepository interface {
+	// Create stores an order with its items and history.
+	Create(ctx context.Context, order *domain.Order) error
+	GetByID(ctx context.Context, id uint) (*domain.Order, error)
+	// GetByUser returns the orders of a user, newest first.
+	GetByUser(ctx context.Context, userID uint) ([]domain.Order, error)
+	// GetAll returns the orders in status, or every order when status is
+	// empty, newest first.
+	GetAll(ctx context.Context, status domain.OrderStatus) ([]domain.Order, error)
+	// UpdateStatus moves an order from event.From to event.To and records
+	// event. It returns false, changing nothing, when the order is no longer
+	// in event.From.
+	UpdateStatus(ctx context.Context, event *domain.OrderEvent) (bool, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *domain.Payment) error
+	GetByIntent(ctx context.Context, intentID string) (*domain.Payment, error)
+	// GetByOrder returns the payments of an order, newest first.
+	GetByOrder(ctx context.Context, orderID uint) ([]domain.Payment, error)
+	// UpdateStatus moves a payment from one status to another. It returns
+	// false, changing nothing, when the payment is no longer in from.
+	UpdateStatus(ctx context.Context, id uint, from, to domain.PaymentStatus) (bool, error)
+	// GetStale returns the payments in one of statuses not updated since before.
+	GetStale(ctx context.Context, statuses []domain.PaymentStatus, before time.Time) ([]domain.Payment, error)
+	HasEvent(ctx context.Context, id string) (bool, error)
+	RecordEvent(ctx context.Context, event *domain.PaymentEvent) error
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type PriceRepository interface {
+	RecordChange(ctx context.Context, change *domain.PriceChange) error
+	// GetHistory returns the most recent price changes of a product, newest first.
+	GetHistory(ctx context.Context, productID uint, limit int) ([]domain.PriceChange, error)
+	CreateSchedule(ctx context.Context, schedule *domain.PriceSchedule) error
+	GetSchedule(ctx context.Context, id uint) (*domain.PriceSchedule, error)
+	// GetSchedules returns the schedules of a product ordered by start time.
+	GetSchedules(ctx context.Context, productID uint) ([]domain.PriceSchedule, error)
+	// GetDueSchedules returns scheduled entries that have started and active
+	// entries that have ended by now, ordered by start time.
+	GetDueSchedules(ctx context.Context, now time.Time) ([]domain.PriceSchedule, error)
+	// LockSchedule returns a schedule, or nil when it does not exist, and
+	// locks it until the unit of work it runs in ends.
+	LockSchedule(ctx context.Context, id uint) (*domain.PriceSchedule, error)
+	// ClaimDueSchedule locks a schedule until the unit of work it runs in ends
+	// and returns it when it is still due by now. It returns nil when the
+	// schedule is no longer due or another transaction holds it, so
+	// schedulers running side by side apply each schedule once.
+	ClaimDueSchedule(ctx context.Context, id uint, now time.Time) (*domain.PriceSchedule, error)
+	UpdateSchedule(ctx context.Context, schedule *domain.PriceSchedule) error
+}
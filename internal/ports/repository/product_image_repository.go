@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type ProductImageRepository interface {
+	Create(ctx context.Context, image *domain.ProductImage) error
+	GetByID(ctx context.Context, id uint) (*domain.ProductImage, error)
+	// GetByProduct returns the images of a product ordered by position.
+	GetByProduct(ctx context.Context, productID uint) ([]domain.ProductImage, error)
+	GetByProducts(ctx context.Context, productIDs []uint) (map[uint][]domain.ProductImage, error)
+	// UpdateAll saves the position and primary flag of every given image at once.
+	UpdateAll(ctx context.Context, images []domain.ProductImage) error
+	Delete(ctx context.Context, id uint) error
+	// DeleteByProduct removes every image of a product.
+	DeleteByProduct(ctx context.Context, productID uint) error
+}
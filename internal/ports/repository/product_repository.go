@@ -1,11 +1,30 @@
 package repository
 
-import "github.com/euro1061/gohex/internal/domain"
+import (
+	"context"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
 
 type ProductRepository interface {
-    Create(product *domain.Product) error
-    GetByID(id uint) (*domain.Product, error)
-    GetAll() ([]domain.Product, error)
-    Update(product *domain.Product) error
-    Delete(id uint) error
+    Create(ctx context.Context, product *domain.Product) error
+    GetByID(ctx context.Context, id uint) (*domain.Product, error)
+    GetBySKU(ctx context.Context, sku string) (*domain.Product, error)
+    GetBySlug(ctx context.Context, slug string) (*domain.Product, error)
+    GetVariantBySKU(ctx context.Context, sku string) (*domain.ProductVariant, error)
+    GetAll(ctx context.Context) ([]domain.Product, error)
+    GetByIDs(ctx context.Context, ids []uint) ([]domain.Product, error)
+    Find(ctx context.Context, filter domain.ProductFilter) ([]domain.Product, error)
+    // FindAfter returns up to limit products matching filter with an ID
+    // greater than afterID, ordered by ID, so callers can page through a
+    // large catalog with a cursor.
+    FindAfter(ctx context.Context, filter domain.ProductFilter, afterID uint, limit int) ([]domain.Product, error)
+    Update(ctx context.Context, product *domain.Product) error
+    Delete(ctx context.Context, id uint) error
+    // CreateRevision stores revision as the next revision of its product,
+    // setting its Number.
+    CreateRevision(ctx context.Context, revision *domain.ProductRevision) error
+    GetRevision(ctx context.Context, productID uint, number int) (*domain.ProductRevision, error)
+    // GetRevisions returns the most recent revisions of a product, newest first.
+    GetRevisions(ctx context.Context, productID uint, limit int) ([]domain.ProductRevision, error)
 }
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type PromotionRepository interface {
+	// Create returns ErrDuplicateKey when the coupon code is taken.
+	Create(ctx context.Context, promotion *domain.Promotion) error
+	GetByID(ctx context.Context, id uint) (*domain.Promotion, error)
+	GetByCode(ctx context.Context, code string) (*domain.Promotion, error)
+	GetAll(ctx context.Context) ([]domain.Promotion, error)
+	// GetAutomatic returns the promotions without a coupon code that are in
+	// effect at now.
+	GetAutomatic(ctx context.Context, now time.Time) ([]domain.Promotion, error)
+	// Update returns ErrDuplicateKey when the coupon code is taken. It does
+	// not change the usage count, which only Redeem does.
+	Update(ctx context.Context, promotion *domain.Promotion) error
+	Delete(ctx context.Context, id uint) error
+	// CountRedemptions counts the times a user redeemed a promotion.
+	CountRedemptions(ctx context.Context, promotionID, userID uint) (int, error)
+	// Redeem records a redemption and increments the usage count of its
+	// promotion. It reports false, recording nothing, when the promotion
+	// already reached its global limit or the user their per user limit.
+	Redeem(ctx context.Context, redemption *domain.PromotionRedemption) (bool, error)
+	// Unredeem removes the redemptions recorded with reference and gives
+	// their uses back to the promotions.
+	Unredeem(ctx context.Context, reference string) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type ReviewRepository interface {
+	// Create returns ErrDuplicateKey when the user already reviewed the product.
+	Create(ctx context.Context, review *domain.Review) error
+	GetByID(ctx context.Context, id uint) (*domain.Review, error)
+	// GetByProduct returns the reviews of a product, newest first. An empty
+	// status returns reviews in every status.
+	GetByProduct(ctx context.Context, productID uint, status domain.ReviewStatus) ([]domain.Review, error)
+	Update(ctx context.Context, review *domain.Review) error
+	Delete(ctx context.Context, id uint) error
+	// GetRatingCounts counts the approved reviews of each product per star
+	// rating. Products without approved reviews are left out.
+	GetRatingCounts(ctx context.Context, productIDs []uint) (map[uint]map[int]int, error)
+}
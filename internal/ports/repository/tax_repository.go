@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type TaxRateRepository interface {
+	// Create returns ErrDuplicateKey when the jurisdiction already has a
+	// rate for the tax class.
+	Create(ctx context.Context, rate *domain.TaxRate) error
+	GetByID(ctx context.Context, id uint) (*domain.TaxRate, error)
+	GetAll(ctx context.Context) ([]domain.TaxRate, error)
+	// GetByCountry returns the rates of a country, for every region.
+	GetByCountry(ctx context.Context, country string) ([]domain.TaxRate, error)
+	Update(ctx context.Context, rate *domain.TaxRate) error
+	Delete(ctx context.Context, id uint) error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import "context"
+
+// Repositories are the repositories of a unit of work, bound to its
+// transaction.
+type Repositories struct {
+	Products   ProductRepository
+	Users      UserRepository
+	Categories CategoryRepository
+	Inventory  InventoryRepository
+	Images     ProductImageRepository
+	Prices     PriceRepository
+	Reviews    ReviewRepository
+	Promotions PromotionRepository
+	Carts      CartRepository
+	Orders     OrderRepository
+	Payments   PaymentRepository
+	TaxRates   TaxRateRepository
+	Wishlists  WishlistRepository
+	// UnitOfWork runs nested transactions. A nested transaction that fails
+	// rolls back its own writes only.
+	UnitOfWork UnitOfWork
+}
+
+// UnitOfWork runs functions whose writes to several repositories must
+// succeed or fail together.
+type UnitOfWork interface {
+	// Transaction runs fn with repositories bound to a new transaction. The
+	// transaction is committed when fn returns nil and rolled back otherwise.
+	Transaction(ctx context.Context, fn func(repos Repositories) error) error
+}
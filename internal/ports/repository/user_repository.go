@@ -1,11 +1,15 @@
 package repository
 
-import "github.com/euro1061/gohex/internal/domain"
+import (
+	"context"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
 
 type UserRepository interface {
-	Create(user *domain.User) error
-	GetByID(id uint) (*domain.User, error)
-	GetByUsername(username string) (*domain.User, error)
-	GetByEmail(email string) (*domain.User, error)
-	Update(user *domain.User) error
+	Create(ctx context.Context, user *domain.User) error
+	GetByID(ctx context.Context, id uint) (*domain.User, error)
+	GetByUsername(ctx context.Context, username string) (*domain.User, error)
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	Update(ctx context.Context, user *domain.User) error
 }
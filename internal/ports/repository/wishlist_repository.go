@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+type WishlistRepository interface {
+	Create(ctx context.Context, wishlist *domain.Wishlist) error
+	// GetByID and GetByShareToken return the wishlist with its items,
+	// oldest first.
+	GetByID(ctx context.Context, id uint) (*domain.Wishlist, error)
+	GetByShareToken(ctx context.Context, token string) (*domain.Wishlist, error)
+	// GetByUser returns the wishlists of a user with their items, oldest
+	// first.
+	GetByUser(ctx context.Context, userID uint) ([]domain.Wishlist, error)
+	CountByUser(ctx context.Context, userID uint) (int64, error)
+	// Update stores the name, visibility and share token of a wishlist.
+	Update(ctx context.Context, wishlist *domain.Wishlist) error
+	// Delete deletes a wishlist and its items.
+	Delete(ctx context.Context, id uint) error
+	// AddItem returns ErrDuplicateKey when the product is already in the
+	// wishlist.
+	AddItem(ctx context.Context, item *domain.WishlistItem) error
+	// RemoveItem reports whether the product was in the wishlist.
+	RemoveItem(ctx context.Context, wishlistID, productID uint) (bool, error)
+	// GetSavedProductIDs returns which of productIDs are in a wishlist of
+	// the user.
+	GetSavedProductIDs(ctx context.Context, userID uint, productIDs []uint) ([]uint, error)
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrBlobNotFound is returned when a key does not exist in the store.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore stores binary objects such as product images under string keys.
+// Keys use forward slashes, e.g. "products/1/abc.jpg".
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL a client can fetch the object from.
+	URL(key string) string
+}
@@ -0,0 +1,15 @@
+package tax
+
+import (
+	"context"
+
+	"github.com/euro1061/gohex/internal/domain"
+)
+
+// TaxCalculator computes the tax on amounts sold to an address. The built-in
+// calculator uses the configured rate table; a remote tax service can take
+// its place by implementing this interface.
+type TaxCalculator interface {
+	// Calculate returns the tax on lines, one LineTax per line in order.
+	Calculate(ctx context.Context, address domain.TaxAddress, lines []domain.TaxableLine) (*domain.TaxQuote, error)
+}